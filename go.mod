@@ -4,10 +4,8 @@ go 1.23
 
 require (
 	github.com/coder/websocket v1.8.14
+	github.com/klauspost/compress v1.18.4
 	github.com/valkey-io/valkey-go v1.0.47
 )
 
-require (
-	github.com/klauspost/compress v1.18.4 // indirect
-	golang.org/x/sys v0.24.0 // indirect
-)
+require golang.org/x/sys v0.24.0 // indirect
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSlowLogArgLen truncates slow log arguments so huge values don't bloat the response
+const maxSlowLogArgLen = 128
+
+// truncateArg shortens s to max bytes, noting how much was cut
+func truncateArg(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "... (" + strconv.Itoa(len(s)-max) + " more bytes)"
+}
+
+func (h *Handler) handleSlowLog(w http.ResponseWriter, r *http.Request) {
+	// Slow log arguments hold key names and values from the whole server, which
+	// --prefix and key patterns must not leak; key positions can't be told
+	// apart reliably for every command (EVAL, MSET, ...), so refuse outright
+	if h.cfg.KeyScoped() {
+		jsonError(w, "Slow log is unavailable when --prefix or key patterns are set", http.StatusForbidden)
+		return
+	}
+
+	count := int64(50)
+	if s := r.URL.Query().Get("count"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < 1 || n > 1000 {
+			jsonError(w, "count must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+		count = n
+	}
+
+	entries, err := h.client.SlowLogGet(r.Context(), count)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	for i := range entries {
+		for j, arg := range entries[i].Args {
+			entries[i].Args[j] = truncateArg(arg, maxSlowLogArgLen)
+		}
+	}
+
+	jsonResponse(w, map[string]any{"entries": entries})
+}
+
+func (h *Handler) handleSlowLogReset(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	if err := h.client.SlowLogReset(r.Context()); err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]string{"status": "ok"})
+}
+
+func (h *Handler) handleClientList(w http.ResponseWriter, r *http.Request) {
+	clients, err := h.client.ClientList(r.Context())
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{"clients": clients})
+}
+
+func (h *Handler) handleClientKill(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+	if !h.cfg.AllowClientKill {
+		jsonError(w, "Killing clients is disabled (start with --allow-client-kill)", http.StatusForbidden)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		jsonError(w, "Invalid client id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.client.ClientKill(r.Context(), id); err != nil {
+		if strings.Contains(err.Error(), "No such client") {
+			jsonError(w, "Client not found", http.StatusNotFound)
+			return
+		}
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]string{"status": "ok"})
+}
+
+// maxWaitTimeout bounds how long a replication WAIT may block a request
+const maxWaitTimeout = 5 * time.Second
+
+// handleReplication reports role, replicas, and offsets. With wait=1 on a primary it
+// also issues WAIT for all connected replicas (timeout in ms, default 1000). kvweb's
+// writes are spread over pooled connections, so this is a best-effort health probe
+// rather than a guarantee about a specific write.
+func (h *Handler) handleReplication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	info, err := h.client.Replication(ctx)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	resp := map[string]any{"replication": info}
+
+	if r.URL.Query().Get("wait") == "1" && info.Role == "master" && info.ConnectedReplicas > 0 {
+		timeout := time.Second
+		if s := r.URL.Query().Get("timeout"); s != "" {
+			ms, err := strconv.ParseInt(s, 10, 64)
+			if err != nil || ms < 1 || time.Duration(ms)*time.Millisecond > maxWaitTimeout {
+				jsonError(w, "timeout must be between 1 and 5000 ms", http.StatusBadRequest)
+				return
+			}
+			timeout = time.Duration(ms) * time.Millisecond
+		}
+
+		acked, err := h.client.Wait(ctx, info.ConnectedReplicas, timeout)
+		if err != nil {
+			errorResponse(w, err)
+			return
+		}
+		resp["acked"] = acked
+	}
+
+	jsonResponse(w, resp)
+}
+
+// handleKeyDebug shows DEBUG OBJECT details such as serialized length. DEBUG is often
+// disabled server-side, so that case is reported as 501 rather than a server error.
+func (h *Handler) handleKeyDebug(w http.ResponseWriter, r *http.Request) {
+	if !h.cfg.EnableDebug {
+		jsonError(w, "DEBUG is disabled (start with --enable-debug)", http.StatusForbidden)
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	fields, err := h.client.DebugObject(r.Context(), key)
+	if err != nil {
+		msg := err.Error()
+		switch {
+		case strings.Contains(msg, "no such key"):
+			jsonError(w, "Key not found", http.StatusNotFound)
+		case strings.Contains(msg, "DEBUG command not allowed"), strings.Contains(msg, "unknown command"), strings.Contains(msg, "NOPERM"):
+			jsonError(w, "DEBUG is not available on this server: "+msg, http.StatusNotImplemented)
+		default:
+			errorResponse(w, err)
+		}
+		return
+	}
+
+	resp := map[string]any{"key": key, "fields": fields}
+	if n, err := strconv.ParseInt(fields["serializedlength"], 10, 64); err == nil {
+		resp["serializedLength"] = n
+	}
+	resp["encoding"] = fields["encoding"]
+
+	jsonResponse(w, resp)
+}
+
+type channelInfo struct {
+	Channel     string `json:"channel"`
+	Subscribers int64  `json:"subscribers"`
+}
+
+// handlePubSubChannels lists active pub/sub channels (optionally matching pattern)
+// with subscriber counts
+func (h *Handler) handlePubSubChannels(w http.ResponseWriter, r *http.Request) {
+	counts, err := h.client.PubSubChannels(r.Context(), r.URL.Query().Get("pattern"))
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	channels := make([]channelInfo, 0, len(counts))
+	for name, n := range counts {
+		// Keyspace notification channels name keys, which --prefix and key patterns must not leak
+		if h.cfg.KeyScoped() && strings.HasPrefix(name, "__key") {
+			continue
+		}
+		channels = append(channels, channelInfo{Channel: name, Subscribers: n})
+	}
+	sort.Slice(channels, func(i, j int) bool { return channels[i].Channel < channels[j].Channel })
+
+	jsonResponse(w, map[string]any{"channels": channels})
+}
+
+// aclUserInfo is one ACL user as returned by /api/acl, with password hashes removed
+type aclUserInfo struct {
+	Name      string   `json:"name"`
+	Rules     string   `json:"rules"` // ACL LIST rules after the name
+	Flags     []string `json:"flags"`
+	Passwords int      `json:"passwords"` // how many passwords are set
+	Commands  string   `json:"commands"`
+	Keys      string   `json:"keys"`
+	Channels  string   `json:"channels"`
+}
+
+// redactACLRules hides password hashes (#<hash>) in an ACL rule string. ACL LIST
+// never shows cleartext (>pass) rules, but they're hidden too in case one slips through.
+func redactACLRules(rules string) string {
+	fields := strings.Fields(rules)
+	for i, f := range fields {
+		// #hash and !hash add and remove hashed passwords, >pass and <pass cleartext ones
+		if strings.HasPrefix(f, "#") || strings.HasPrefix(f, "!") || strings.HasPrefix(f, ">") || strings.HasPrefix(f, "<") {
+			fields[i] = f[:1] + "<redacted>"
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// handleACL lists ACL users with their rules and the user kvweb is connected as
+func (h *Handler) handleACL(w http.ResponseWriter, r *http.Request) {
+	if !h.cfg.EnableACL {
+		jsonError(w, "ACL inspection is disabled (start with --enable-acl)", http.StatusForbidden)
+		return
+	}
+
+	ctx := r.Context()
+	whoami, err := h.client.AclWhoAmI(ctx)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	lines, err := h.client.AclList(ctx)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	users := make([]aclUserInfo, 0, len(lines))
+	for _, line := range lines {
+		// "user <name> <rules...>"
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) < 2 || fields[0] != "user" {
+			continue
+		}
+		info := aclUserInfo{Name: fields[1], Flags: []string{}}
+		if len(fields) == 3 {
+			info.Rules = redactACLRules(fields[2])
+		}
+
+		// Deleted between LIST and GETUSER: keep what LIST said
+		user, err := h.client.AclGetUser(ctx, info.Name)
+		if err != nil {
+			errorResponse(w, err)
+			return
+		}
+		if user != nil {
+			info.Flags = user.Flags
+			info.Passwords = len(user.Passwords)
+			info.Commands = user.Commands
+			info.Keys = user.Keys
+			info.Channels = user.Channels
+		}
+		users = append(users, info)
+	}
+
+	jsonResponse(w, map[string]any{"whoami": whoami, "users": users})
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+func TestRedactACLRules(t *testing.T) {
+	tests := []struct {
+		rules string
+		want  string
+	}{
+		{"on nopass ~* &* +@all", "on nopass ~* &* +@all"},
+		{
+			"on #5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8 ~cache:* +get",
+			"on #<redacted> ~cache:* +get",
+		},
+		{"on >secret <old !abc ~*", "on ><redacted> <<redacted> !<redacted> ~*"},
+	}
+
+	for _, tt := range tests {
+		if got := redactACLRules(tt.rules); got != tt.want {
+			t.Errorf("redactACLRules(%q) = %q, want %q", tt.rules, got, tt.want)
+		}
+	}
+}
+
+func TestACLDisabled(t *testing.T) {
+	h := New(&config.Config{}, nil)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/acl", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestSlowLogKeyScoped(t *testing.T) {
+	configs := map[string]*config.Config{
+		"prefix":        {Prefix: "app:"},
+		"allow pattern": {AllowPatterns: []string{"app:*"}},
+		"deny pattern":  {DenyPatterns: []string{"secret:*"}},
+	}
+
+	for name, cfg := range configs {
+		t.Run(name, func(t *testing.T) {
+			h := New(cfg, nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/slowlog", nil))
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusForbidden)
+			}
+		})
+	}
+}
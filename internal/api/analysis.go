@@ -0,0 +1,221 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+// maxAnalysisKeys bounds how many keys a single analysis run examines
+const maxAnalysisKeys = 1_000_000
+
+// defaultTopKeys is the default number of entries in top-N analysis results
+const defaultTopKeys = 10
+
+// typeSummary aggregates key count and memory for one data type
+type typeSummary struct {
+	Count  int64 `json:"count"`
+	Memory int64 `json:"memory"`
+}
+
+// analysisPattern returns the prefixed SCAN pattern from the "pattern" query param
+func (h *Handler) analysisPattern(r *http.Request) string {
+	pattern := r.URL.Query().Get("pattern")
+	if pattern == "" {
+		pattern = "*"
+	}
+	return h.applyPrefixToPattern(pattern)
+}
+
+// analysisLimit returns the key limit from the "sample" query param, or
+// maxAnalysisKeys when sampling isn't requested. ok is false on invalid input.
+func analysisLimit(r *http.Request) (limit int64, sampled bool, ok bool) {
+	s := r.URL.Query().Get("sample")
+	if s == "" {
+		return maxAnalysisKeys, false, true
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 1 || n > maxAnalysisKeys {
+		return 0, false, false
+	}
+	return n, true, true
+}
+
+// keepTop sorts stats by less and truncates to n entries
+func keepTop(stats []valkey.KeyStat, n int, less func(a, b valkey.KeyStat) bool) []valkey.KeyStat {
+	sort.Slice(stats, func(i, j int) bool { return less(stats[i], stats[j]) })
+	if len(stats) > n {
+		stats = stats[:n]
+	}
+	return stats
+}
+
+// handleBigKeys scans the keyspace and reports the largest keys by memory and by
+// element count, plus a per-type memory summary. Progress is reported per batch.
+func (h *Handler) handleBigKeys(w http.ResponseWriter, r *http.Request) {
+	top := defaultTopKeys
+	if s := r.URL.Query().Get("top"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > 100 {
+			jsonError(w, "top must be between 1 and 100", http.StatusBadRequest)
+			return
+		}
+		top = n
+	}
+
+	ctx := r.Context()
+	total, _ := h.client.DBSize(ctx)
+
+	var bySize, byLength []valkey.KeyStat
+	types := make(map[string]*typeSummary)
+	var scanned int64
+
+	err := h.scanBatches(ctx, h.analysisPattern(r), maxAnalysisKeys, func(keys []string) error {
+		stats := h.client.KeyStats(ctx, keys, true)
+		for _, s := range stats {
+			t := types[s.Type]
+			if t == nil {
+				t = &typeSummary{}
+				types[s.Type] = t
+			}
+			t.Count++
+			t.Memory += s.Memory
+		}
+
+		bySize = keepTop(append(bySize, stats...), top, func(a, b valkey.KeyStat) bool {
+			return a.Memory > b.Memory
+		})
+		byLength = keepTop(append(byLength, stats...), top, func(a, b valkey.KeyStat) bool {
+			return a.Length > b.Length
+		})
+
+		scanned += int64(len(keys))
+		h.reportProgress("bigkeys", scanned, total, false)
+		return ctx.Err()
+	})
+	h.reportProgress("bigkeys", scanned, total, true)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{
+		"bySize":   nonNil(bySize),
+		"byLength": nonNil(byLength),
+		"types":    types,
+		"scanned":  scanned,
+	})
+}
+
+// nonNil returns an empty slice instead of nil so JSON encodes [] rather than null
+func nonNil(stats []valkey.KeyStat) []valkey.KeyStat {
+	if stats == nil {
+		return []valkey.KeyStat{}
+	}
+	return stats
+}
+
+// handleTypeDistribution counts keys and memory per data type. With sample=N only the
+// first N keys SCAN returns are examined; SCAN walks the hash table, so this is an
+// unbiased-enough approximation and dbSize can be used to scale the counts.
+func (h *Handler) handleTypeDistribution(w http.ResponseWriter, r *http.Request) {
+	limit, sampled, ok := analysisLimit(r)
+	if !ok {
+		jsonError(w, "Invalid sample size", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	dbSize, _ := h.client.DBSize(ctx)
+
+	types := make(map[string]*typeSummary)
+	var scanned int64
+
+	err := h.scanBatches(ctx, h.analysisPattern(r), limit, func(keys []string) error {
+		for _, s := range h.client.KeyStats(ctx, keys, true) {
+			t := types[s.Type]
+			if t == nil {
+				t = &typeSummary{}
+				types[s.Type] = t
+			}
+			t.Count++
+			t.Memory += s.Memory
+		}
+		scanned += int64(len(keys))
+		h.reportProgress("types", scanned, dbSize, false)
+		return ctx.Err()
+	})
+	h.reportProgress("types", scanned, dbSize, true)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{
+		"types":   types,
+		"scanned": scanned,
+		"sampled": sampled,
+		"dbSize":  dbSize,
+	})
+}
+
+// ttlBuckets lists TTL bucket names in display order
+var ttlBuckets = []string{"noExpiry", "lt1m", "lt1h", "lt1d", "gt1d"}
+
+// ttlBucket returns the bucket name for a TTL in seconds (-1 = no expiry)
+func ttlBucket(ttl int64) string {
+	switch {
+	case ttl < 0:
+		return "noExpiry"
+	case ttl < 60:
+		return "lt1m"
+	case ttl < 3600:
+		return "lt1h"
+	case ttl < 86400:
+		return "lt1d"
+	default:
+		return "gt1d"
+	}
+}
+
+// handleTTLDistribution buckets keys by remaining TTL so keys that never expire stand out.
+// Supports sample=N like handleTypeDistribution.
+func (h *Handler) handleTTLDistribution(w http.ResponseWriter, r *http.Request) {
+	limit, sampled, ok := analysisLimit(r)
+	if !ok {
+		jsonError(w, "Invalid sample size", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	dbSize, _ := h.client.DBSize(ctx)
+
+	buckets := make(map[string]int64, len(ttlBuckets))
+	for _, b := range ttlBuckets {
+		buckets[b] = 0
+	}
+	var scanned int64
+
+	err := h.scanBatches(ctx, h.analysisPattern(r), limit, func(keys []string) error {
+		for _, ttl := range h.client.TTLBatch(ctx, keys) {
+			buckets[ttlBucket(ttl)]++
+		}
+		scanned += int64(len(keys))
+		h.reportProgress("ttl", scanned, dbSize, false)
+		return ctx.Err()
+	})
+	h.reportProgress("ttl", scanned, dbSize, true)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{
+		"buckets": buckets,
+		"scanned": scanned,
+		"sampled": sampled,
+		"dbSize":  dbSize,
+	})
+}
@@ -0,0 +1,25 @@
+package api
+
+import "testing"
+
+func TestTTLBucket(t *testing.T) {
+	tests := []struct {
+		ttl  int64
+		want string
+	}{
+		{-1, "noExpiry"},
+		{0, "lt1m"},
+		{59, "lt1m"},
+		{60, "lt1h"},
+		{3599, "lt1h"},
+		{3600, "lt1d"},
+		{86399, "lt1d"},
+		{86400, "gt1d"},
+	}
+
+	for _, tt := range tests {
+		if got := ttlBucket(tt.ttl); got != tt.want {
+			t.Errorf("ttlBucket(%d) = %q, want %q", tt.ttl, got, tt.want)
+		}
+	}
+}
@@ -1,11 +1,16 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"net/http"
 	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -23,73 +28,143 @@ type Handler struct {
 	cfg                     *config.Config
 	client                  *valkey.Client
 	mux                     *http.ServeMux
-	onNotificationsEnabled  func() // Callback when notifications are enabled at runtime
-	onNotificationsDisabled func() // Callback when notifications are disabled at runtime
+	onNotificationsEnabled  func()        // Callback when notifications are enabled at runtime
+	onNotificationsDisabled func()        // Callback when notifications are disabled at runtime
+	scanSem                 chan struct{} // Limits concurrent scan-based requests (nil = unlimited)
+	onProgress              ProgressFunc  // Reports progress of long-running scans (nil = disabled)
+	audit                   *auditLog     // Records successful writes (nil = disabled)
+	history                 *history      // Recent writes available for undo
+	metaCache               *metaCache    // Recent key type/TTL lookups (nil = disabled)
+	started                 time.Time     // Reported as uptime by the health check
 }
 
 // New creates a new API handler
 func New(cfg *config.Config, client *valkey.Client) *Handler {
 	h := &Handler{
-		cfg:    cfg,
-		client: client,
-		mux:    http.NewServeMux(),
+		cfg:     cfg,
+		client:  client,
+		mux:     http.NewServeMux(),
+		history: &history{},
+		started: time.Now(),
+	}
+
+	if cfg.MaxConcurrentScans > 0 {
+		h.scanSem = make(chan struct{}, cfg.MaxConcurrentScans)
+	}
+	if cfg.MetaCacheTTL > 0 {
+		h.metaCache = newMetaCache(time.Duration(cfg.MetaCacheTTL) * time.Second)
 	}
 
 	// Register routes
 	h.mux.HandleFunc("GET /api/health", h.handleHealth)
+	h.mux.HandleFunc("GET /api/ready", h.handleReady)
 	h.mux.HandleFunc("GET /api/config", h.handleConfig)
+	h.mux.HandleFunc("GET /api/version", h.handleVersion)
 	h.mux.HandleFunc("GET /api/info", h.handleInfo)
-	h.mux.HandleFunc("GET /api/keys", h.handleKeys)
-	h.mux.HandleFunc("GET /api/prefixes", h.handlePrefixes)
+	h.mux.HandleFunc("GET /api/config/memory", h.handleGetMemoryConfig)
+	h.mux.HandleFunc("POST /api/config/memory", h.handleSetMemoryConfig)
+	h.mux.HandleFunc("GET /api/config/server", h.handleGetServerConfig)
+	h.mux.HandleFunc("POST /api/config/server", h.handleSetServerConfig)
+	h.mux.HandleFunc("GET /api/slowlog", h.handleSlowLog)
+	h.mux.HandleFunc("POST /api/slowlog/reset", h.handleSlowLogReset)
+	h.mux.HandleFunc("GET /api/clients", h.handleClientList)
+	h.mux.HandleFunc("GET /api/replication", h.handleReplication)
+	h.mux.HandleFunc("GET /api/pubsub/channels", h.handlePubSubChannels)
+	h.mux.HandleFunc("POST /api/clients/{id}/kill", h.requireConfirm(h.handleClientKill))
+	h.mux.HandleFunc("GET /api/keys", h.limitScan(h.handleKeys))
+	h.mux.HandleFunc("GET /api/keys/sample", h.limitScan(h.handleSampleKeys))
+	h.mux.HandleFunc("GET /api/prefixes", h.limitScan(h.handlePrefixes))
 	h.mux.HandleFunc("GET /api/key/{key}", h.handleGetKey)
-	h.mux.HandleFunc("PUT /api/key/{key}", h.handleSetKey)
-	h.mux.HandleFunc("DELETE /api/key/{key}", h.handleDeleteKey)
-	h.mux.HandleFunc("POST /api/key/{key}/incr", h.handleIncrKey)
-	h.mux.HandleFunc("POST /api/key/{key}/expire", h.handleExpire)
-	h.mux.HandleFunc("POST /api/key/{key}/rename", h.handleRename)
-	h.mux.HandleFunc("POST /api/keys/delete", h.handleDeleteKeys)
+	h.mux.HandleFunc("PUT /api/key/{key}", h.writableKey(h.writableAs("string", h.handleSetKey)))
+	h.mux.HandleFunc("DELETE /api/key/{key}", h.requireConfirm(h.writableKey(h.handleDeleteKey)))
+	h.mux.HandleFunc("POST /api/key/{key}/incr", h.writableAs("string", h.handleIncrKey))
+	h.mux.HandleFunc("GET /api/key/{key}/type", h.handleKeyType)
+	h.mux.HandleFunc("GET /api/key/{key}/object", h.handleKeyObject)
+	h.mux.HandleFunc("GET /api/key/{key}/debug", h.handleKeyDebug)
+	h.mux.HandleFunc("GET /api/acl", h.handleACL)
+	h.mux.HandleFunc("POST /api/key/{key}/append", h.writableAs("string", h.handleAppend))
+	h.mux.HandleFunc("PATCH /api/key/{key}/range", h.writableAs("string", h.handleSetRange))
+	h.mux.HandleFunc("POST /api/key/{key}/expire", h.writableKey(h.handleExpire))
+	h.mux.HandleFunc("GET /api/key/{key}/dump", h.handleDump)
+	h.mux.HandleFunc("POST /api/key/{key}/restore", h.writableAll(h.handleRestore))
+	h.mux.HandleFunc("POST /api/key/{key}/migrate", h.writableKey(h.handleMigrate))
+	h.mux.HandleFunc("POST /api/key/{key}/rename", h.writableKey(h.handleRename))
+	h.mux.HandleFunc("POST /api/keys/delete", h.requireConfirm(h.handleDeleteKeys))
 	h.mux.HandleFunc("POST /api/keys/memory", h.handleKeysMemory)
-	h.mux.HandleFunc("POST /api/flush", h.handleFlush)
+	h.mux.HandleFunc("POST /api/keys/exists", h.handleKeysExists)
+	h.mux.HandleFunc("POST /api/flush", h.requireConfirm(h.writableAll(h.handleFlush)))
+	h.mux.HandleFunc("POST /api/import", h.limitScan(h.handleImport))
+	h.mux.HandleFunc("POST /api/check-references", h.limitScan(h.handleCheckReferences))
+	h.mux.HandleFunc("GET /api/analysis/bigkeys", h.limitScan(h.handleBigKeys))
+	h.mux.HandleFunc("GET /api/analysis/types", h.limitScan(h.handleTypeDistribution))
+	h.mux.HandleFunc("GET /api/analysis/ttl", h.limitScan(h.handleTTLDistribution))
+	h.mux.HandleFunc("GET /api/trash", h.limitScan(h.handleTrashList))
+	h.mux.HandleFunc("POST /api/trash/{key}/restore", h.writableAll(h.handleTrashRestore))
+	h.mux.HandleFunc("GET /api/history", h.handleHistory)
+	h.mux.HandleFunc("POST /api/history/{id}/undo", h.handleHistoryUndo)
 	h.mux.HandleFunc("GET /api/notifications", h.handleGetNotifications)
 	h.mux.HandleFunc("POST /api/notifications", h.handleSetNotifications)
 
 	// Complex type CRUD endpoints
 	// List operations
-	h.mux.HandleFunc("POST /api/key/{key}/list", h.handleListAdd)
-	h.mux.HandleFunc("PUT /api/key/{key}/list/{index}", h.handleListSet)
-	h.mux.HandleFunc("DELETE /api/key/{key}/list/{index}", h.handleListRemove)
+	h.mux.HandleFunc("POST /api/key/{key}/list", h.writableAs("list", h.handleListAdd))
+	h.mux.HandleFunc("POST /api/key/{key}/list/insert", h.writableAs("list", h.handleListInsert))
+	h.mux.HandleFunc("POST /api/key/{key}/list/trim", h.writableAs("list", h.handleListTrim))
+	h.mux.HandleFunc("PUT /api/key/{key}/list/{index}", h.writableAs("list", h.handleListSet))
+	h.mux.HandleFunc("DELETE /api/key/{key}/list/{index}", h.writableAs("list", h.handleListRemove))
 
 	// Set operations
-	h.mux.HandleFunc("POST /api/key/{key}/set", h.handleSetAdd)
-	h.mux.HandleFunc("DELETE /api/key/{key}/set/{member}", h.handleSetRemove)
-	h.mux.HandleFunc("PATCH /api/key/{key}/set/{member}", h.handleSetRename)
+	h.mux.HandleFunc("POST /api/key/{key}/set", h.writableAs("set", h.handleSetAdd))
+	h.mux.HandleFunc("DELETE /api/key/{key}/set/{member}", h.writableAs("set", h.handleSetRemove))
+	h.mux.HandleFunc("PATCH /api/key/{key}/set/{member}", h.writableAs("set", h.handleSetRename))
+	h.mux.HandleFunc("POST /api/sets/op", h.handleSetOp)
 
 	// Hash operations
-	h.mux.HandleFunc("POST /api/key/{key}/hash", h.handleHashSet)
-	h.mux.HandleFunc("DELETE /api/key/{key}/hash/{field}", h.handleHashRemove)
-	h.mux.HandleFunc("PATCH /api/key/{key}/hash/{field}", h.handleHashRename)
+	h.mux.HandleFunc("POST /api/key/{key}/hash", h.writableAs("hash", h.handleHashSet))
+	h.mux.HandleFunc("DELETE /api/key/{key}/hash/{field}", h.writableAs("hash", h.handleHashRemove))
+	h.mux.HandleFunc("PATCH /api/key/{key}/hash/{field}", h.writableAs("hash", h.handleHashRename))
 
 	// ZSet operations
-	h.mux.HandleFunc("POST /api/key/{key}/zset", h.handleZSetAdd)
-	h.mux.HandleFunc("DELETE /api/key/{key}/zset/{member}", h.handleZSetRemove)
-	h.mux.HandleFunc("PATCH /api/key/{key}/zset/{member}", h.handleZSetRename)
-	h.mux.HandleFunc("POST /api/key/{key}/zset/{member}/incr", h.handleZSetIncrScore)
+	h.mux.HandleFunc("POST /api/key/{key}/zset", h.writableAs("zset", h.handleZSetAdd))
+	h.mux.HandleFunc("GET /api/key/{key}/zset/{member}", h.handleZSetLocate)
+	h.mux.HandleFunc("DELETE /api/key/{key}/zset/{member}", h.writableAs("zset", h.handleZSetRemove))
+	h.mux.HandleFunc("PATCH /api/key/{key}/zset/{member}", h.writableAs("zset", h.handleZSetRename))
+	h.mux.HandleFunc("POST /api/key/{key}/zset/{member}/incr", h.writableAs("zset", h.handleZSetIncrScore))
 
 	// Geo operations (uses zset internally, provides coordinate view)
 	h.mux.HandleFunc("GET /api/key/{key}/geo", h.handleGeoGet)
-	h.mux.HandleFunc("POST /api/key/{key}/geo", h.handleGeoAdd)
+	h.mux.HandleFunc("POST /api/key/{key}/geo", h.writableAs("zset", h.handleGeoAdd))
+	h.mux.HandleFunc("GET /api/key/{key}/geo/search", h.handleGeoSearch)
+	h.mux.HandleFunc("GET /api/key/{key}/geo/hash", h.handleGeoHash)
 	// DELETE uses handleZSetRemove - same underlying operation
 
 	// Stream operations
-	h.mux.HandleFunc("POST /api/key/{key}/stream", h.handleStreamAdd)
-	h.mux.HandleFunc("DELETE /api/key/{key}/stream/{id}", h.handleStreamRemove)
+	h.mux.HandleFunc("POST /api/key/{key}/stream", h.writableAs("stream", h.handleStreamAdd))
+	h.mux.HandleFunc("DELETE /api/key/{key}/stream/{id}", h.writableAs("stream", h.handleStreamRemove))
+	h.mux.HandleFunc("POST /api/key/{key}/stream/trim", h.writableAs("stream", h.handleStreamTrim))
+	h.mux.HandleFunc("GET /api/key/{key}/stream/groups", h.handleStreamGroups)
+	h.mux.HandleFunc("POST /api/key/{key}/stream/groups", h.writableAs("stream", h.handleStreamGroupCreate))
+	h.mux.HandleFunc("DELETE /api/key/{key}/stream/groups/{group}", h.requireConfirm(h.writableAs("stream", h.handleStreamGroupDestroy)))
+	h.mux.HandleFunc("POST /api/key/{key}/stream/groups/{group}/ack", h.writableAs("stream", h.handleStreamAck))
 
 	// HyperLogLog operations
-	h.mux.HandleFunc("POST /api/key/{key}/hll", h.handleHLLAdd)
+	h.mux.HandleFunc("POST /api/key/{key}/hll", h.writableAs("string", h.handleHLLAdd))
+	h.mux.HandleFunc("POST /api/key/{key}/hll/merge", h.writableAs("string", h.handleHLLMerge))
+
+	// Bitmap operations (strings viewed bit by bit)
+	h.mux.HandleFunc("GET /api/key/{key}/bitmap", h.handleBitmapGet)
+	h.mux.HandleFunc("POST /api/key/{key}/bitmap", h.writableAs("string", h.handleBitmapSet))
+	h.mux.HandleFunc("POST /api/key/{key}/bitfield", h.handleBitField)
+
+	// Atomic multi-key writes
+	h.mux.HandleFunc("POST /api/transaction", h.handleTransaction)
 
 	// Console
 	h.mux.HandleFunc("POST /api/exec", h.handleExec)
 
+	// Anything else under /api/ gets a JSON error rather than the mux's plain text
+	h.mux.HandleFunc("/api/", h.handleNotFound)
+
 	return h
 }
 
@@ -103,12 +178,28 @@ func (h *Handler) SetOnNotificationsDisabled(fn func()) {
 	h.onNotificationsDisabled = fn
 }
 
+// ProgressFunc receives progress updates from long-running scans such as keyspace analysis.
+// total is the database size and may be 0 if unknown; done is set on the final update.
+type ProgressFunc func(op string, scanned, total int64, done bool)
+
+// SetOnProgress sets the callback for long-running scan progress
+func (h *Handler) SetOnProgress(fn ProgressFunc) {
+	h.onProgress = fn
+}
+
+// reportProgress forwards scan progress to the progress callback, if any
+func (h *Handler) reportProgress(op string, scanned, total int64, done bool) {
+	if h.onProgress != nil {
+		h.onProgress(op, scanned, total, done)
+	}
+}
+
 // ServeHTTP implements http.Handler
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if h.cfg.CORSOrigin != "" {
 		w.Header().Set("Access-Control-Allow-Origin", h.cfg.CORSOrigin)
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, If-Match, "+confirmHeader)
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -118,9 +209,25 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Limit request body size to prevent memory exhaustion
 	if r.Body != nil {
-		r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+		limit := int64(maxBodySize)
+		if r.URL.Path == "/api/import" {
+			limit = maxImportSize
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+	}
+
+	// Bound the Valkey calls a request makes so slow scans fail fast instead of
+	// piling up; import streams a large body and is exempt
+	if h.cfg.OpTimeout > 0 && r.URL.Path != "/api/import" {
+		ctx, cancel := context.WithTimeout(r.Context(), time.Duration(h.cfg.OpTimeout)*time.Second)
+		defer cancel()
+		r = r.WithContext(ctx)
 	}
 
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		h.serveWrite(w, r)
+		return
+	}
 	h.mux.ServeHTTP(w, r)
 }
 
@@ -141,33 +248,129 @@ func jsonError(w http.ResponseWriter, message string, code int) {
 	}
 }
 
-// internalError logs the real error server-side and returns a generic message to the client
-func internalError(w http.ResponseWriter, err error) {
-	log.Printf("Error: %v", err)
-	jsonError(w, "Internal server error", http.StatusInternalServerError)
-}
-
-// checkReadOnly returns true and sends an error response if in readonly mode
-func (h *Handler) checkReadOnly(w http.ResponseWriter) bool {
+// checkReadOnly returns true and sends a response if the write must not run:
+// an error in readonly mode, or a synthetic success in dry-run mode. Every
+// write handler calls it before touching the client, which makes it the single
+// gate for both modes.
+func (h *Handler) checkReadOnly(w http.ResponseWriter, r *http.Request) bool {
 	if h.cfg.ReadOnly {
 		jsonError(w, "Server is in read-only mode", http.StatusForbidden)
 		return true
 	}
+	if h.cfg.DryRun {
+		dryRun(w, r)
+		return true
+	}
 	return false
 }
 
-// checkKeyPrefix returns true and sends an error response if key doesn't match prefix
+// maxDryRunLogBody caps how much of a request body is logged in dry-run mode
+const maxDryRunLogBody = 1024
+
+// dryRun logs the write a request would have performed and reports success without running it
+func dryRun(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(io.LimitReader(r.Body, maxDryRunLogBody))
+	log.Printf("Dry run: %s %s %s", r.Method, r.URL.RequestURI(), body)
+	jsonResponse(w, map[string]any{"dryRun": true})
+}
+
+// keyAllowed reports whether key matches the configured prefix and allow patterns
+func (h *Handler) keyAllowed(key string) bool {
+	return h.cfg.KeyAllowed(key)
+}
+
+// keyScopeError explains why key is outside the configured scope, or returns "" if it's allowed
+func (h *Handler) keyScopeError(key string) string {
+	switch {
+	case !strings.HasPrefix(key, h.cfg.Prefix):
+		return "Key does not match required prefix"
+	case h.cfg.KeyDenied(key):
+		return "Key matches a denied pattern"
+	case !h.cfg.KeyAllowed(key):
+		return "Key does not match any allowed pattern"
+	}
+	return ""
+}
+
+// checkKeyPrefix returns true and sends an error response if key is outside the configured scope
 func (h *Handler) checkKeyPrefix(w http.ResponseWriter, key string) bool {
-	if h.cfg.Prefix != "" && !strings.HasPrefix(key, h.cfg.Prefix) {
-		jsonError(w, "Key does not match required prefix", http.StatusForbidden)
+	if msg := h.keyScopeError(key); msg != "" {
+		jsonError(w, msg, http.StatusForbidden)
+		return true
+	}
+	return false
+}
+
+// checkValueSize writes a 413 and returns true if any value exceeds MaxValueSize
+func (h *Handler) checkValueSize(w http.ResponseWriter, values ...string) bool {
+	if msg := h.valueSizeError(values...); msg != "" {
+		jsonError(w, msg, http.StatusRequestEntityTooLarge)
+		return true
+	}
+	return false
+}
+
+// valueSizeError returns a message naming the limit if any value exceeds MaxValueSize
+func (h *Handler) valueSizeError(values ...string) string {
+	if tooLarge(h.cfg.MaxValueSize, values...) {
+		return fmt.Sprintf("Value exceeds the %d byte limit", h.cfg.MaxValueSize)
+	}
+	return ""
+}
+
+// tooLarge reports whether any value is longer than limit (<= 0 = no limit)
+func tooLarge(limit int64, values ...string) bool {
+	if limit <= 0 {
+		return false
+	}
+	for _, v := range values {
+		if int64(len(v)) > limit {
+			return true
+		}
+	}
+	return false
+}
+
+// checkStringGrowth writes a 413 and returns true if the string at key would
+// exceed MaxValueSize after writing value at offset (offset < 0 appends)
+func (h *Handler) checkStringGrowth(w http.ResponseWriter, r *http.Request, key string, offset int64, value string) bool {
+	if h.cfg.MaxValueSize <= 0 {
+		return false
+	}
+	// Too large on its own; no need to ask the server
+	if h.checkValueSize(w, value) || (offset >= 0 && h.checkSize(w, offset+int64(len(value)))) {
+		return true
+	}
+
+	current, err := h.client.StrLen(r.Context(), key)
+	if err != nil {
+		errorResponse(w, err)
+		return true
+	}
+	size := current + int64(len(value))
+	if offset >= 0 {
+		size = max(current, offset+int64(len(value)))
+	}
+	return h.checkSize(w, size)
+}
+
+// checkSize writes a 413 and returns true if size exceeds MaxValueSize
+func (h *Handler) checkSize(w http.ResponseWriter, size int64) bool {
+	if size > h.cfg.MaxValueSize {
+		jsonError(w, fmt.Sprintf("Value exceeds the %d byte limit", h.cfg.MaxValueSize), http.StatusRequestEntityTooLarge)
 		return true
 	}
 	return false
 }
 
-// applyPrefixToPattern prepends the configured prefix to a search pattern
+// applyPrefixToPattern prepends the configured prefix to a search pattern.
+// Allow and deny patterns can't be combined into one glob, so scans filter
+// with keyAllowed afterwards; a lone allow pattern still narrows a match-all scan.
 func (h *Handler) applyPrefixToPattern(pattern string) string {
 	if h.cfg.Prefix == "" {
+		if pattern == "*" && len(h.cfg.AllowPatterns) == 1 {
+			return h.cfg.AllowPatterns[0]
+		}
 		return pattern
 	}
 	// If pattern is "*", return "prefix*"
@@ -179,9 +382,114 @@ func (h *Handler) applyPrefixToPattern(pattern string) string {
 	return h.cfg.Prefix + pattern
 }
 
+// escapeGlob escapes SCAN MATCH metacharacters so s matches literally
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// scanCount returns the configured SCAN COUNT hint, or fallback if unset
+func (h *Handler) scanCount(fallback int64) int64 {
+	if h.cfg.ScanCount > 0 {
+		return h.cfg.ScanCount
+	}
+	return fallback
+}
+
+// maxScanKeys bounds how many keys full-keyspace scans collect
+const maxScanKeys = 10000
+
+// scanKeys iterates SCAN over pattern and collects matching keys, stopping at
+// maxScanKeys (or MaxKeys if lower). The pattern should already include the prefix.
+func (h *Handler) scanKeys(ctx context.Context, pattern string) ([]string, error) {
+	var allKeys []string
+	err := h.scanBatches(ctx, pattern, maxScanKeys, func(keys []string) error {
+		allKeys = append(allKeys, keys...)
+		return nil
+	})
+	return allKeys, err
+}
+
+// scanBatches iterates SCAN over pattern and calls fn with each non-empty batch,
+// stopping after limit keys (or MaxKeys if lower). Soft-delete backups and keys
+// outside the allow/deny patterns are skipped.
+func (h *Handler) scanBatches(ctx context.Context, pattern string, limit int64, fn func(keys []string) error) error {
+	if h.cfg.MaxKeys > 0 && h.cfg.MaxKeys < limit {
+		limit = h.cfg.MaxKeys
+	}
+
+	var cursor uint64
+	var seen int64
+	for {
+		keys, nextCursor, err := h.client.Keys(ctx, pattern, cursor, h.scanCount(1000), "")
+		if err != nil {
+			return err
+		}
+
+		batch := keys[:0]
+		for _, key := range keys {
+			if !valkey.IsTrashKey(key) && h.keyAllowed(key) {
+				batch = append(batch, key)
+			}
+		}
+		if int64(len(batch)) > limit-seen {
+			batch = batch[:limit-seen]
+		}
+		seen += int64(len(batch))
+
+		if len(batch) > 0 {
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+
+		cursor = nextCursor
+		if cursor == 0 || seen >= limit {
+			return nil
+		}
+	}
+}
+
 // Handlers
 
+// handleNotFound answers unmatched API requests: 405 when another method is
+// routed for the path, 404 otherwise
+func (h *Handler) handleNotFound(w http.ResponseWriter, r *http.Request) {
+	var allowed []string
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete} {
+		probe := r.Clone(r.Context())
+		probe.Method = method
+		if _, pattern := h.mux.Handler(probe); pattern != "/api/" {
+			allowed = append(allowed, method)
+		}
+	}
+	if len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		jsonError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	jsonError(w, "Not found", http.StatusNotFound)
+}
+
+// handleHealth always answers 200 so the UI can show a degraded state;
+// with ?strict=1 an unreachable database is reported as 503 for probes
 func (h *Handler) handleHealth(w http.ResponseWriter, r *http.Request) {
+	h.serveHealth(w, r, r.URL.Query().Get("strict") == "1")
+}
+
+// handleReady is the strict health check, for load balancer and readiness probes
+func (h *Handler) handleReady(w http.ResponseWriter, r *http.Request) {
+	h.serveHealth(w, r, true)
+}
+
+func (h *Handler) serveHealth(w http.ResponseWriter, r *http.Request, strict bool) {
 	// Check database connectivity by pinging
 	err := h.client.Ping(r.Context())
 
@@ -193,30 +501,79 @@ func (h *Handler) handleHealth(w http.ResponseWriter, r *http.Request) {
 		dbConnected = false
 	}
 
+	if strict && !dbConnected {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
 	jsonResponse(w, map[string]any{
 		"status":    status,
 		"database":  dbConnected,
 		"timestamp": time.Now().Unix(),
+		"uptime":    int64(time.Since(h.started).Seconds()),
+		"version":   h.cfg.Version,
 	})
 }
 
+// handleConfig reports the settings the frontend needs to hide disabled
+// features and check limits before sending a request
 func (h *Handler) handleConfig(w http.ResponseWriter, r *http.Request) {
 	jsonResponse(w, map[string]any{
-		"readOnly":     h.cfg.ReadOnly,
-		"prefix":       h.cfg.Prefix,
-		"disableFlush": h.cfg.DisableFlush,
-		"version":      h.cfg.Version,
-		"commit":       h.cfg.Commit,
-		"dirty":        h.cfg.Dirty,
+		"readOnly":       h.cfg.ReadOnly,
+		"dryRun":         h.cfg.DryRun,
+		"prefix":         h.cfg.Prefix,
+		"allowPatterns":  h.cfg.AllowPatterns,
+		"denyPatterns":   h.cfg.DenyPatterns,
+		"writableTypes":  h.writableTypes(),
+		"disableFlush":   h.cfg.DisableFlush,
+		"softDelete":     h.cfg.SoftDeleteTTL > 0,
+		"requireConfirm": h.cfg.RequireConfirm,
+
+		// Limits (0 = none)
+		"maxKeys":            h.cfg.MaxKeys,
+		"scanCount":          h.cfg.ScanCount,
+		"maxValueSize":       h.cfg.MaxValueSize,
+		"maxBodySize":        maxBodySize,
+		"maxImportSize":      maxImportSize,
+		"maxConcurrentScans": h.cfg.MaxConcurrentScans,
+		"opTimeout":          h.cfg.OpTimeout,
+
+		// Optional endpoints
+		"clientKill":    h.cfg.AllowClientKill && !h.cfg.ReadOnly,
+		"debug":         h.cfg.EnableDebug,
+		"acl":           h.cfg.EnableACL,
+		"serverConfig":  h.cfg.EnableConfig && !h.cfg.ReadOnly,
+		"monitor":       h.cfg.EnableMonitor && h.cfg.MonitorToken != "",
+		"monitorAuth":   h.cfg.MonitorToken != "",
+		"metrics":       h.cfg.Metrics,
+		"notifications": h.cfg.Notifications,
+
+		"version": h.cfg.Version,
+		"commit":  h.cfg.Commit,
+		"dirty":   h.cfg.Dirty,
 	})
 }
 
+// handleVersion reports the kvweb build and the server it is connected to, for
+// the About dialog and bug reports. serverVersion is omitted if INFO fails.
+func (h *Handler) handleVersion(w http.ResponseWriter, r *http.Request) {
+	resp := map[string]any{
+		"version":   h.cfg.Version,
+		"commit":    h.cfg.Commit,
+		"dirty":     h.cfg.Dirty,
+		"goVersion": runtime.Version(),
+	}
+	if serverVersion, err := h.client.ServerVersion(r.Context()); err == nil {
+		resp["serverVersion"] = serverVersion
+	}
+	jsonResponse(w, resp)
+}
+
 func (h *Handler) handleInfo(w http.ResponseWriter, r *http.Request) {
 	section := r.URL.Query().Get("section")
 
 	info, err := h.client.Info(r.Context(), section)
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -225,16 +582,19 @@ func (h *Handler) handleInfo(w http.ResponseWriter, r *http.Request) {
 		log.Printf("DBSize error: %v", err)
 	}
 
+	// info keeps the raw INFO text for older clients; sections is the parsed form
 	jsonResponse(w, map[string]any{
-		"info":   info,
-		"dbSize": dbSize,
+		"info":     info,
+		"sections": valkey.ParseInfo(info),
+		"dbSize":   dbSize,
 	})
 }
 
 type keyMeta struct {
-	Key  string `json:"key"`
-	Type string `json:"type"`
-	TTL  int64  `json:"ttl"`
+	Key    string `json:"key"`
+	Type   string `json:"type"`
+	TTL    int64  `json:"ttl"`
+	Memory int64  `json:"memory,omitempty"` // only set when sorting by size
 }
 
 func (h *Handler) handleKeys(w http.ResponseWriter, r *http.Request) {
@@ -244,19 +604,34 @@ func (h *Handler) handleKeys(w http.ResponseWriter, r *http.Request) {
 	}
 
 	useRegex := r.URL.Query().Get("regex") == "1"
-
-	// If regex mode, validate and compile the pattern before applying prefix
-	var re *regexp.Regexp
-	if useRegex {
-		var err error
-		re, err = regexp.Compile(pattern)
+	search := r.URL.Query().Get("search")
+
+	// match filters scanned keys when SCAN MATCH can't express the query
+	var match func(key string) bool
+	switch {
+	case search != "" && useRegex:
+		jsonError(w, "search and regex cannot be combined", http.StatusBadRequest)
+		return
+	case search != "" && r.URL.Query().Get("caseInsensitive") == "1":
+		// SCAN MATCH is case-sensitive, so scan everything and filter here
+		needle := strings.ToLower(search)
+		match = func(key string) bool {
+			return strings.Contains(strings.ToLower(key), needle)
+		}
+		pattern = h.applyPrefixToPattern("*")
+	case search != "":
+		pattern = h.applyPrefixToPattern("*" + escapeGlob(search) + "*")
+	case useRegex:
+		// Validate and compile the pattern before applying prefix
+		re, err := regexp.Compile(pattern)
 		if err != nil {
 			jsonError(w, "Invalid regex: "+err.Error(), http.StatusBadRequest)
 			return
 		}
+		match = re.MatchString
 		// Use wildcard for SCAN, filter with regex after
 		pattern = h.applyPrefixToPattern("*")
-	} else {
+	default:
 		pattern = h.applyPrefixToPattern(pattern)
 	}
 
@@ -272,7 +647,7 @@ func (h *Handler) handleKeys(w http.ResponseWriter, r *http.Request) {
 	}
 
 	countStr := r.URL.Query().Get("count")
-	count := int64(100)
+	count := h.scanCount(100)
 	if countStr != "" {
 		var err error
 		count, err = strconv.ParseInt(countStr, 10, 64)
@@ -290,70 +665,75 @@ func (h *Handler) handleKeys(w http.ResponseWriter, r *http.Request) {
 	typeFilter := r.URL.Query().Get("type")
 	withMeta := r.URL.Query().Get("meta") == "1"
 
-	keys, nextCursor, err := h.client.Keys(r.Context(), pattern, cursor, count)
+	// Sorting needs the whole (capped) result set instead of one SCAN page
+	if sortBy := r.URL.Query().Get("sort"); sortBy != "" {
+		h.handleKeysSorted(w, r, pattern, match, typeFilter, sortBy, cursor, count)
+		return
+	}
+
+	// SCAN can filter real types server-side; the synthetic hyperloglog type
+	// is stored as a string, so scan strings and check the magic header below
+	scanType := typeFilter
+	if typeFilter == "hyperloglog" {
+		scanType = "string"
+	}
+
+	keys, nextCursor, err := h.client.Keys(r.Context(), pattern, cursor, count, scanType)
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
-	// Filter by regex if in regex mode
-	if re != nil {
-		filtered := make([]string, 0, len(keys))
-		for _, key := range keys {
-			if re.MatchString(key) {
-				filtered = append(filtered, key)
-			}
-		}
-		keys = filtered
+	// Progress estimate: SCAN examines roughly count entries per call, so the client
+	// passes back the previous scanned value and we add this call's share
+	dbSize, _ := h.client.DBSize(r.Context())
+	var scanned int64
+	if cursor != 0 {
+		scanned, _ = strconv.ParseInt(r.URL.Query().Get("scanned"), 10, 64)
+	}
+	scanned += count
+	if nextCursor == 0 || scanned > dbSize {
+		scanned = dbSize
 	}
 
-	// Filter by type if requested
-	if typeFilter != "" {
+	// Filter by regex or case-insensitive search; soft-delete backups and keys
+	// outside the allow/deny patterns are never listed
+	if match != nil || h.cfg.SoftDeleteTTL > 0 || h.cfg.KeyPatterns() {
 		filtered := make([]string, 0, len(keys))
 		for _, key := range keys {
-			keyType, err := h.client.Type(r.Context(), key)
-			if err != nil {
+			if valkey.IsTrashKey(key) || !h.keyAllowed(key) {
 				continue
 			}
-			// Detect HyperLogLog (stored as string with HYLL magic header)
-			if keyType == "string" {
-				val, err := h.client.Get(r.Context(), key)
-				if err == nil && len(val) >= 4 && val[:4] == "HYLL" {
-					keyType = "hyperloglog"
-				}
-			}
-			if keyType == typeFilter {
+			if match == nil || match(key) {
 				filtered = append(filtered, key)
 			}
 		}
 		keys = filtered
 	}
 
+	// HyperLogLog can't be expressed as a SCAN type and SCAN TYPE string returns
+	// HyperLogLogs too, so both filters check the magic headers
+	if typeFilter == "hyperloglog" || typeFilter == "string" {
+		keys = h.client.FilterHyperLogLog(r.Context(), keys, typeFilter == "hyperloglog")
+	}
+
 	// Return with metadata if requested (for sorting)
 	if withMeta {
-		metas := make([]keyMeta, 0, len(keys))
-		for _, key := range keys {
-			keyType, _ := h.client.Type(r.Context(), key)
-			// Detect HyperLogLog (stored as string with HYLL magic header)
-			if keyType == "string" {
-				val, err := h.client.Get(r.Context(), key)
-				if err == nil && len(val) >= 4 && val[:4] == "HYLL" {
-					keyType = "hyperloglog"
-				}
-			}
-			ttl, _ := h.client.TTL(r.Context(), key)
-			metas = append(metas, keyMeta{Key: key, Type: keyType, TTL: ttl})
-		}
+		metas := h.fetchKeyMeta(r.Context(), keys)
 		jsonResponse(w, map[string]any{
-			"keys":   metas,
-			"cursor": nextCursor,
+			"keys":    metas,
+			"cursor":  nextCursor,
+			"dbSize":  dbSize,
+			"scanned": scanned,
 		})
 		return
 	}
 
 	jsonResponse(w, map[string]any{
-		"keys":   keys,
-		"cursor": nextCursor,
+		"keys":    keys,
+		"cursor":  nextCursor,
+		"dbSize":  dbSize,
+		"scanned": scanned,
 	})
 }
 
@@ -379,31 +759,22 @@ func (h *Handler) handlePrefixes(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Scan all matching keys (with reasonable limit)
-	var allKeys []string
-	var cursor uint64
-	limit := int64(10000)
-	if h.cfg.MaxKeys > 0 && h.cfg.MaxKeys < limit {
-		limit = h.cfg.MaxKeys
-	}
-
-	for {
-		keys, nextCursor, err := h.client.Keys(r.Context(), pattern, cursor, 1000)
-		if err != nil {
-			internalError(w, err)
-			return
-		}
-		allKeys = append(allKeys, keys...)
-		cursor = nextCursor
-		if cursor == 0 || int64(len(allKeys)) >= limit {
-			break
-		}
+	allKeys, err := h.scanKeys(r.Context(), pattern)
+	if err != nil {
+		errorResponse(w, err)
+		return
 	}
 
-	// Group by next prefix segment
+	// Group by next prefix segment; groups only need a count, not their keys
 	prefixLen := len(prefix)
-	groups := make(map[string][]string)
+	groups := make(map[string]int)
+	leafSet := make(map[string]bool)
 
 	for _, key := range allKeys {
+		if valkey.IsTrashKey(key) {
+			continue
+		}
+
 		// Remove the search prefix to get the remainder
 		remainder := key
 		if prefixLen > 0 && len(key) > prefixLen {
@@ -416,34 +787,40 @@ func (h *Handler) handlePrefixes(w http.ResponseWriter, r *http.Request) {
 		delimIdx := strings.Index(remainder, delimiter)
 		if delimIdx == -1 {
 			// This is a leaf key
-			groups[key] = nil
+			leafSet[key] = true
 		} else {
 			// This is a prefix group
-			groupPrefix := prefix + remainder[:delimIdx+1]
-			groups[groupPrefix] = append(groups[groupPrefix], key)
+			groups[prefix+remainder[:delimIdx+1]]++
 		}
 	}
 
+	// Leaf types are fetched in pipelined, bounded-parallel batches
+	leaves := make([]string, 0, len(leafSet))
+	for key := range leafSet {
+		leaves = append(leaves, key)
+	}
+	leafTypes := make(map[string]string, len(leaves))
+	for _, m := range h.fetchKeyMeta(r.Context(), leaves) {
+		leafTypes[m.Key] = m.Type
+	}
+
 	// Build response
-	entries := make([]prefixEntry, 0, len(groups))
-	for groupKey, members := range groups {
-		if members == nil {
-			// Leaf key - get its type
-			keyType, _ := h.client.Type(r.Context(), groupKey)
-			entries = append(entries, prefixEntry{
-				Prefix:  groupKey,
-				Count:   1,
-				IsLeaf:  true,
-				FullKey: groupKey,
-				KeyType: keyType,
-			})
-		} else {
-			entries = append(entries, prefixEntry{
-				Prefix: groupKey,
-				Count:  len(members),
-				IsLeaf: false,
-			})
-		}
+	entries := make([]prefixEntry, 0, len(groups)+len(leaves))
+	for _, key := range leaves {
+		entries = append(entries, prefixEntry{
+			Prefix:  key,
+			Count:   1,
+			IsLeaf:  true,
+			FullKey: key,
+			KeyType: leafTypes[key],
+		})
+	}
+	for groupKey, count := range groups {
+		entries = append(entries, prefixEntry{
+			Prefix: groupKey,
+			Count:  count,
+			IsLeaf: false,
+		})
 	}
 
 	// Sort by prefix
@@ -459,6 +836,18 @@ func (h *Handler) handlePrefixes(w http.ResponseWriter, r *http.Request) {
 
 const defaultPageSize = 100 // default page size for collections
 
+// maxStringPreview is the largest string slice returned by handleGetKey (256KB).
+// Bigger values are previewed; the rest can be fetched with start/end.
+const maxStringPreview = 256 << 10
+
+// validScoreBound reports whether s is a ZRANGEBYSCORE bound: a number,
+// "(" followed by a number for an exclusive bound, or -inf/+inf
+func validScoreBound(s string) bool {
+	s = strings.TrimPrefix(s, "(")
+	f, err := strconv.ParseFloat(s, 64)
+	return err == nil && !math.IsNaN(f)
+}
+
 func (h *Handler) handleGetKey(w http.ResponseWriter, r *http.Request) {
 	key := r.PathValue("key")
 	if h.checkKeyPrefix(w, key) {
@@ -482,18 +871,14 @@ func (h *Handler) handleGetKey(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Cursor for set/hash cursor-based pagination
-	cursorStr := r.URL.Query().Get("cursor")
-	scanCursor := uint64(0)
-	if cursorStr != "" {
-		if c, err := strconv.ParseUint(cursorStr, 10, 64); err == nil {
-			scanCursor = c
-		}
-	}
+	// Opaque cursor for set/hash/stream pagination; page is ignored for those types
+	cursor := r.URL.Query().Get("cursor")
+	// Glob filtering set/zset members and hash fields server-side via SSCAN/HSCAN/ZSCAN MATCH
+	match := r.URL.Query().Get("match")
 
 	keyType, err := h.client.Type(r.Context(), key)
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -509,14 +894,47 @@ func (h *Handler) handleGetKey(w http.ResponseWriter, r *http.Request) {
 	var value any
 	var length int64
 	var pagination map[string]any
-	var encoding string // detected compression encoding (gzip, zstd)
+	var encoding string             // detected compression encoding (gzip, zstd)
+	var strRange []int64            // byte range returned for partial string views
+	var truncated bool              // string value is only a slice of the full value
+	var version string              // version of a fully loaded string, for conditional saves
+	var latest []valkey.StreamEntry // newest stream entry, shown whatever the page
 
 	switch keyType {
 	case "string":
+		length, _ = h.client.StrLen(ctx, key)
+		startStr, endStr := r.URL.Query().Get("start"), r.URL.Query().Get("end")
+		if startStr != "" || endStr != "" || length > maxStringPreview {
+			// Partial view: an explicit byte range, or a preview of a huge value
+			start, end := int64(0), int64(maxStringPreview-1)
+			if startStr != "" {
+				s, parseErr := strconv.ParseInt(startStr, 10, 64)
+				if parseErr != nil || s < 0 {
+					jsonError(w, "Invalid start", http.StatusBadRequest)
+					return
+				}
+				start, end = s, s+maxStringPreview-1
+			}
+			if endStr != "" {
+				e, parseErr := strconv.ParseInt(endStr, 10, 64)
+				if parseErr != nil || e < start {
+					jsonError(w, "Invalid end", http.StatusBadRequest)
+					return
+				}
+				end = min(e, start+maxStringPreview-1)
+			}
+			value, err = h.client.GetRange(ctx, key, start, end)
+			strRange = []int64{start, min(end, length-1)}
+			truncated = start > 0 || end < length-1
+			break
+		}
 		val, getErr := h.client.Get(ctx, key)
+		if getErr == nil {
+			version = valkey.ValueVersion(val)
+		}
 		if getErr != nil {
 			err = getErr
-		} else if len(val) >= 4 && val[:4] == "HYLL" {
+		} else if valkey.IsHyperLogLog(val) {
 			// HyperLogLog detected by magic header
 			keyType = "hyperloglog"
 			count, _ := h.client.PFCount(ctx, key)
@@ -535,36 +953,48 @@ func (h *Handler) handleGetKey(w http.ResponseWriter, r *http.Request) {
 		}
 	case "list":
 		length, _ = h.client.LLen(ctx, key)
-		start := (page - 1) * pageSize
-		stop := start + pageSize - 1
+		page, totalPages, start, stop := pageBounds(page, pageSize, length)
 		value, err = h.client.LRange(ctx, key, start, stop)
 		if err == nil {
 			pagination = map[string]any{
-				"page":     page,
-				"pageSize": pageSize,
-				"total":    length,
-				"hasMore":  stop < length-1,
+				"page":       page,
+				"pageSize":   pageSize,
+				"total":      length,
+				"totalPages": totalPages,
+				"hasMore":    stop < length-1,
 			}
 		}
 	case "set":
 		length, _ = h.client.SCard(ctx, key)
-		// Single SSCAN call per request — no accumulation
-		members, nextCursor, scanErr := h.client.SScan(ctx, key, scanCursor, pageSize)
+		scanCursor, cursorErr := decodeScanCursor(cursor)
+		if cursorErr != nil {
+			jsonError(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		// Each page continues the previous page's SSCAN; nothing is re-scanned
+		members, nextCursor, scanErr := h.client.SScanPage(ctx, key, scanCursor, match, pageSize)
 		if scanErr != nil {
 			err = scanErr
 		} else {
 			value = members
+			_, totalPages, _, _ := pageBounds(1, pageSize, length)
 			pagination = map[string]any{
 				"pageSize":   pageSize,
 				"total":      length,
+				"totalPages": totalPages,
 				"hasMore":    nextCursor != 0,
-				"nextCursor": nextCursor,
+				"nextCursor": encodeScanCursor(nextCursor),
 			}
 		}
 	case "hash":
 		length, _ = h.client.HLen(ctx, key)
-		// Single HSCAN call per request — no accumulation
-		fields, nextCursor, scanErr := h.client.HScan(ctx, key, scanCursor, pageSize)
+		scanCursor, cursorErr := decodeScanCursor(cursor)
+		if cursorErr != nil {
+			jsonError(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		// Each page continues the previous page's HSCAN; nothing is re-scanned
+		fields, nextCursor, scanErr := h.client.HScanPage(ctx, key, scanCursor, match, pageSize)
 		if scanErr != nil {
 			err = scanErr
 		} else {
@@ -581,60 +1011,112 @@ func (h *Handler) handleGetKey(w http.ResponseWriter, r *http.Request) {
 				return pairs[i].Field < pairs[j].Field
 			})
 			value = pairs
+			_, totalPages, _, _ := pageBounds(1, pageSize, length)
 			pagination = map[string]any{
 				"pageSize":   pageSize,
 				"total":      length,
+				"totalPages": totalPages,
 				"hasMore":    nextCursor != 0,
-				"nextCursor": nextCursor,
+				"nextCursor": encodeScanCursor(nextCursor),
 			}
 		}
 	case "zset":
 		length, _ = h.client.ZCard(ctx, key)
-		start := (page - 1) * pageSize
-		stop := start + pageSize - 1
+		if match != "" {
+			// Name search: ZSCAN with cursor pagination instead of ordered index ranges
+			if r.URL.Query().Get("byScore") == "1" {
+				jsonError(w, "match cannot be combined with byScore", http.StatusBadRequest)
+				return
+			}
+			scanCursor, cursorErr := decodeScanCursor(cursor)
+			if cursorErr != nil {
+				jsonError(w, "Invalid cursor", http.StatusBadRequest)
+				return
+			}
+			members, nextCursor, scanErr := h.client.ZScanPage(ctx, key, scanCursor, match, pageSize)
+			if scanErr != nil {
+				err = scanErr
+				break
+			}
+			value = members
+			_, totalPages, _, _ := pageBounds(1, pageSize, length)
+			pagination = map[string]any{
+				"pageSize":   pageSize,
+				"total":      length,
+				"totalPages": totalPages,
+				"hasMore":    nextCursor != 0,
+				"nextCursor": encodeScanCursor(nextCursor),
+			}
+			break
+		}
+		if r.URL.Query().Get("byScore") == "1" {
+			// Score window: pagination counts only members inside [min, max]
+			minScore, maxScore := r.URL.Query().Get("min"), r.URL.Query().Get("max")
+			if minScore == "" {
+				minScore = "-inf"
+			}
+			if maxScore == "" {
+				maxScore = "+inf"
+			}
+			if !validScoreBound(minScore) || !validScoreBound(maxScore) {
+				jsonError(w, "Invalid score bound (expected a number, (number, -inf, or +inf)", http.StatusBadRequest)
+				return
+			}
+			total, countErr := h.client.ZCount(ctx, key, minScore, maxScore)
+			if countErr != nil {
+				errorResponse(w, countErr)
+				return
+			}
+			page, totalPages, start, stop := pageBounds(page, pageSize, total)
+			value, err = h.client.ZRangeByScore(ctx, key, minScore, maxScore, start, pageSize)
+			if err == nil {
+				pagination = map[string]any{
+					"page":       page,
+					"pageSize":   pageSize,
+					"total":      total,
+					"totalPages": totalPages,
+					"hasMore":    stop < total-1,
+				}
+			}
+			break
+		}
+		page, totalPages, start, stop := pageBounds(page, pageSize, length)
 		value, err = h.client.ZRangeWithScores(ctx, key, start, stop)
 		if err == nil {
 			pagination = map[string]any{
-				"page":     page,
-				"pageSize": pageSize,
-				"total":    length,
-				"hasMore":  stop < length-1,
+				"page":       page,
+				"pageSize":   pageSize,
+				"total":      length,
+				"totalPages": totalPages,
+				"hasMore":    stop < length-1,
 			}
 		}
 	case "stream":
 		length, _ = h.client.XLen(ctx, key)
-		// Streams use ID-based pagination for efficiency
-		// We fetch only the entries needed using XRANGE with cursor
-
-		// To support page jumping, we need to find the starting ID for the requested page
-		// For page 1, start from beginning. For others, we need to skip entries.
-		var startAfterID string
-		if page > 1 {
-			// We need to skip (page-1) * pageSize entries to find the start ID
-			skipCount := (page - 1) * pageSize
-			if skipCount < length {
-				// Fetch entries up to but not including the target page to get the cursor
-				skipEntries, err := h.client.XRange(ctx, key, "-", "+", skipCount)
-				if err != nil {
-					internalError(w, err)
-					return
-				}
-				if len(skipEntries) > 0 {
-					startAfterID = skipEntries[len(skipEntries)-1].ID
-				}
-			}
+		// Each page continues from the last ID of the previous one, so deep pages
+		// cost the same as the first. reverse=1 pages newest-first.
+		startID, cursorErr := decodeCursor(cursor)
+		if cursorErr != nil || (startID != "" && !streamIDPattern.MatchString(startID)) {
+			jsonError(w, "Invalid cursor", http.StatusBadRequest)
+			return
 		}
-
-		// Now fetch the actual page using the cursor
-		entries, nextCursor, err := h.client.XRangePage(ctx, key, startAfterID, pageSize)
-		if err == nil {
+		pageFn := h.client.XRangePage
+		if r.URL.Query().Get("reverse") == "1" {
+			pageFn = h.client.XRevRangePage
+		}
+		entries, nextCursor, rangeErr := pageFn(ctx, key, startID, pageSize)
+		if rangeErr != nil {
+			err = rangeErr
+		} else {
+			latest, _ = h.client.XRevRange(ctx, key, "+", "-", 1)
 			value = entries
+			_, totalPages, _, _ := pageBounds(1, pageSize, length)
 			pagination = map[string]any{
-				"page":       page,
 				"pageSize":   pageSize,
 				"total":      length,
+				"totalPages": totalPages,
 				"hasMore":    nextCursor != "",
-				"nextCursor": nextCursor,
+				"nextCursor": encodeCursor(nextCursor),
 			}
 		}
 	default:
@@ -642,7 +1124,7 @@ func (h *Handler) handleGetKey(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -666,11 +1148,42 @@ func (h *Handler) handleGetKey(w http.ResponseWriter, r *http.Request) {
 		resp["encoding"] = encoding
 	}
 
+	if strRange != nil {
+		resp["range"] = strRange
+		resp["truncated"] = truncated
+	}
+
+	if len(latest) > 0 {
+		resp["latest"] = latest[0]
+	}
+
+	// OBJECT ENCODING only matters for types with compact and heavy forms
+	var objEncoding string
+	if _, ok := heavyEncodings[keyType]; ok {
+		objEncoding, _ = h.client.ObjectEncoding(ctx, key)
+	}
+	if warnings := keyWarnings(keyType, objEncoding, length); len(warnings) > 0 {
+		resp["warnings"] = warnings
+	}
+
+	if version != "" {
+		resp["version"] = version
+		w.Header().Set("ETag", `"`+version+`"`)
+	}
+
 	jsonResponse(w, resp)
 }
 
+// ifMatchVersion extracts the value version from an If-Match header. The
+// compress middleware weakens the ETag of gzipped responses (W/"..."), but the
+// version hashes the value, not the bytes sent, so the weak form matches too.
+func ifMatchVersion(header string) string {
+	tag := strings.TrimPrefix(strings.TrimSpace(header), "W/")
+	return strings.Trim(tag, `"`)
+}
+
 func (h *Handler) handleSetKey(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -683,6 +1196,7 @@ func (h *Handler) handleSetKey(w http.ResponseWriter, r *http.Request) {
 		Value    string `json:"value"`
 		TTL      int64  `json:"ttl"`      // seconds, 0 = no expiry
 		Encoding string `json:"encoding"` // "gzip", "zstd", or ""
+		Version  string `json:"version"`  // only save if the value still has this version (also via If-Match)
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -699,22 +1213,42 @@ func (h *Handler) handleSetKey(w http.ResponseWriter, r *http.Request) {
 		}
 		body.Value = compressed
 	}
+	if h.checkValueSize(w, body.Value) {
+		return
+	}
 
 	ttl := time.Duration(0)
 	if body.TTL > 0 {
 		ttl = time.Duration(body.TTL) * time.Second
 	}
 
-	if err := h.client.Set(r.Context(), key, body.Value, ttl); err != nil {
-		internalError(w, err)
+	version := body.Version
+	if version == "" {
+		version = ifMatchVersion(r.Header.Get("If-Match"))
+	}
+
+	undo := h.captureString(r.Context(), key)
+	if version != "" {
+		ok, err := h.client.SetIfUnchanged(r.Context(), key, version, body.Value, ttl)
+		if err != nil {
+			errorResponse(w, err)
+			return
+		}
+		if !ok {
+			jsonError(w, "Value changed since it was loaded", http.StatusPreconditionFailed)
+			return
+		}
+	} else if err := h.client.Set(r.Context(), key, body.Value, ttl); err != nil {
+		errorResponse(w, err)
 		return
 	}
+	setUndo(r, undo)
 
 	jsonResponse(w, map[string]string{"status": "ok"})
 }
 
 func (h *Handler) handleDeleteKey(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -723,11 +1257,15 @@ func (h *Handler) handleDeleteKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	deleted, err := h.client.Del(r.Context(), key)
+	undo := h.captureString(r.Context(), key)
+	deleted, err := h.deleteKeys(r.Context(), key)
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
+	if deleted > 0 {
+		setUndo(r, undo)
+	}
 
 	jsonResponse(w, map[string]any{
 		"deleted": deleted,
@@ -735,7 +1273,7 @@ func (h *Handler) handleDeleteKey(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) handleDeleteKeys(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -758,10 +1296,13 @@ func (h *Handler) handleDeleteKeys(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if h.checkKeysWritable(w, r.Context(), body.Keys...) {
+		return
+	}
 
-	deleted, err := h.client.Del(r.Context(), body.Keys...)
+	deleted, err := h.deleteKeys(r.Context(), body.Keys...)
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -771,7 +1312,7 @@ func (h *Handler) handleDeleteKeys(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) handleIncrKey(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -789,9 +1330,31 @@ func (h *Handler) handleIncrKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Whole amounts use the integer commands so counters keep integer semantics.
+	// A key already holding a float can't be INCRBY'd, so fall back to INCRBYFLOAT.
+	if body.Amount == math.Trunc(body.Amount) && math.Abs(body.Amount) < 1<<53 {
+		var newValue int64
+		var err error
+		if body.Amount < 0 {
+			newValue, err = h.client.DecrBy(r.Context(), key, int64(-body.Amount))
+		} else {
+			newValue, err = h.client.IncrBy(r.Context(), key, int64(body.Amount))
+		}
+		if err == nil {
+			jsonResponse(w, map[string]any{
+				"value": newValue,
+			})
+			return
+		}
+		if !strings.Contains(err.Error(), "not an integer") {
+			errorResponse(w, err)
+			return
+		}
+	}
+
 	newValue, err := h.client.IncrByFloat(r.Context(), key, body.Amount)
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -800,9 +1363,148 @@ func (h *Handler) handleIncrKey(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (h *Handler) handleExpire(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
-		return
+// handleKeyType returns the key's type, reporting HyperLogLogs as "hyperloglog"
+func (h *Handler) handleKeyType(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	keyType, err := h.client.DetectType(r.Context(), key)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	if keyType == "none" {
+		jsonError(w, "Key not found", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, map[string]string{"key": key, "type": keyType})
+}
+
+// handleKeyObject returns internal storage details: encoding, refcount, idle time, and memory.
+// Idle time is omitted when the server uses an LFU eviction policy.
+func (h *Handler) handleKeyObject(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	ctx := r.Context()
+
+	encoding, err := h.client.ObjectEncoding(ctx, key)
+	if err != nil {
+		if valkey.IsNil(err) {
+			jsonError(w, "Key not found", http.StatusNotFound)
+			return
+		}
+		errorResponse(w, err)
+		return
+	}
+
+	resp := map[string]any{
+		"key":      key,
+		"encoding": encoding,
+	}
+
+	if refCount, err := h.client.ObjectRefCount(ctx, key); err == nil {
+		resp["refCount"] = refCount
+	}
+	// The server tracks either access frequency (LFU) or idle time (otherwise), not both
+	policy, _ := h.client.MaxMemoryPolicy(ctx)
+	if policy != "" {
+		resp["evictionPolicy"] = policy
+	}
+	if strings.Contains(policy, "lfu") {
+		if freq, err := h.client.ObjectFreq(ctx, key); err == nil {
+			resp["freq"] = freq
+		}
+	} else if idle, err := h.client.ObjectIdleTime(ctx, key); err == nil {
+		resp["idleTime"] = idle
+	}
+	if memory, err := h.client.MemoryUsage(ctx, key); err == nil {
+		resp["memory"] = memory
+	}
+
+	jsonResponse(w, resp)
+}
+
+func (h *Handler) handleAppend(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	var body struct {
+		Value string `json:"value"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if h.checkStringGrowth(w, r, key, -1, body.Value) {
+		return
+	}
+
+	length, err := h.client.Append(r.Context(), key, body.Value)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{"status": "ok", "length": length})
+}
+
+// maxStringSize is the largest string Valkey allows (512MB)
+const maxStringSize = 512 << 20
+
+func (h *Handler) handleSetRange(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	var body struct {
+		Offset int64  `json:"offset"`
+		Value  string `json:"value"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if body.Offset < 0 || body.Offset+int64(len(body.Value)) > maxStringSize {
+		jsonError(w, "Offset out of range", http.StatusBadRequest)
+		return
+	}
+	if h.checkStringGrowth(w, r, key, body.Offset, body.Value) {
+		return
+	}
+
+	length, err := h.client.SetRange(r.Context(), key, body.Offset, body.Value)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{"status": "ok", "length": length})
+}
+
+func (h *Handler) handleExpire(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
 	}
 
 	key := r.PathValue("key")
@@ -829,7 +1531,7 @@ func (h *Handler) handleExpire(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -837,7 +1539,7 @@ func (h *Handler) handleExpire(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) handleRename(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -865,9 +1567,24 @@ func (h *Handler) handleRename(w http.ResponseWriter, r *http.Request) {
 	if h.checkKeyPrefix(w, body.NewKey) {
 		return
 	}
+	if h.checkKeysWritable(w, r.Context(), body.NewKey) {
+		return
+	}
+
+	// Renaming over an existing key destroys it, so it needs confirmation
+	if h.cfg.RequireConfirm && !confirmed(r) {
+		exists, err := h.client.Exists(r.Context(), body.NewKey)
+		if err != nil {
+			errorResponse(w, err)
+			return
+		}
+		if exists > 0 && h.checkConfirm(w, r) {
+			return
+		}
+	}
 
 	if err := h.client.Rename(r.Context(), key, body.NewKey); err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -875,7 +1592,7 @@ func (h *Handler) handleRename(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) handleFlush(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -885,7 +1602,7 @@ func (h *Handler) handleFlush(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.client.FlushDB(r.Context()); err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -895,7 +1612,7 @@ func (h *Handler) handleFlush(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) handleGetNotifications(w http.ResponseWriter, r *http.Request) {
 	val, err := h.client.GetNotifyKeyspaceEvents(r.Context())
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 	jsonResponse(w, map[string]any{
@@ -905,7 +1622,7 @@ func (h *Handler) handleGetNotifications(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *Handler) handleSetNotifications(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -927,7 +1644,7 @@ func (h *Handler) handleSetNotifications(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := h.client.SetNotifyKeyspaceEvents(r.Context(), val); err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -947,7 +1664,7 @@ func (h *Handler) handleSetNotifications(w http.ResponseWriter, r *http.Request)
 // List operation handlers
 
 func (h *Handler) handleListAdd(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -965,6 +1682,9 @@ func (h *Handler) handleListAdd(w http.ResponseWriter, r *http.Request) {
 		jsonError(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if h.checkValueSize(w, body.Value) {
+		return
+	}
 
 	var err error
 	if body.Position == "head" {
@@ -974,15 +1694,121 @@ func (h *Handler) handleListAdd(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
 	jsonResponse(w, map[string]string{"status": "ok"})
 }
 
+func (h *Handler) handleListInsert(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	var body struct {
+		Pivot    string `json:"pivot"`
+		Value    string `json:"value"`
+		Position string `json:"position"` // "before" or "after"
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if body.Position != "before" && body.Position != "after" {
+		jsonError(w, "Position must be before or after", http.StatusBadRequest)
+		return
+	}
+	if h.checkValueSize(w, body.Value) {
+		return
+	}
+
+	length, err := h.client.LInsert(r.Context(), key, body.Position == "before", body.Pivot, body.Value)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	switch length {
+	case 0:
+		jsonError(w, "Key not found", http.StatusNotFound)
+		return
+	case -1:
+		jsonError(w, "Pivot not found", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, map[string]any{"status": "ok", "length": length})
+}
+
+func (h *Handler) handleListTrim(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	var body struct {
+		Start *int64 `json:"start"`
+		Stop  *int64 `json:"stop"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if body.Start == nil || body.Stop == nil {
+		jsonError(w, "start and stop are required", http.StatusBadRequest)
+		return
+	}
+
+	// An inverted range empties (and deletes) the list, which is almost never intended
+	if *body.Start >= 0 && *body.Stop >= 0 && *body.Start > *body.Stop {
+		jsonError(w, "start must not be greater than stop", http.StatusBadRequest)
+		return
+	}
+
+	keyType, err := h.client.Type(r.Context(), key)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	if keyType == "none" {
+		jsonError(w, "Key not found", http.StatusNotFound)
+		return
+	}
+	if keyType != "list" {
+		jsonError(w, "Key is not a list", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.client.LTrim(r.Context(), key, *body.Start, *body.Stop); err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	length, err := h.client.LLen(r.Context(), key)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{"status": "ok", "length": length})
+}
+
 func (h *Handler) handleListSet(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1006,9 +1832,13 @@ func (h *Handler) handleListSet(w http.ResponseWriter, r *http.Request) {
 		jsonError(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if h.checkValueSize(w, body.Value) {
+		return
+	}
 
+	// Negative indices count from the tail; LSET handles them natively
 	if err := h.client.LSet(r.Context(), key, index, body.Value); err != nil {
-		internalError(w, err)
+		listIndexError(w, err)
 		return
 	}
 
@@ -1016,7 +1846,7 @@ func (h *Handler) handleListSet(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) handleListRemove(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1033,17 +1863,31 @@ func (h *Handler) handleListRemove(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.client.LRemByIndex(r.Context(), key, index); err != nil {
-		internalError(w, err)
+		listIndexError(w, err)
 		return
 	}
 
 	jsonResponse(w, map[string]string{"status": "ok"})
 }
 
+// listIndexError reports an out-of-range list index (from LSET or
+// LRemByIndex) as 400 and anything else as an internal error
+func listIndexError(w http.ResponseWriter, err error) {
+	if errors.Is(err, valkey.ErrIndexOutOfRange) || strings.Contains(err.Error(), "index out of range") {
+		jsonError(w, "Index out of range", http.StatusBadRequest)
+		return
+	}
+	if strings.Contains(err.Error(), "no such key") {
+		jsonError(w, "Key not found", http.StatusNotFound)
+		return
+	}
+	errorResponse(w, err)
+}
+
 // Set operation handlers
 
 func (h *Handler) handleSetAdd(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1065,11 +1909,14 @@ func (h *Handler) handleSetAdd(w http.ResponseWriter, r *http.Request) {
 		jsonError(w, "Member cannot be empty", http.StatusBadRequest)
 		return
 	}
+	if h.checkValueSize(w, body.Member) {
+		return
+	}
 
 	// Check for duplicate
 	exists, err := h.client.SIsMember(r.Context(), key, body.Member)
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 	if exists {
@@ -1078,7 +1925,7 @@ func (h *Handler) handleSetAdd(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.client.SAdd(r.Context(), key, body.Member); err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -1086,7 +1933,7 @@ func (h *Handler) handleSetAdd(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) handleSetRemove(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1102,7 +1949,7 @@ func (h *Handler) handleSetRemove(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.client.SRem(r.Context(), key, member); err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -1110,7 +1957,7 @@ func (h *Handler) handleSetRemove(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) handleSetRename(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1147,7 +1994,7 @@ func (h *Handler) handleSetRename(w http.ResponseWriter, r *http.Request) {
 		case "New member already exists":
 			jsonError(w, "New member already exists", http.StatusConflict)
 		default:
-			internalError(w, err)
+			errorResponse(w, err)
 		}
 		return
 	}
@@ -1155,21 +2002,18 @@ func (h *Handler) handleSetRename(w http.ResponseWriter, r *http.Request) {
 	jsonResponse(w, map[string]string{"status": "ok"})
 }
 
-// Hash operation handlers
-
-func (h *Handler) handleHashSet(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
-		return
-	}
-
-	key := r.PathValue("key")
-	if h.checkKeyPrefix(w, key) {
-		return
-	}
+// maxSetOpKeys limits how many sets a single set operation may combine
+const maxSetOpKeys = 32
 
+// handleSetOp previews SINTER/SUNION/SDIFF results without creating a key.
+// With a store target, the result is written there instead (a write).
+func (h *Handler) handleSetOp(w http.ResponseWriter, r *http.Request) {
 	var body struct {
-		Field string `json:"field"`
-		Value string `json:"value"`
+		Op       string   `json:"op"`
+		Keys     []string `json:"keys"`
+		Store    string   `json:"store"` // optional destination key
+		Page     int64    `json:"page"`
+		PageSize int64    `json:"pageSize"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -1177,45 +2021,81 @@ func (h *Handler) handleHashSet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if body.Field == "" {
-		jsonError(w, "Field name cannot be empty", http.StatusBadRequest)
+	if body.Op != "inter" && body.Op != "union" && body.Op != "diff" {
+		jsonError(w, "Invalid op (expected inter, union, or diff)", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.client.HSet(r.Context(), key, body.Field, body.Value); err != nil {
-		internalError(w, err)
+	if len(body.Keys) == 0 {
+		jsonError(w, "At least one key is required", http.StatusBadRequest)
 		return
 	}
-
-	jsonResponse(w, map[string]string{"status": "ok"})
-}
-
-func (h *Handler) handleHashRemove(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if len(body.Keys) > maxSetOpKeys {
+		jsonError(w, fmt.Sprintf("Too many keys (max %d)", maxSetOpKeys), http.StatusBadRequest)
 		return
 	}
 
-	key := r.PathValue("key")
-	if h.checkKeyPrefix(w, key) {
-		return
+	for _, key := range body.Keys {
+		if h.checkKeyPrefix(w, key) {
+			return
+		}
 	}
 
-	field := r.PathValue("field")
-	if field == "" {
-		jsonError(w, "Field name cannot be empty", http.StatusBadRequest)
+	if body.Store != "" {
+		if h.checkReadOnly(w, r) {
+			return
+		}
+		if h.checkKeyPrefix(w, body.Store) {
+			return
+		}
+		if h.checkTypeWritable(w, "set") || h.checkKeysWritable(w, r.Context(), body.Store) {
+			return
+		}
+
+		size, err := h.client.SetOpStore(r.Context(), body.Op, body.Store, body.Keys...)
+		if err != nil {
+			errorResponse(w, err)
+			return
+		}
+
+		jsonResponse(w, map[string]any{"status": "ok", "store": body.Store, "total": size})
 		return
 	}
 
-	if err := h.client.HDel(r.Context(), key, field); err != nil {
-		internalError(w, err)
+	markReadOnly(r)
+	members, err := h.client.SetOp(r.Context(), body.Op, body.Keys...)
+	if err != nil {
+		errorResponse(w, err)
 		return
 	}
+	sort.Strings(members) // stable order across pages
 
-	jsonResponse(w, map[string]string{"status": "ok"})
+	pageSize := body.PageSize
+	if pageSize <= 0 || pageSize > 1000 {
+		pageSize = defaultPageSize
+	}
+	total := int64(len(members))
+	page, totalPages, start, stop := pageBounds(body.Page, pageSize, total)
+	if stop >= total {
+		stop = total - 1
+	}
+
+	jsonResponse(w, map[string]any{
+		"members": members[start : stop+1],
+		"pagination": map[string]any{
+			"page":       page,
+			"pageSize":   pageSize,
+			"total":      total,
+			"totalPages": totalPages,
+			"hasMore":    stop < total-1,
+		},
+	})
 }
 
-func (h *Handler) handleHashRename(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+// Hash operation handlers
+
+func (h *Handler) handleHashSet(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1224,14 +2104,11 @@ func (h *Handler) handleHashRename(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	oldField := r.PathValue("field")
-	if oldField == "" {
-		jsonError(w, "Field name cannot be empty", http.StatusBadRequest)
-		return
-	}
-
 	var body struct {
-		NewField string `json:"newField"`
+		Field  string            `json:"field"`
+		Value  string            `json:"value"`
+		Fields map[string]string `json:"fields"` // set many fields at once instead of field/value
+		NX     bool              `json:"nx"`     // only set if the field doesn't exist
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -1239,7 +2116,121 @@ func (h *Handler) handleHashRename(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if body.NewField == "" {
+	if len(body.Fields) > 0 {
+		if body.NX {
+			jsonError(w, "nx is only supported for a single field", http.StatusBadRequest)
+			return
+		}
+		for field, value := range body.Fields {
+			if field == "" {
+				jsonError(w, "Field name cannot be empty", http.StatusBadRequest)
+				return
+			}
+			if h.checkValueSize(w, field, value) {
+				return
+			}
+		}
+
+		fields := make([]string, 0, len(body.Fields))
+		for field := range body.Fields {
+			fields = append(fields, field)
+		}
+		undo := h.captureHashFields(r.Context(), key, fields...)
+		if err := h.client.HSetMulti(r.Context(), key, body.Fields); err != nil {
+			errorResponse(w, err)
+			return
+		}
+		setUndo(r, undo)
+
+		jsonResponse(w, map[string]any{"status": "ok", "fields": len(body.Fields)})
+		return
+	}
+
+	if body.Field == "" {
+		jsonError(w, "Field name cannot be empty", http.StatusBadRequest)
+		return
+	}
+	if h.checkValueSize(w, body.Field, body.Value) {
+		return
+	}
+
+	if body.NX {
+		set, err := h.client.HSetNX(r.Context(), key, body.Field, body.Value)
+		if err != nil {
+			errorResponse(w, err)
+			return
+		}
+		if !set {
+			jsonError(w, "Field already exists", http.StatusConflict)
+			return
+		}
+		setUndo(r, &undoStep{Type: "hash", Fields: map[string]*string{body.Field: nil}})
+		jsonResponse(w, map[string]string{"status": "ok"})
+		return
+	}
+
+	undo := h.captureHashFields(r.Context(), key, body.Field)
+	if err := h.client.HSet(r.Context(), key, body.Field, body.Value); err != nil {
+		errorResponse(w, err)
+		return
+	}
+	setUndo(r, undo)
+
+	jsonResponse(w, map[string]string{"status": "ok"})
+}
+
+func (h *Handler) handleHashRemove(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	field := r.PathValue("field")
+	if field == "" {
+		jsonError(w, "Field name cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	undo := h.captureHashFields(r.Context(), key, field)
+	if err := h.client.HDel(r.Context(), key, field); err != nil {
+		errorResponse(w, err)
+		return
+	}
+	setUndo(r, undo)
+
+	jsonResponse(w, map[string]string{"status": "ok"})
+}
+
+func (h *Handler) handleHashRename(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	oldField := r.PathValue("field")
+	if oldField == "" {
+		jsonError(w, "Field name cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		NewField string `json:"newField"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if body.NewField == "" {
 		jsonError(w, "New field name cannot be empty", http.StatusBadRequest)
 		return
 	}
@@ -1253,7 +2244,7 @@ func (h *Handler) handleHashRename(w http.ResponseWriter, r *http.Request) {
 		case "New field already exists":
 			jsonError(w, "New field already exists", http.StatusConflict)
 		default:
-			internalError(w, err)
+			errorResponse(w, err)
 		}
 		return
 	}
@@ -1267,7 +2258,7 @@ func (h *Handler) handleHashRename(w http.ResponseWriter, r *http.Request) {
 // ZSet operation handlers
 
 func (h *Handler) handleZSetAdd(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1290,17 +2281,22 @@ func (h *Handler) handleZSetAdd(w http.ResponseWriter, r *http.Request) {
 		jsonError(w, "Member cannot be empty", http.StatusBadRequest)
 		return
 	}
+	if h.checkValueSize(w, body.Member) {
+		return
+	}
 
+	undo := h.captureZSetScore(r.Context(), key, body.Member)
 	if err := h.client.ZAdd(r.Context(), key, body.Member, body.Score); err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
+	setUndo(r, undo)
 
 	jsonResponse(w, map[string]string{"status": "ok"})
 }
 
 func (h *Handler) handleZSetRemove(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1315,16 +2311,18 @@ func (h *Handler) handleZSetRemove(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	undo := h.captureZSetScore(r.Context(), key, member)
 	if err := h.client.ZRem(r.Context(), key, member); err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
+	setUndo(r, undo)
 
 	jsonResponse(w, map[string]string{"status": "ok"})
 }
 
 func (h *Handler) handleZSetRename(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1362,7 +2360,7 @@ func (h *Handler) handleZSetRename(w http.ResponseWriter, r *http.Request) {
 		case "New member already exists":
 			jsonError(w, "New member already exists", http.StatusConflict)
 		default:
-			internalError(w, err)
+			errorResponse(w, err)
 		}
 		return
 	}
@@ -1374,7 +2372,7 @@ func (h *Handler) handleZSetRename(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) handleZSetIncrScore(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1390,7 +2388,7 @@ func (h *Handler) handleZSetIncrScore(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var body struct {
-		Amount float64 `json:"amount"`
+		Amount *float64 `json:"amount"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -1398,17 +2396,55 @@ func (h *Handler) handleZSetIncrScore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newScore, err := h.client.ZIncrBy(r.Context(), key, member, body.Amount)
+	if body.Amount == nil {
+		jsonError(w, "Amount is required", http.StatusBadRequest)
+		return
+	}
+
+	undo := h.captureZSetScore(r.Context(), key, member)
+	newScore, err := h.client.ZIncrBy(r.Context(), key, member, *body.Amount)
 	if err != nil {
-		internalError(w, err)
+		// e.g. adding -inf to +inf
+		if strings.Contains(err.Error(), "NaN") {
+			jsonError(w, "Resulting score is not a number", http.StatusBadRequest)
+			return
+		}
+		errorResponse(w, err)
 		return
 	}
 
+	setUndo(r, undo)
 	jsonResponse(w, map[string]any{
 		"score": newScore,
 	})
 }
 
+// handleZSetLocate returns a member's rank, reverse rank, and score without paging
+func (h *Handler) handleZSetLocate(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	member := r.PathValue("member")
+	if member == "" {
+		jsonError(w, "Member cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	pos, err := h.client.ZLocate(r.Context(), key, member)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	if pos == nil {
+		jsonError(w, "Member not found", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, pos)
+}
+
 // Geo operation handlers
 
 func (h *Handler) handleGeoGet(w http.ResponseWriter, r *http.Request) {
@@ -1440,12 +2476,11 @@ func (h *Handler) handleGeoGet(w http.ResponseWriter, r *http.Request) {
 	length, _ := h.client.ZCard(ctx, key)
 
 	// Get paginated members
-	start := (page - 1) * pageSize
-	stop := start + pageSize - 1
+	page, totalPages, start, stop := pageBounds(page, pageSize, length)
 
 	zMembers, err := h.client.ZRangeWithScores(ctx, key, start, stop)
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -1458,7 +2493,7 @@ func (h *Handler) handleGeoGet(w http.ResponseWriter, r *http.Request) {
 	// Get coordinates
 	positions, err := h.client.GeoPos(ctx, key, memberNames...)
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -1483,16 +2518,17 @@ func (h *Handler) handleGeoGet(w http.ResponseWriter, r *http.Request) {
 		"ttl":    ttl,
 		"length": length,
 		"pagination": map[string]any{
-			"page":     page,
-			"pageSize": pageSize,
-			"total":    length,
-			"hasMore":  stop < length-1,
+			"page":       page,
+			"pageSize":   pageSize,
+			"total":      length,
+			"totalPages": totalPages,
+			"hasMore":    stop < length-1,
 		},
 	})
 }
 
 func (h *Handler) handleGeoAdd(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1516,6 +2552,9 @@ func (h *Handler) handleGeoAdd(w http.ResponseWriter, r *http.Request) {
 		jsonError(w, "Member cannot be empty", http.StatusBadRequest)
 		return
 	}
+	if h.checkValueSize(w, body.Member) {
+		return
+	}
 
 	// Validate coordinates (Redis geo limits)
 	if body.Longitude < -180 || body.Longitude > 180 {
@@ -1528,17 +2567,132 @@ func (h *Handler) handleGeoAdd(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.client.GeoAdd(r.Context(), key, body.Longitude, body.Latitude, body.Member); err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
 	jsonResponse(w, map[string]string{"status": "ok"})
 }
 
+// maxGeoHashMembers caps how many members one geohash lookup may ask about
+const maxGeoHashMembers = 1000
+
+// handleGeoHash returns member -> geohash for a comma-separated members list,
+// with null for members not in the index
+func (h *Handler) handleGeoHash(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	param := r.URL.Query().Get("members")
+	if param == "" {
+		jsonError(w, "members is required", http.StatusBadRequest)
+		return
+	}
+	members := strings.Split(param, ",")
+	if len(members) > maxGeoHashMembers {
+		jsonError(w, fmt.Sprintf("Too many members (max %d)", maxGeoHashMembers), http.StatusBadRequest)
+		return
+	}
+
+	hashes, err := h.client.GeoHash(r.Context(), key, members...)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	result := make(map[string]*string, len(members))
+	for i, m := range members {
+		if i < len(hashes) {
+			result[m] = hashes[i]
+		}
+	}
+
+	jsonResponse(w, map[string]any{"hashes": result})
+}
+
+// handleGeoSearch finds members within radius of lon/lat, or within a width x height
+// box centered there, nearest first
+func (h *Handler) handleGeoSearch(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	q := r.URL.Query()
+	parse := func(name string) (float64, bool) {
+		s := q.Get(name)
+		if s == "" {
+			return 0, false
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil && !math.IsNaN(f) && !math.IsInf(f, 0)
+	}
+
+	lon, okLon := parse("lon")
+	lat, okLat := parse("lat")
+	if !okLon || lon < -180 || lon > 180 {
+		jsonError(w, "lon must be between -180 and 180", http.StatusBadRequest)
+		return
+	}
+	if !okLat || lat < -85.05112878 || lat > 85.05112878 {
+		jsonError(w, "lat must be between -85.05112878 and 85.05112878", http.StatusBadRequest)
+		return
+	}
+
+	search := valkey.GeoSearchQuery{Longitude: lon, Latitude: lat, Unit: q.Get("unit"), Count: 100}
+	if search.Unit == "" {
+		search.Unit = "km"
+	}
+	if search.Unit != "m" && search.Unit != "km" && search.Unit != "mi" && search.Unit != "ft" {
+		jsonError(w, "unit must be m, km, mi, or ft", http.StatusBadRequest)
+		return
+	}
+
+	if q.Has("radius") {
+		radius, ok := parse("radius")
+		if !ok || radius <= 0 {
+			jsonError(w, "radius must be a positive number", http.StatusBadRequest)
+			return
+		}
+		search.Radius = radius
+	} else {
+		width, okW := parse("width")
+		height, okH := parse("height")
+		if !okW || !okH || width <= 0 || height <= 0 {
+			jsonError(w, "Provide radius, or width and height for a box search", http.StatusBadRequest)
+			return
+		}
+		search.Width, search.Height = width, height
+	}
+
+	if s := q.Get("count"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < 1 || n > 1000 {
+			jsonError(w, "count must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+		search.Count = n
+	}
+
+	results, err := h.client.GeoSearch(r.Context(), key, search)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{
+		"members": results,
+		"unit":    search.Unit,
+		"limited": int64(len(results)) == search.Count,
+	})
+}
+
 // Stream operation handlers
 
 func (h *Handler) handleStreamAdd(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1549,6 +2703,9 @@ func (h *Handler) handleStreamAdd(w http.ResponseWriter, r *http.Request) {
 
 	var body struct {
 		Fields map[string]string `json:"fields"`
+		ID     string            `json:"id"`     // optional explicit "ms-seq" ID, default auto
+		MaxLen int64             `json:"maxlen"` // optional cap applied after adding
+		Approx bool              `json:"approx"` // use MAXLEN ~ for efficient trimming
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -1561,6 +2718,16 @@ func (h *Handler) handleStreamAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if body.MaxLen < 0 {
+		jsonError(w, "maxlen must be positive", http.StatusBadRequest)
+		return
+	}
+
+	if body.ID != "" && body.ID != "*" && !streamEntryIDPattern.MatchString(body.ID) {
+		jsonError(w, "Invalid stream ID (expected <ms>-<seq>)", http.StatusBadRequest)
+		return
+	}
+
 	// Validate field names and values are non-empty
 	for field, value := range body.Fields {
 		if field == "" {
@@ -1571,11 +2738,19 @@ func (h *Handler) handleStreamAdd(w http.ResponseWriter, r *http.Request) {
 			jsonError(w, "Field value cannot be empty", http.StatusBadRequest)
 			return
 		}
+		if h.checkValueSize(w, field, value) {
+			return
+		}
 	}
 
-	id, err := h.client.XAddMulti(r.Context(), key, body.Fields)
+	id, err := h.client.XAddMulti(r.Context(), key, body.Fields, valkey.XAddOptions{ID: body.ID, MaxLen: body.MaxLen, Approx: body.Approx})
 	if err != nil {
-		internalError(w, err)
+		if strings.Contains(err.Error(), "equal or smaller than the target stream top item") ||
+			strings.Contains(err.Error(), "must be greater than 0-0") {
+			jsonError(w, "Stream ID must be greater than the last entry ID", http.StatusConflict)
+			return
+		}
+		errorResponse(w, err)
 		return
 	}
 
@@ -1583,7 +2758,7 @@ func (h *Handler) handleStreamAdd(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) handleStreamRemove(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1600,7 +2775,7 @@ func (h *Handler) handleStreamRemove(w http.ResponseWriter, r *http.Request) {
 
 	deleted, err := h.client.XDel(r.Context(), key, id)
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
@@ -1612,10 +2787,247 @@ func (h *Handler) handleStreamRemove(w http.ResponseWriter, r *http.Request) {
 	jsonResponse(w, map[string]string{"status": "ok"})
 }
 
+// streamEntryIDPattern matches a complete stream entry ID ("1700000000000-0")
+var streamEntryIDPattern = regexp.MustCompile(`^\d+-\d+$`)
+
+// streamIDPattern matches a full or partial stream ID ("1700000000000-0" or "1700000000000")
+var streamIDPattern = regexp.MustCompile(`^\d+(-\d+)?$`)
+
+func (h *Handler) handleStreamTrim(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	var body struct {
+		Strategy  string `json:"strategy"`  // "maxlen" or "minid"
+		Threshold string `json:"threshold"` // max length, or minimum ID to keep
+		Approx    bool   `json:"approx"`    // use ~ for efficient approximate trimming
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	switch strings.ToLower(body.Strategy) {
+	case "maxlen":
+		if n, err := strconv.ParseInt(body.Threshold, 10, 64); err != nil || n < 0 {
+			jsonError(w, "MAXLEN threshold must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+	case "minid":
+		if !streamIDPattern.MatchString(body.Threshold) {
+			jsonError(w, "MINID threshold must be a stream ID", http.StatusBadRequest)
+			return
+		}
+	default:
+		jsonError(w, "Strategy must be maxlen or minid", http.StatusBadRequest)
+		return
+	}
+
+	removed, err := h.client.XTrim(r.Context(), key, body.Strategy, body.Threshold, body.Approx)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{
+		"status":  "ok",
+		"removed": removed,
+	})
+}
+
+func (h *Handler) handleStreamGroups(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	ctx := r.Context()
+
+	keyType, err := h.client.Type(ctx, key)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	if keyType == "none" {
+		jsonError(w, "Key not found", http.StatusNotFound)
+		return
+	}
+	if keyType != "stream" {
+		jsonError(w, "Key is not a stream", http.StatusBadRequest)
+		return
+	}
+
+	groups, err := h.client.XInfoGroups(ctx, key)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	type groupInfo struct {
+		valkey.StreamGroup
+		PendingMinID string `json:"pendingMinId,omitempty"`
+		PendingMaxID string `json:"pendingMaxId,omitempty"`
+	}
+
+	result := make([]groupInfo, 0, len(groups))
+	for _, g := range groups {
+		consumers, err := h.client.XInfoConsumers(ctx, key, g.Name)
+		if err != nil {
+			errorResponse(w, err)
+			return
+		}
+		g.Consumers = consumers
+
+		info := groupInfo{StreamGroup: g}
+		if g.Pending > 0 {
+			summary, err := h.client.XPending(ctx, key, g.Name)
+			if err != nil {
+				errorResponse(w, err)
+				return
+			}
+			info.PendingMinID = summary.MinID
+			info.PendingMaxID = summary.MaxID
+		}
+		result = append(result, info)
+	}
+
+	jsonResponse(w, map[string]any{
+		"key":    key,
+		"groups": result,
+	})
+}
+
+func (h *Handler) handleStreamGroupCreate(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	var body struct {
+		Group    string `json:"group"`
+		ID       string `json:"id"`       // start ID, "$" (default) = only new entries, "0" = all
+		MkStream bool   `json:"mkstream"` // create the stream if it doesn't exist
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	body.Group = strings.TrimSpace(body.Group)
+	if body.Group == "" {
+		jsonError(w, "Group name cannot be empty", http.StatusBadRequest)
+		return
+	}
+	if body.ID == "" {
+		body.ID = "$"
+	}
+
+	if err := h.client.XGroupCreate(r.Context(), key, body.Group, body.ID, body.MkStream); err != nil {
+		msg := err.Error()
+		switch {
+		case strings.HasPrefix(msg, "BUSYGROUP"):
+			jsonError(w, "Group already exists", http.StatusConflict)
+		case strings.Contains(msg, "requires the key to exist"):
+			jsonError(w, "Stream does not exist (set mkstream to create it)", http.StatusNotFound)
+		case strings.Contains(msg, "Invalid stream ID"):
+			jsonError(w, "Invalid start ID", http.StatusBadRequest)
+		default:
+			errorResponse(w, err)
+		}
+		return
+	}
+
+	jsonResponse(w, map[string]string{"status": "ok"})
+}
+
+func (h *Handler) handleStreamGroupDestroy(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	group := r.PathValue("group")
+	if group == "" {
+		jsonError(w, "Group name cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	destroyed, err := h.client.XGroupDestroy(r.Context(), key, group)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	if !destroyed {
+		jsonError(w, "Group not found", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, map[string]string{"status": "ok"})
+}
+
+func (h *Handler) handleStreamAck(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	group := r.PathValue("group")
+	if group == "" {
+		jsonError(w, "Group name cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		IDs []string `json:"ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(body.IDs) == 0 {
+		jsonError(w, "At least one entry ID is required", http.StatusBadRequest)
+		return
+	}
+
+	acked, err := h.client.XAck(r.Context(), key, group, body.IDs...)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{
+		"status": "ok",
+		"acked":  acked,
+	})
+}
+
 // HyperLogLog operation handlers
 
 func (h *Handler) handleHLLAdd(w http.ResponseWriter, r *http.Request) {
-	if h.checkReadOnly(w) {
+	if h.checkReadOnly(w, r) {
 		return
 	}
 
@@ -1625,7 +3037,8 @@ func (h *Handler) handleHLLAdd(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var body struct {
-		Element string `json:"element"`
+		Element  string   `json:"element"`
+		Elements []string `json:"elements"` // batch form, added in a single PFADD
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -1633,22 +3046,332 @@ func (h *Handler) handleHLLAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if body.Element == "" {
+	elements := body.Elements
+	if body.Element != "" {
+		elements = append(elements, body.Element)
+	}
+
+	if len(elements) == 0 {
 		jsonError(w, "Element cannot be empty", http.StatusBadRequest)
 		return
 	}
+	for _, e := range elements {
+		if e == "" {
+			jsonError(w, "Element cannot be empty", http.StatusBadRequest)
+			return
+		}
+	}
+	if h.checkValueSize(w, elements...) {
+		return
+	}
 
-	if err := h.client.PFAdd(r.Context(), key, body.Element); err != nil {
-		internalError(w, err)
+	ctx := r.Context()
+	if err := h.client.PFAdd(ctx, key, elements...); err != nil {
+		errorResponse(w, err)
 		return
 	}
 
-	jsonResponse(w, map[string]string{"status": "ok"})
+	count, err := h.client.PFCount(ctx, key)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{"status": "ok", "added": len(elements), "count": count})
+}
+
+// handleHLLMerge unions other HyperLogLogs into the key with PFMERGE
+func (h *Handler) handleHLLMerge(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	var body struct {
+		Sources []string `json:"sources"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(body.Sources) == 0 {
+		jsonError(w, "At least one source is required", http.StatusBadRequest)
+		return
+	}
+	if len(body.Sources) > maxSetOpKeys {
+		jsonError(w, fmt.Sprintf("Too many sources (max %d)", maxSetOpKeys), http.StatusBadRequest)
+		return
+	}
+
+	for _, source := range body.Sources {
+		if h.checkKeyPrefix(w, source) {
+			return
+		}
+	}
+
+	count, err := h.client.PFMerge(r.Context(), key, body.Sources...)
+	if err != nil {
+		if valkey.IsWrongType(err) {
+			jsonError(w, "All keys must be HyperLogLogs", http.StatusBadRequest)
+			return
+		}
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{"status": "ok", "count": count})
+}
+
+// Bitmap operation handlers
+
+const (
+	maxBitmapBytes   = 64 << 10 // bytes scanned for set-bit indices
+	maxBitmapIndices = 10000    // set-bit indices returned
+)
+
+// setBitIndices returns the offsets of set bits in data, most significant bit first
+// as SETBIT/GETBIT address them, stopping after limit indices
+func setBitIndices(data string, limit int) ([]int64, bool) {
+	indices := make([]int64, 0)
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		if b == 0 {
+			continue
+		}
+		for bit := 0; bit < 8; bit++ {
+			if b&(0x80>>bit) != 0 {
+				if len(indices) >= limit {
+					return indices, true
+				}
+				indices = append(indices, int64(i*8+bit))
+			}
+		}
+	}
+	return indices, false
+}
+
+func (h *Handler) handleBitmapGet(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	ctx := r.Context()
+
+	keyType, err := h.client.Type(ctx, key)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	if keyType == "none" {
+		jsonError(w, "Key not found", http.StatusNotFound)
+		return
+	}
+	if keyType != "string" {
+		jsonError(w, "Bitmaps are stored as strings; key is a "+keyType, http.StatusBadRequest)
+		return
+	}
+
+	// Optional byte range for BITCOUNT
+	var byteRange *[2]int64
+	startStr, endStr := r.URL.Query().Get("start"), r.URL.Query().Get("end")
+	if startStr != "" || endStr != "" {
+		start, err1 := strconv.ParseInt(startStr, 10, 64)
+		end, err2 := strconv.ParseInt(endStr, 10, 64)
+		if err1 != nil || err2 != nil {
+			jsonError(w, "start and end must both be integers", http.StatusBadRequest)
+			return
+		}
+		byteRange = &[2]int64{start, end}
+	}
+
+	count, err := h.client.BitCount(ctx, key, byteRange)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	length, err := h.client.StrLen(ctx, key)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	// Only scan a bounded prefix of the bitmap for set-bit indices
+	data, err := h.client.GetRange(ctx, key, 0, maxBitmapBytes-1)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	bits, truncated := setBitIndices(data, maxBitmapIndices)
+	if length > maxBitmapBytes {
+		truncated = true
+	}
+
+	jsonResponse(w, map[string]any{
+		"key":       key,
+		"count":     count,
+		"bytes":     length,
+		"bits":      bits,
+		"truncated": truncated,
+	})
+}
+
+func (h *Handler) handleBitmapSet(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	var body struct {
+		Offset int64 `json:"offset"`
+		Value  int64 `json:"value"` // 0 or 1
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Valkey limits bitmaps to 512MB (2^32 bits)
+	if body.Offset < 0 || body.Offset >= 1<<32 {
+		jsonError(w, "Offset must be between 0 and 4294967295", http.StatusBadRequest)
+		return
+	}
+	if body.Value != 0 && body.Value != 1 {
+		jsonError(w, "Value must be 0 or 1", http.StatusBadRequest)
+		return
+	}
+
+	previous, err := h.client.SetBit(r.Context(), key, body.Offset, body.Value)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	count, err := h.client.BitCount(r.Context(), key, nil)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{
+		"status":   "ok",
+		"previous": previous,
+		"count":    count,
+	})
+}
+
+var (
+	bitFieldEncoding = regexp.MustCompile(`^[iu]\d+$`)
+	bitFieldOffset   = regexp.MustCompile(`^#?\d+$`)
+)
+
+// validateBitFieldOps checks each BITFIELD operation and reports whether any of them write
+func validateBitFieldOps(ops []string) (bool, error) {
+	write := false
+	for _, op := range ops {
+		parts := strings.Fields(op)
+		if len(parts) == 0 {
+			return false, fmt.Errorf("empty operation")
+		}
+
+		switch strings.ToUpper(parts[0]) {
+		case "GET":
+			if len(parts) != 3 {
+				return false, fmt.Errorf("GET takes an encoding and an offset: %q", op)
+			}
+		case "SET", "INCRBY":
+			if len(parts) != 4 {
+				return false, fmt.Errorf("%s takes an encoding, an offset, and a value: %q", strings.ToUpper(parts[0]), op)
+			}
+			if _, err := strconv.ParseInt(parts[3], 10, 64); err != nil {
+				return false, fmt.Errorf("invalid value in %q", op)
+			}
+			write = true
+		case "OVERFLOW":
+			if len(parts) != 2 {
+				return false, fmt.Errorf("OVERFLOW takes WRAP, SAT, or FAIL: %q", op)
+			}
+			switch strings.ToUpper(parts[1]) {
+			case "WRAP", "SAT", "FAIL":
+			default:
+				return false, fmt.Errorf("OVERFLOW takes WRAP, SAT, or FAIL: %q", op)
+			}
+			continue
+		default:
+			return false, fmt.Errorf("unknown operation: %q", op)
+		}
+
+		if !bitFieldEncoding.MatchString(parts[1]) {
+			return false, fmt.Errorf("invalid encoding in %q (expected e.g. u8 or i16)", op)
+		}
+		if !bitFieldOffset.MatchString(parts[2]) {
+			return false, fmt.Errorf("invalid offset in %q (expected e.g. 0 or #2)", op)
+		}
+	}
+	return write, nil
+}
+
+func (h *Handler) handleBitField(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	var body struct {
+		Ops []string `json:"ops"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(body.Ops) == 0 {
+		jsonError(w, "At least one operation is required", http.StatusBadRequest)
+		return
+	}
+
+	write, err := validateBitFieldOps(body.Ops)
+	if err != nil {
+		jsonError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// GET-only requests are allowed in readonly mode and whatever types are writable
+	if write && (h.checkReadOnly(w, r) || h.checkTypeWritable(w, "string")) {
+		return
+	}
+	if !write {
+		markReadOnly(r)
+	}
+
+	results, err := h.client.BitField(r.Context(), key, body.Ops)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{
+		"results": results,
+	})
 }
 
 // Memory usage handler
 
 func (h *Handler) handleKeysMemory(w http.ResponseWriter, r *http.Request) {
+	markReadOnly(r)
 	var body struct {
 		Keys []string `json:"keys"`
 	}
@@ -1676,7 +3399,7 @@ func (h *Handler) handleKeysMemory(w http.ResponseWriter, r *http.Request) {
 
 	memory, err := h.client.MemoryUsageBatch(r.Context(), body.Keys)
 	if err != nil {
-		internalError(w, err)
+		errorResponse(w, err)
 		return
 	}
 
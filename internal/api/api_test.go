@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+func TestConfigReportsLimits(t *testing.T) {
+	h := New(&config.Config{MaxKeys: 500, MaxValueSize: 1024, EnableMonitor: true}, nil)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/config", nil))
+
+	var got map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got["maxKeys"] != float64(500) || got["maxValueSize"] != float64(1024) {
+		t.Errorf("limits = %v/%v, want 500/1024", got["maxKeys"], got["maxValueSize"])
+	}
+	if got["maxBodySize"] != float64(maxBodySize) {
+		t.Errorf("maxBodySize = %v, want %d", got["maxBodySize"], maxBodySize)
+	}
+	// Monitor without a token can't be used, so it's reported off
+	if got["monitor"] != false {
+		t.Errorf("monitor = %v, want false without a token", got["monitor"])
+	}
+	for _, field := range []string{"readOnly", "prefix", "disableFlush"} {
+		if _, ok := got[field]; !ok {
+			t.Errorf("missing original field %q", field)
+		}
+	}
+}
+
+func TestOpTimeout(t *testing.T) {
+	h := New(&config.Config{OpTimeout: 2}, nil)
+
+	h.mux.HandleFunc("GET /test/deadline", func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		if !ok || time.Until(deadline) > 2*time.Second {
+			t.Errorf("expected a deadline within 2s, got %v (set=%v)", deadline, ok)
+		}
+		<-r.Context().Done()
+		errorResponse(w, r.Context().Err())
+	})
+
+	// Shorten the wait by cancelling through the parent context's deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/test/deadline", nil).WithContext(ctx))
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("expected 504 on timeout, got %d", rec.Code)
+	}
+}
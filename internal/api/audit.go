@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// auditEntry is one line of the audit log
+type auditEntry struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"` // client address; kvweb has no user accounts
+	Method string    `json:"method"`
+	Path   string    `json:"path"`
+	Key    string    `json:"key,omitempty"`
+	Status int       `json:"status"`
+	DryRun bool      `json:"dryRun,omitempty"`
+}
+
+// auditLog appends entries as JSON lines, serializing concurrent writers
+type auditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (a *auditLog) write(e auditEntry) {
+	line, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("Audit log: %v", err)
+		return
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.w.Write(line); err != nil {
+		log.Printf("Audit log write failed: %v", err)
+	}
+}
+
+// SetAuditLog records every successful mutating request to w as JSON lines
+func (h *Handler) SetAuditLog(w io.Writer) {
+	h.audit = &auditLog{w: w}
+}
+
+// statusRecorder captures the response status for the audit log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// readOnlySlotKey carries a *bool through the request context so handlers of
+// non-GET routes can report that this particular request only read data
+type readOnlySlotKey struct{}
+
+// markReadOnly tells serveWrite that the current request changed nothing, so
+// it is neither audited nor journaled and cached metadata is kept
+func markReadOnly(r *http.Request) {
+	if slot, ok := r.Context().Value(readOnlySlotKey{}).(*bool); ok {
+		*slot = true
+	}
+}
+
+// serveWrite runs a non-GET request and, if it changed data successfully,
+// records it in the audit log and the undo history. The mux fills in
+// r.Pattern and path values, so they are read afterwards.
+func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request) {
+	var undo *undoStep
+	var readOnly bool
+	ctx := context.WithValue(r.Context(), undoSlotKey{}, &undo)
+	r = r.WithContext(context.WithValue(ctx, readOnlySlotKey{}, &readOnly))
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	h.mux.ServeHTTP(rec, r)
+
+	if rec.status >= 300 || readOnly {
+		return
+	}
+	now := time.Now().UTC()
+	key := r.PathValue("key")
+
+	// Writes naming other keys in the body (bulk delete, rename target,
+	// transactions, import) can touch anything, so drop all cached metadata
+	if h.metaCache != nil {
+		if key == "" || r.Pattern == "POST /api/key/{key}/rename" {
+			h.metaCache.clear()
+		} else {
+			h.metaCache.remove(key)
+		}
+	}
+
+	// Dry runs changed nothing, so there is nothing to undo
+	if !h.cfg.DryRun {
+		h.history.add(&historyEntry{Time: now, Method: r.Method, Path: r.URL.Path, Key: key, undo: undo})
+	}
+
+	if h.audit == nil {
+		return
+	}
+	h.audit.write(auditEntry{
+		Time:   now,
+		Actor:  h.cfg.ClientIP(r),
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Key:    key,
+		Status: rec.status,
+		DryRun: h.cfg.DryRun,
+	})
+}
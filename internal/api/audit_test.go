@@ -0,0 +1,84 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+func TestAuditLog(t *testing.T) {
+	// Dry-run lets writes succeed without a Valkey connection
+	h := New(&config.Config{DryRun: true}, nil)
+	var buf bytes.Buffer
+	h.SetAuditLog(&buf)
+
+	req := httptest.NewRequest("PUT", "/api/key/user:1", strings.NewReader(`{"value":"x"}`))
+	req.RemoteAddr = "10.0.0.7:51234"
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry auditEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid audit line %q: %v", buf.String(), err)
+	}
+	if entry.Actor != "10.0.0.7" || entry.Method != "PUT" || entry.Key != "user:1" || entry.Status != 200 || !entry.DryRun {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if entry.Time.IsZero() {
+		t.Error("expected a timestamp")
+	}
+
+	t.Run("reads are not logged", func(t *testing.T) {
+		buf.Reset()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/config", nil))
+		if buf.Len() != 0 {
+			t.Errorf("expected no entry, got %q", buf.String())
+		}
+	})
+}
+
+func TestAuditLogSkipsRejected(t *testing.T) {
+	h := New(&config.Config{ReadOnly: true}, nil)
+	var buf bytes.Buffer
+	h.SetAuditLog(&buf)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/api/key/foo", nil))
+	if buf.Len() != 0 {
+		t.Errorf("expected rejected write not to be logged, got %q", buf.String())
+	}
+}
+
+func TestAuditLogSkipsReadOnlyRequests(t *testing.T) {
+	h := New(&config.Config{}, nil)
+	var buf bytes.Buffer
+	h.SetAuditLog(&buf)
+
+	// Stand-ins for POST routes that decide per request whether they wrote
+	h.mux.HandleFunc("POST /api/test/read", func(w http.ResponseWriter, r *http.Request) {
+		markReadOnly(r)
+		jsonResponse(w, map[string]string{"status": "ok"})
+	})
+	h.mux.HandleFunc("POST /api/test/write", func(w http.ResponseWriter, r *http.Request) {
+		jsonResponse(w, map[string]string{"status": "ok"})
+	})
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/test/read", nil))
+	if buf.Len() != 0 {
+		t.Errorf("expected read-only request not to be logged, got %q", buf.String())
+	}
+	if entries := h.history.list(); len(entries) != 0 {
+		t.Errorf("expected read-only request not to be journaled, got %d entries", len(entries))
+	}
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/test/write", nil))
+	if buf.Len() == 0 {
+		t.Error("expected the write to be logged")
+	}
+	if entries := h.history.list(); len(entries) != 1 {
+		t.Errorf("expected the write to be journaled, got %d entries", len(entries))
+	}
+}
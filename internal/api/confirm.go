@@ -0,0 +1,31 @@
+package api
+
+import "net/http"
+
+// confirmHeader must be set to "yes" on destructive requests when RequireConfirm is enabled
+const confirmHeader = "X-Kvweb-Confirm"
+
+// confirmed reports whether the request carries the explicit confirmation header
+func confirmed(r *http.Request) bool {
+	return r.Header.Get(confirmHeader) == "yes"
+}
+
+// checkConfirm returns true and sends a 428 response if confirmation is required but missing
+func (h *Handler) checkConfirm(w http.ResponseWriter, r *http.Request) bool {
+	if h.cfg.RequireConfirm && !confirmed(r) {
+		jsonError(w, "Destructive operation requires header "+confirmHeader+": yes", http.StatusPreconditionRequired)
+		return true
+	}
+	return false
+}
+
+// requireConfirm wraps a destructive handler so it is rejected with 428
+// unless the request is explicitly confirmed
+func (h *Handler) requireConfirm(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.checkConfirm(w, r) {
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+func TestRequireConfirm(t *testing.T) {
+	// Readonly makes confirmed requests stop at the readonly check,
+	// so no Valkey connection is needed
+	h := New(&config.Config{RequireConfirm: true, ReadOnly: true}, nil)
+
+	destructive := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"delete key", "DELETE", "/api/key/foo", ""},
+		{"bulk delete", "POST", "/api/keys/delete", `{"keys":["foo"]}`},
+		{"flush", "POST", "/api/flush", ""},
+		{"console del", "POST", "/api/exec", `{"command":"DEL foo"}`},
+		{"console unlink", "POST", "/api/exec", `{"command":"unlink foo bar"}`},
+		{"console flushdb", "POST", "/api/exec", `{"command":"FLUSHDB"}`},
+		{"console flushall", "POST", "/api/exec", `{"command":"FLUSHALL"}`},
+		{"import overwrite", "POST", "/api/import?mode=overwrite", ""},
+		{"transaction del", "POST", "/api/transaction", `{"ops":[{"op":"set","key":"a","value":"1"},{"op":"del","key":"b"}]}`},
+	}
+
+	for _, tt := range destructive {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if rec.Code != http.StatusPreconditionRequired {
+				t.Errorf("expected 428 without confirmation, got %d", rec.Code)
+			}
+
+			rec = httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(confirmHeader, "yes")
+			h.ServeHTTP(rec, req)
+			if rec.Code == http.StatusPreconditionRequired {
+				t.Error("expected confirmed request to pass the confirmation check")
+			}
+
+			rec = httptest.NewRecorder()
+			req = httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(confirmHeader, "no")
+			h.ServeHTTP(rec, req)
+			if rec.Code != http.StatusPreconditionRequired {
+				t.Errorf("expected 428 for non-yes confirmation, got %d", rec.Code)
+			}
+		})
+	}
+
+	t.Run("reads need no confirmation", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/config", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected 200, got %d", rec.Code)
+		}
+	})
+
+	// Stopped by the readonly check rather than the confirmation check
+	nonDestructive := []struct {
+		name, path, body string
+	}{
+		{"console write", "/api/exec", `{"command":"SET foo bar"}`},
+		{"import skip", "/api/import", ""},
+		{"import merge", "/api/import?mode=merge", ""},
+		{"transaction without del", "/api/transaction", `{"ops":[{"op":"set","key":"a","value":"1"}]}`},
+	}
+	for _, tt := range nonDestructive {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body)))
+			if rec.Code == http.StatusPreconditionRequired {
+				t.Error("expected no confirmation requirement")
+			}
+		})
+	}
+}
+
+func TestRequireConfirmDisabled(t *testing.T) {
+	h := New(&config.Config{ReadOnly: true}, nil)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/key/foo", nil))
+	if rec.Code == http.StatusPreconditionRequired {
+		t.Error("expected no confirmation requirement when disabled")
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+func TestDryRun(t *testing.T) {
+	// A nil client panics on use, so any write reaching Valkey fails the test
+	h := New(&config.Config{DryRun: true}, nil)
+
+	writes := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"set key", "PUT", "/api/key/foo", `{"value":"bar"}`},
+		{"delete key", "DELETE", "/api/key/foo", ""},
+		{"flush", "POST", "/api/flush", ""},
+		{"exec write", "POST", "/api/exec", `{"command":"SET foo bar"}`},
+	}
+
+	for _, tt := range writes {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			var resp map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid JSON response: %v", err)
+			}
+			if resp["dryRun"] != true {
+				t.Errorf("expected dryRun true, got %v", resp)
+			}
+		})
+	}
+}
+
+func TestDryRunReadOnlyWins(t *testing.T) {
+	h := New(&config.Config{DryRun: true, ReadOnly: true}, nil)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/key/foo", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 in readonly mode, got %d", rec.Code)
+	}
+}
+
+func TestIsReadOnlyCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"GET", "foo"}, true},
+		{[]string{"get", "foo"}, true},
+		{[]string{"SET", "foo", "bar"}, false},
+		{[]string{"DEL", "foo"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := isReadOnlyCommand(tt.args); got != tt.want {
+			t.Errorf("isReadOnlyCommand(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
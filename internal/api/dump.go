@@ -0,0 +1,188 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+// handleDump returns the key's DUMP payload (base64) and its remaining TTL in ms.
+// The payload keeps the internal encoding, so a RESTORE elsewhere is byte-exact.
+func (h *Handler) handleDump(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	ctx := r.Context()
+
+	payload, err := h.client.Dump(ctx, key)
+	if err != nil {
+		if valkey.IsNil(err) {
+			jsonError(w, "Key not found", http.StatusNotFound)
+			return
+		}
+		errorResponse(w, err)
+		return
+	}
+
+	ttl, _ := h.client.PTTL(ctx, key)
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	jsonResponse(w, map[string]any{
+		"key":     key,
+		"payload": base64.StdEncoding.EncodeToString([]byte(payload)),
+		"ttl":     ttl,
+	})
+}
+
+// handleRestore creates a key from a base64 DUMP payload. An existing key is a 409
+// unless replace is set, which counts as destructive for --require-confirm-header.
+func (h *Handler) handleRestore(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	var body struct {
+		Payload string `json:"payload"`
+		TTL     int64  `json:"ttl"` // milliseconds, 0 = no expiry
+		Replace bool   `json:"replace"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(body.Payload)
+	if err != nil || len(payload) == 0 {
+		jsonError(w, "payload must be non-empty base64", http.StatusBadRequest)
+		return
+	}
+	if h.checkValueSize(w, string(payload)) {
+		return
+	}
+	if body.TTL < 0 {
+		jsonError(w, "ttl cannot be negative", http.StatusBadRequest)
+		return
+	}
+	if body.Replace && h.checkConfirm(w, r) {
+		return
+	}
+
+	err = h.client.Restore(r.Context(), key, time.Duration(body.TTL)*time.Millisecond, string(payload), body.Replace)
+	if err != nil {
+		msg := err.Error()
+		switch {
+		case strings.HasPrefix(msg, "BUSYKEY"):
+			jsonError(w, "Key already exists", http.StatusConflict)
+		case strings.Contains(msg, "payload version or checksum"):
+			jsonError(w, "Invalid or incompatible DUMP payload", http.StatusBadRequest)
+		default:
+			errorResponse(w, err)
+		}
+		return
+	}
+
+	jsonResponse(w, map[string]string{"status": "ok"})
+}
+
+// maxMigrateTimeout bounds how long the source server may wait on the target
+const maxMigrateTimeout = 60 * time.Second
+
+// handleMigrate sends the key to another server with MIGRATE. Without copy the source
+// key is deleted, so that (and replace) counts as destructive for --require-confirm-header.
+func (h *Handler) handleMigrate(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	var body struct {
+		Host     string `json:"host"`
+		Port     int    `json:"port"`
+		DB       int    `json:"db"`
+		Password string `json:"password"`
+		Timeout  int64  `json:"timeout"` // milliseconds, default 5000
+		Copy     bool   `json:"copy"`
+		Replace  bool   `json:"replace"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if body.Host == "" {
+		jsonError(w, "host is required", http.StatusBadRequest)
+		return
+	}
+	if body.Port == 0 {
+		body.Port = 6379
+	}
+	if body.Port < 1 || body.Port > 65535 {
+		jsonError(w, "port must be between 1 and 65535", http.StatusBadRequest)
+		return
+	}
+	if body.DB < 0 {
+		jsonError(w, "db cannot be negative", http.StatusBadRequest)
+		return
+	}
+	timeout := 5 * time.Second
+	if body.Timeout != 0 {
+		timeout = time.Duration(body.Timeout) * time.Millisecond
+		if timeout <= 0 || timeout > maxMigrateTimeout {
+			jsonError(w, "timeout must be between 1 and 60000 ms", http.StatusBadRequest)
+			return
+		}
+	}
+	if (!body.Copy || body.Replace) && h.checkConfirm(w, r) {
+		return
+	}
+
+	result, err := h.client.Migrate(r.Context(), key, valkey.MigrateTarget{
+		Host:     body.Host,
+		Port:     body.Port,
+		DB:       body.DB,
+		Password: body.Password,
+		Timeout:  timeout,
+		Copy:     body.Copy,
+		Replace:  body.Replace,
+	})
+	if err != nil {
+		msg := err.Error()
+		switch {
+		case strings.Contains(msg, "BUSYKEY"):
+			jsonError(w, "Key already exists on the target", http.StatusConflict)
+		case strings.HasPrefix(msg, "IOERR"):
+			jsonError(w, "Could not reach the target server (timed out or connection refused)", http.StatusBadGateway)
+		case strings.Contains(msg, "Target instance replied with error"):
+			jsonError(w, msg, http.StatusBadGateway)
+		default:
+			errorResponse(w, err)
+		}
+		return
+	}
+
+	if result == "NOKEY" {
+		jsonError(w, "Key not found", http.StatusNotFound)
+		return
+	}
+
+	jsonResponse(w, map[string]any{"status": "ok", "copied": body.Copy})
+}
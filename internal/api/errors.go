@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+// serverErrors maps the error code a Valkey reply starts with to the status and
+// message the client gets. Anything not listed is a 500.
+var serverErrors = map[string]struct {
+	status  int
+	message string
+}{
+	// The request doesn't fit the data; retrying won't help
+	"WRONGTYPE": {http.StatusConflict, "Operation not valid for this key's type"},
+	"CROSSSLOT": {http.StatusBadRequest, "Keys must hash to the same cluster slot (use a {hash tag})"},
+
+	// kvweb's connection isn't allowed to do this
+	"NOAUTH":    {http.StatusForbidden, "Server requires authentication"},
+	"WRONGPASS": {http.StatusForbidden, "Server rejected the credentials"},
+	"NOPERM":    {http.StatusForbidden, "Server ACL does not permit this command or key"},
+	"READONLY":  {http.StatusForbidden, "Server is a read-only replica"},
+
+	// The server can't serve it right now; a retry may succeed
+	"OOM":         {http.StatusServiceUnavailable, "Server is out of memory (maxmemory reached)"},
+	"LOADING":     {http.StatusServiceUnavailable, "Server is loading its dataset"},
+	"BUSY":        {http.StatusServiceUnavailable, "Server is busy running a script"},
+	"MASTERDOWN":  {http.StatusServiceUnavailable, "Replica has lost its primary"},
+	"NOSCRIPT":    {http.StatusServiceUnavailable, "Script cache was flushed; retry"},
+	"MOVED":       {http.StatusServiceUnavailable, "Key is served by another cluster node"},
+	"ASK":         {http.StatusServiceUnavailable, "Key is migrating between cluster nodes"},
+	"TRYAGAIN":    {http.StatusServiceUnavailable, "Cluster is resharding; retry"},
+	"CLUSTERDOWN": {http.StatusServiceUnavailable, "Cluster is down"},
+}
+
+// errorResponse reports a failed Valkey call. Known server error codes map to
+// 403/409/503 with a readable message; timeouts are 504. Anything else is
+// logged server-side and returned as a generic 500.
+func errorResponse(w http.ResponseWriter, err error) {
+	status, message := mapError(err)
+	if status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", "1")
+	}
+	jsonError(w, message, status)
+}
+
+// mapError picks the status and client-facing message for a failed Valkey
+// call, logging the ones whose details the client doesn't get
+func mapError(err error) (int, string) {
+	// Script errors wrap the command's reply, so WRONGTYPE may be mid-message
+	code := "WRONGTYPE"
+	if !valkey.IsWrongType(err) {
+		code, _, _ = strings.Cut(err.Error(), " ")
+	}
+	if mapped, ok := serverErrors[code]; ok {
+		if mapped.status == http.StatusServiceUnavailable {
+			log.Printf("Error: %v", err)
+		}
+		return mapped.status, mapped.message
+	}
+
+	log.Printf("Error: %v", err)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, "Operation timed out"
+	}
+	return http.StatusInternalServerError, "Internal server error"
+}
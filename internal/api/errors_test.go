@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+func TestListIndexError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"remove out of range", valkey.ErrIndexOutOfRange, http.StatusBadRequest},
+		{"set out of range", errors.New("ERR index out of range"), http.StatusBadRequest},
+		{"set missing key", errors.New("ERR no such key"), http.StatusNotFound},
+		{"other", fmt.Errorf("failed to remove list element at index %d", -1), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			listIndexError(rec, tt.err)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	tests := []struct {
+		err        string
+		want       int
+		retryAfter bool
+	}{
+		{"WRONGTYPE Operation against a key holding the wrong kind of value", http.StatusConflict, false},
+		{"ERR Error running script: WRONGTYPE Operation against a key", http.StatusConflict, false},
+		{"NOAUTH Authentication required.", http.StatusForbidden, false},
+		{"NOPERM User default has no permissions to run the 'set' command", http.StatusForbidden, false},
+		{"READONLY You can't write against a read only replica.", http.StatusForbidden, false},
+		{"OOM command not allowed when used memory > 'maxmemory'.", http.StatusServiceUnavailable, true},
+		{"LOADING Valkey is loading the dataset in memory", http.StatusServiceUnavailable, true},
+		{"MOVED 3999 127.0.0.1:6381", http.StatusServiceUnavailable, true},
+		{valkey.ErrCrossSlot.Error(), http.StatusBadRequest, false},
+		{"ERR syntax error", http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			errorResponse(rec, errors.New(tt.err))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if got := rec.Header().Get("Retry-After") != ""; got != tt.retryAfter {
+				t.Errorf("Retry-After set = %v, want %v", got, tt.retryAfter)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	errorResponse(rec, fmt.Errorf("scan: %w", context.DeadlineExceeded))
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("deadline: status = %d, want 504", rec.Code)
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -44,6 +45,11 @@ func (h *Handler) handleExec(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Deleting keys from the console needs the same confirmation as the delete routes
+	if destructiveCommands[cmd] && h.checkConfirm(w, r) {
+		return
+	}
+
 	// Readonly mode: only allow known read-only commands
 	if h.cfg.ReadOnly {
 		if !readOnlyCommands[cmd] {
@@ -60,16 +66,37 @@ func (h *Handler) handleExec(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Per-type write limits can't be checked for arbitrary commands
+	if len(h.cfg.WritableTypes) > 0 && !isReadOnlyCommand(args) {
+		jsonError(w, "Console writes are disabled while only some types are writable", http.StatusForbidden)
+		return
+	}
+
+	if isReadOnlyCommand(args) {
+		markReadOnly(r)
+	}
+
+	if !isReadOnlyCommand(args) && h.checkValueSize(w, args[1:]...) {
+		return
+	}
+
+	// Dry-run mode: reads still run, anything else is only logged
+	if h.cfg.DryRun && !isReadOnlyCommand(args) {
+		log.Printf("Dry run: EXEC %s", body.Command)
+		jsonResponse(w, map[string]any{"dryRun": true})
+		return
+	}
+
 	// FLUSHDB/FLUSHALL blocked when DisableFlush is set
 	if h.cfg.DisableFlush && (cmd == "FLUSHDB" || cmd == "FLUSHALL") {
 		jsonError(w, "FLUSHDB/FLUSHALL is disabled", http.StatusForbidden)
 		return
 	}
 
-	// Prefix enforcement: check key arguments
-	if h.cfg.Prefix != "" {
-		if !checkPrefixArgs(cmd, args, h.cfg.Prefix) {
-			jsonError(w, "Key does not match required prefix: "+h.cfg.Prefix, http.StatusForbidden)
+	// Prefix and allow-pattern enforcement: check key arguments
+	if h.cfg.KeyScoped() {
+		if msg := h.keyArgsError(cmd, args); msg != "" {
+			jsonError(w, msg, http.StatusForbidden)
 			return
 		}
 	}
@@ -159,15 +186,18 @@ func formatResult(v any) map[string]any {
 	}
 }
 
-// checkPrefixArgs validates that key arguments match the required prefix.
-func checkPrefixArgs(cmd string, args []string, prefix string) bool {
-	positions := keyPositions(cmd, len(args))
-	for _, pos := range positions {
-		if pos < len(args) && !strings.HasPrefix(args[pos], prefix) {
-			return false
+// keyArgsError validates that key arguments are within the configured scope,
+// returning an error message naming the first one that isn't.
+func (h *Handler) keyArgsError(cmd string, args []string) string {
+	for _, pos := range keyPositions(cmd, len(args)) {
+		if pos >= len(args) {
+			continue
+		}
+		if msg := h.keyScopeError(args[pos]); msg != "" {
+			return msg + ": " + args[pos]
 		}
 	}
-	return true
+	return ""
 }
 
 // keyPositions returns the argument indices (0-based) that are key arguments for the given command.
@@ -237,6 +267,11 @@ var blockedSubcommands = map[string]map[string]bool{
 	"ACL":    {"SETUSER": true, "DELUSER": true, "SAVE": true, "LOAD": true},
 }
 
+// destructiveCommands delete keys, so they need --require-confirm-header's confirmation.
+var destructiveCommands = map[string]bool{
+	"DEL": true, "UNLINK": true, "FLUSHDB": true, "FLUSHALL": true,
+}
+
 // readOnlyCommands are commands allowed in readonly mode.
 var readOnlyCommands = map[string]bool{
 	// Generic
@@ -291,3 +326,15 @@ var readOnlySubcommands = map[string]map[string]bool{
 	"COMMAND": {"COUNT": true, "DOCS": true, "INFO": true, "LIST": true, "GETKEYS": true},
 	"XINFO":   {"STREAM": true, "GROUPS": true, "CONSUMERS": true},
 }
+
+// isReadOnlyCommand reports whether args is a command (and subcommand) allowed in readonly mode
+func isReadOnlyCommand(args []string) bool {
+	cmd := strings.ToUpper(args[0])
+	if !readOnlyCommands[cmd] {
+		return false
+	}
+	if subs, ok := readOnlySubcommands[cmd]; ok && len(args) > 1 {
+		return subs[strings.ToUpper(args[1])]
+	}
+	return true
+}
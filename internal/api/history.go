@@ -0,0 +1,241 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+// historySize is how many recent writes the undo journal keeps
+const historySize = 100
+
+// maxUndoValueSize caps string values kept for undo; larger overwrites aren't undoable
+const maxUndoValueSize = 1 << 20
+
+// undoStep holds the state a write replaced, enough to put it back.
+// Only string set/delete and hash/zset field edits capture one.
+type undoStep struct {
+	Type   string              // "string", "hash" or "zset"
+	Value  *string             // previous string value (nil = key didn't exist)
+	TTL    time.Duration       // previous string TTL (0 = none)
+	Fields map[string]*string  // previous hash field values (nil = field didn't exist)
+	Scores map[string]*float64 // previous zset scores (nil = member didn't exist)
+}
+
+// historyEntry is one journaled write
+type historyEntry struct {
+	ID       int64     `json:"id"`
+	Time     time.Time `json:"time"`
+	Method   string    `json:"method"`
+	Path     string    `json:"path"`
+	Key      string    `json:"key,omitempty"`
+	Undoable bool      `json:"undoable"`
+	Undone   bool      `json:"undone"`
+	undo     *undoStep
+}
+
+// history is a fixed-size ring of recent writes, oldest dropped first
+type history struct {
+	mu      sync.Mutex
+	entries []*historyEntry
+	nextID  int64
+}
+
+func (hs *history) add(e *historyEntry) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	hs.nextID++
+	e.ID = hs.nextID
+	e.Undoable = e.undo != nil
+	if len(hs.entries) == historySize {
+		hs.entries = hs.entries[1:]
+	}
+	hs.entries = append(hs.entries, e)
+}
+
+// list returns copies of the entries, newest first
+func (hs *history) list() []historyEntry {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	result := make([]historyEntry, 0, len(hs.entries))
+	for i := len(hs.entries) - 1; i >= 0; i-- {
+		result = append(result, *hs.entries[i])
+	}
+	return result
+}
+
+// claim marks entry id as undone and returns it, or an HTTP status explaining why it can't be undone
+func (hs *history) claim(id int64) (*historyEntry, int) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	for _, e := range hs.entries {
+		if e.ID != id {
+			continue
+		}
+		if !e.Undoable || e.Undone {
+			return nil, http.StatusConflict
+		}
+		e.Undone = true
+		return e, 0
+	}
+	return nil, http.StatusNotFound
+}
+
+func (hs *history) release(e *historyEntry) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	e.Undone = false
+}
+
+// undoSlotKey carries a **undoStep through the request context so handlers can
+// attach an undo step to the history entry serveWrite records
+type undoSlotKey struct{}
+
+// setUndo attaches step to the current request's history entry (nil = not undoable)
+func setUndo(r *http.Request, step *undoStep) {
+	if slot, ok := r.Context().Value(undoSlotKey{}).(**undoStep); ok {
+		*slot = step
+	}
+}
+
+// captureString snapshots a string key before it is overwritten or deleted.
+// It returns nil (not undoable) for other types or oversized values.
+func (h *Handler) captureString(ctx context.Context, key string) *undoStep {
+	keyType, err := h.client.Type(ctx, key)
+	if err != nil {
+		return nil
+	}
+	switch keyType {
+	case "none":
+		return &undoStep{Type: "string"}
+	case "string":
+	default:
+		return nil
+	}
+
+	value, err := h.client.Get(ctx, key)
+	if err != nil || len(value) > maxUndoValueSize {
+		return nil
+	}
+	pttl, err := h.client.PTTL(ctx, key)
+	if err != nil {
+		return nil
+	}
+	step := &undoStep{Type: "string", Value: &value}
+	if pttl > 0 {
+		step.TTL = time.Duration(pttl) * time.Millisecond
+	}
+	return step
+}
+
+// captureHashFields snapshots hash fields before they are set or removed
+func (h *Handler) captureHashFields(ctx context.Context, key string, fields ...string) *undoStep {
+	values, err := h.client.HMGet(ctx, key, fields...)
+	if err != nil {
+		return nil
+	}
+	step := &undoStep{Type: "hash", Fields: make(map[string]*string, len(fields))}
+	for i, field := range fields {
+		step.Fields[field] = values[i]
+	}
+	return step
+}
+
+// captureZSetScore snapshots a sorted set member's score before it changes
+func (h *Handler) captureZSetScore(ctx context.Context, key, member string) *undoStep {
+	scores, err := h.client.ZMScore(ctx, key, member)
+	if err != nil {
+		return nil
+	}
+	return &undoStep{Type: "zset", Scores: map[string]*float64{member: scores[0]}}
+}
+
+// applyUndo writes the state captured in step back to key
+func applyUndo(ctx context.Context, client *valkey.Client, key string, step *undoStep) error {
+	switch step.Type {
+	case "string":
+		if step.Value == nil {
+			_, err := client.Del(ctx, key)
+			return err
+		}
+		return client.Set(ctx, key, *step.Value, step.TTL)
+	case "hash":
+		restore := make(map[string]string)
+		var remove []string
+		for field, value := range step.Fields {
+			if value == nil {
+				remove = append(remove, field)
+			} else {
+				restore[field] = *value
+			}
+		}
+		if len(restore) > 0 {
+			if err := client.HSetMulti(ctx, key, restore); err != nil {
+				return err
+			}
+		}
+		if len(remove) > 0 {
+			return client.HDel(ctx, key, remove...)
+		}
+		return nil
+	case "zset":
+		for member, score := range step.Scores {
+			var err error
+			if score == nil {
+				err = client.ZRem(ctx, key, member)
+			} else {
+				err = client.ZAdd(ctx, key, member, *score)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// handleHistory lists recent writes, newest first
+func (h *Handler) handleHistory(w http.ResponseWriter, r *http.Request) {
+	jsonResponse(w, map[string]any{"entries": h.history.list()})
+}
+
+// handleHistoryUndo puts back the state a journaled write replaced. It doesn't
+// check whether the key changed since, so undoing an old entry overwrites newer edits.
+func (h *Handler) handleHistoryUndo(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		jsonError(w, "Invalid history id", http.StatusBadRequest)
+		return
+	}
+
+	entry, status := h.history.claim(id)
+	switch status {
+	case http.StatusNotFound:
+		jsonError(w, "History entry not found", status)
+		return
+	case http.StatusConflict:
+		jsonError(w, "History entry cannot be undone", status)
+		return
+	}
+	if h.checkTypeWritable(w, entry.undo.Type) {
+		h.history.release(entry)
+		return
+	}
+
+	if err := applyUndo(r.Context(), h.client, entry.Key, entry.undo); err != nil {
+		h.history.release(entry)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]string{"status": "ok"})
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+func TestHistoryRing(t *testing.T) {
+	hs := &history{}
+	for i := 0; i < historySize+5; i++ {
+		hs.add(&historyEntry{Key: "k"})
+	}
+
+	entries := hs.list()
+	if len(entries) != historySize {
+		t.Fatalf("expected %d entries, got %d", historySize, len(entries))
+	}
+	if entries[0].ID != historySize+5 || entries[len(entries)-1].ID != 6 {
+		t.Errorf("expected newest-first IDs %d..6, got %d..%d", historySize+5, entries[0].ID, entries[len(entries)-1].ID)
+	}
+
+	if _, status := hs.claim(1); status != http.StatusNotFound {
+		t.Errorf("expected evicted entry to be 404, got %d", status)
+	}
+	if _, status := hs.claim(6); status != http.StatusConflict {
+		t.Errorf("expected entry without undo step to be 409, got %d", status)
+	}
+}
+
+func TestHistoryClaim(t *testing.T) {
+	hs := &history{}
+	hs.add(&historyEntry{Key: "k", undo: &undoStep{Type: "string"}})
+
+	e, status := hs.claim(1)
+	if status != 0 || e == nil {
+		t.Fatalf("expected claim to succeed, got status %d", status)
+	}
+	if _, status := hs.claim(1); status != http.StatusConflict {
+		t.Errorf("expected second undo to be 409, got %d", status)
+	}
+
+	// A failed undo can be retried
+	hs.release(e)
+	if _, status := hs.claim(1); status != 0 {
+		t.Errorf("expected released entry to be claimable, got %d", status)
+	}
+}
+
+func TestSetUndo(t *testing.T) {
+	step := &undoStep{Type: "zset"}
+	h := New(&config.Config{}, nil)
+	h.mux.HandleFunc("POST /test/{key}", func(w http.ResponseWriter, r *http.Request) {
+		setUndo(r, step)
+		jsonResponse(w, map[string]string{"status": "ok"})
+	})
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/test/foo", nil))
+
+	entries := h.history.list()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Key != "foo" || !entries[0].Undoable || entries[0].undo != step {
+		t.Errorf("unexpected entry: %+v", entries[0])
+	}
+}
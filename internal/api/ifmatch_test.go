@@ -0,0 +1,22 @@
+package api
+
+import "testing"
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{`"abc123"`, "abc123"},
+		{`W/"abc123"`, "abc123"},
+		{` W/"abc123" `, "abc123"},
+		{"abc123", "abc123"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ifMatchVersion(tt.header); got != tt.want {
+			t.Errorf("ifMatchVersion(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
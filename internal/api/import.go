@@ -0,0 +1,274 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+// maxImportSize is the maximum allowed import body size (64MB)
+const maxImportSize = 64 << 20
+
+// maxImportErrors caps how many per-key errors are reported back to the client
+const maxImportErrors = 100
+
+// exportRecord is a single line of the newline-delimited JSON export format.
+// Value holds a type-specific payload:
+//
+//	string: "value"
+//	list:   ["a", "b"]
+//	set:    ["a", "b"]
+//	hash:   {"field": "value"}
+//	zset:   [{"member": "a", "score": 1}]
+//	stream: [{"id": "1-0", "fields": {"f": "v"}}]
+type exportRecord struct {
+	Key   string          `json:"key"`
+	Type  string          `json:"type"`
+	TTL   int64           `json:"ttl"` // seconds, <= 0 = no expiry
+	Value json.RawMessage `json:"value"`
+}
+
+// errValueTooLarge rejects a record holding a value over --max-value-size.
+// It stops the whole import with a 413, like any other oversized write.
+var errValueTooLarge = errors.New("value too large")
+
+type importError struct {
+	Line  int    `json:"line"`
+	Key   string `json:"key,omitempty"`
+	Error string `json:"error"`
+}
+
+// handleImport restores keys from newline-delimited JSON produced by the export format.
+// The mode query param controls existing keys: "skip" (default) leaves them untouched,
+// "overwrite" deletes and recreates them, "merge" writes into the existing value.
+// Overwrite counts as destructive for --require-confirm-header.
+func (h *Handler) handleImport(w http.ResponseWriter, r *http.Request) {
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = "skip"
+	}
+	if mode != "skip" && mode != "overwrite" && mode != "merge" {
+		jsonError(w, "Invalid mode (expected skip, overwrite, or merge)", http.StatusBadRequest)
+		return
+	}
+	if mode == "overwrite" && h.checkConfirm(w, r) {
+		return
+	}
+
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	ctx := r.Context()
+	var created, skipped, failed int
+	var errs []importError
+
+	fail := func(line int, key string, msg string) {
+		failed++
+		if len(errs) < maxImportErrors {
+			errs = append(errs, importError{Line: line, Key: key, Error: msg})
+		}
+	}
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := scanner.Bytes()
+		if len(raw) == 0 {
+			continue
+		}
+
+		var rec exportRecord
+		if err := json.Unmarshal(raw, &rec); err != nil {
+			fail(line, "", "Invalid JSON")
+			continue
+		}
+		if rec.Key == "" {
+			fail(line, "", "Missing key")
+			continue
+		}
+		if msg := h.keyScopeError(rec.Key); msg != "" {
+			fail(line, rec.Key, msg)
+			continue
+		}
+		if !h.typeWritable(rec.Type) {
+			fail(line, rec.Key, "Writing "+rec.Type+" keys is disabled")
+			continue
+		}
+
+		// Decode before touching the server so a bad line never replaces a key
+		value, err := decodeRecord(rec, h.cfg.MaxValueSize)
+		if errors.Is(err, errValueTooLarge) {
+			jsonError(w, fmt.Sprintf("Line %d: value exceeds the %d byte limit (%d keys already imported)", line, h.cfg.MaxValueSize, created), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			fail(line, rec.Key, err.Error())
+			continue
+		}
+
+		exists, err := h.client.Exists(ctx, rec.Key)
+		if err != nil {
+			fail(line, rec.Key, importServerError(err))
+			continue
+		}
+		if exists > 0 {
+			if len(h.cfg.WritableTypes) > 0 {
+				existing, err := h.client.Type(ctx, rec.Key)
+				if err != nil {
+					fail(line, rec.Key, importServerError(err))
+					continue
+				}
+				if !h.typeWritable(existing) {
+					fail(line, rec.Key, "Writing "+existing+" keys is disabled")
+					continue
+				}
+			}
+			if mode == "skip" {
+				skipped++
+				continue
+			}
+		}
+
+		// DEL (overwrite), the write, and EXPIRE run as one MULTI
+		replace := exists > 0 && mode == "overwrite"
+		if err := h.client.WriteKey(ctx, rec.Key, value, replace, time.Duration(max(rec.TTL, 0))*time.Second); err != nil {
+			fail(line, rec.Key, importServerError(err))
+			continue
+		}
+
+		created++
+	}
+
+	if err := scanner.Err(); err != nil {
+		jsonError(w, "Failed to read import body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := map[string]any{
+		"created": created,
+		"skipped": skipped,
+		"failed":  failed,
+	}
+	if len(errs) > 0 {
+		resp["errors"] = errs
+	}
+	jsonResponse(w, resp)
+}
+
+// importServerError is the per-line message for a failed Valkey call, mapped
+// like errorResponse so server internals stay in the log
+func importServerError(err error) string {
+	_, message := mapError(err)
+	return message
+}
+
+// decodeRecord parses and validates a record's type-specific value. Any string,
+// element, member, field, or value longer than maxSize (<= 0 = no limit) is errValueTooLarge.
+func decodeRecord(rec exportRecord, maxSize int64) (valkey.KeyValue, error) {
+	v := valkey.KeyValue{Type: rec.Type}
+	switch rec.Type {
+	case "string":
+		if err := json.Unmarshal(rec.Value, &v.String); err != nil {
+			return v, fmt.Errorf("invalid string value")
+		}
+	case "list", "set":
+		if err := json.Unmarshal(rec.Value, &v.Items); err != nil {
+			return v, fmt.Errorf("invalid %s value", rec.Type)
+		}
+		if len(v.Items) == 0 {
+			return v, fmt.Errorf("empty %s", rec.Type)
+		}
+	case "hash":
+		if err := json.Unmarshal(rec.Value, &v.Fields); err != nil {
+			return v, fmt.Errorf("invalid hash value")
+		}
+		if len(v.Fields) == 0 {
+			return v, fmt.Errorf("empty hash")
+		}
+	case "zset":
+		if err := json.Unmarshal(rec.Value, &v.Members); err != nil {
+			return v, fmt.Errorf("invalid zset value")
+		}
+		if len(v.Members) == 0 {
+			return v, fmt.Errorf("empty sorted set")
+		}
+	case "stream":
+		if err := json.Unmarshal(rec.Value, &v.Entries); err != nil {
+			return v, fmt.Errorf("invalid stream value")
+		}
+		if len(v.Entries) == 0 {
+			return v, fmt.Errorf("empty stream")
+		}
+		if err := checkStreamEntries(v.Entries); err != nil {
+			return v, err
+		}
+	default:
+		return v, fmt.Errorf("unsupported type: %s", rec.Type)
+	}
+	if recordTooLarge(v, maxSize) {
+		return v, errValueTooLarge
+	}
+	return v, nil
+}
+
+// recordTooLarge reports whether any string in v is longer than maxSize
+func recordTooLarge(v valkey.KeyValue, maxSize int64) bool {
+	if tooLarge(maxSize, v.String) || tooLarge(maxSize, v.Items...) {
+		return true
+	}
+	for field, value := range v.Fields {
+		if tooLarge(maxSize, field, value) {
+			return true
+		}
+	}
+	for _, m := range v.Members {
+		if tooLarge(maxSize, m.Member) {
+			return true
+		}
+	}
+	for _, e := range v.Entries {
+		for field, value := range e.Fields {
+			if tooLarge(maxSize, field, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkStreamEntries rejects entries XADD would refuse partway through an
+// import: missing fields, or explicit IDs that aren't "ms-seq" and increasing.
+// Empty and "*" IDs are generated by the server.
+func checkStreamEntries(entries []valkey.StreamEntry) error {
+	var lastMs, lastSeq uint64
+	for i, e := range entries {
+		if len(e.Fields) == 0 {
+			return fmt.Errorf("stream entry %d has no fields", i)
+		}
+		if e.ID == "" || e.ID == "*" {
+			continue
+		}
+		msStr, seqStr, ok := strings.Cut(e.ID, "-")
+		ms, err1 := strconv.ParseUint(msStr, 10, 64)
+		seq, err2 := strconv.ParseUint(seqStr, 10, 64)
+		if !ok || err1 != nil || err2 != nil {
+			return fmt.Errorf("invalid stream entry ID %q", e.ID)
+		}
+		if ms < lastMs || (ms == lastMs && seq <= lastSeq) {
+			return fmt.Errorf("stream entry IDs must increase (%q)", e.ID)
+		}
+		lastMs, lastSeq = ms, seq
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+func TestDecodeRecord(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   string
+		value string
+		ok    bool
+	}{
+		{"string", "string", `"hello"`, true},
+		{"list", "list", `["a","b"]`, true},
+		{"empty list", "list", `[]`, false},
+		{"hash", "hash", `{"f":"v"}`, true},
+		{"hash as list", "hash", `["f"]`, false},
+		{"zset", "zset", `[{"member":"a","score":1}]`, true},
+		{"stream", "stream", `[{"id":"1-0","fields":{"f":"v"}},{"id":"1-1","fields":{"f":"v"}}]`, true},
+		{"stream auto IDs", "stream", `[{"fields":{"f":"v"}},{"id":"*","fields":{"f":"v"}}]`, true},
+		{"stream IDs decrease", "stream", `[{"id":"2-0","fields":{"f":"v"}},{"id":"1-0","fields":{"f":"v"}}]`, false},
+		{"stream ID 0-0", "stream", `[{"id":"0-0","fields":{"f":"v"}}]`, false},
+		{"stream bad ID", "stream", `[{"id":"abc","fields":{"f":"v"}}]`, false},
+		{"stream entry without fields", "stream", `[{"id":"1-0","fields":{}}]`, false},
+		{"unknown type", "module", `"x"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeRecord(exportRecord{Key: "k", Type: tt.typ, Value: json.RawMessage(tt.value)}, 0)
+			if (err == nil) != tt.ok {
+				t.Errorf("decodeRecord() error = %v, want ok=%v", err, tt.ok)
+			}
+		})
+	}
+}
+
+type importResult struct {
+	Created int           `json:"created"`
+	Skipped int           `json:"skipped"`
+	Failed  int           `json:"failed"`
+	Errors  []importError `json:"errors"`
+}
+
+func TestImport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	cfg := &config.Config{
+		ValkeyURL: "localhost:6379",
+		ValkeyDB:  15, // Use DB 15 for testing
+	}
+	client, err := valkey.New(cfg)
+	if err != nil {
+		t.Skip("Valkey not available:", err)
+	}
+	defer client.Close()
+	h := New(cfg, client)
+
+	ctx := context.Background()
+	const key = "test:import:key"
+	reset := func(t *testing.T) {
+		t.Helper()
+		_, _ = client.Del(ctx, key, "test:import:new")
+		if err := client.RPush(ctx, key, "original"); err != nil {
+			t.Fatalf("RPush failed: %v", err)
+		}
+	}
+	defer func() { _, _ = client.Del(ctx, key, "test:import:new") }()
+
+	run := func(t *testing.T, mode, body string) importResult {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("POST", "/api/import?mode="+mode, strings.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+		}
+		var result importResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return result
+	}
+	list := func(t *testing.T) []string {
+		t.Helper()
+		items, err := client.LRange(ctx, key, 0, -1)
+		if err != nil {
+			t.Fatalf("LRange failed: %v", err)
+		}
+		return items
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		reset(t)
+		result := run(t, "skip", `{"key":"`+key+`","type":"list","value":["new"]}`+"\n"+
+			`{"key":"test:import:new","type":"string","value":"v","ttl":60}`)
+		if result.Created != 1 || result.Skipped != 1 || result.Failed != 0 {
+			t.Errorf("result = %+v", result)
+		}
+		if got := list(t); len(got) != 1 || got[0] != "original" {
+			t.Errorf("existing key = %q, want it untouched", got)
+		}
+		if ttl, _ := client.TTL(ctx, "test:import:new"); ttl <= 0 {
+			t.Errorf("TTL = %d, want it applied", ttl)
+		}
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		reset(t)
+		result := run(t, "overwrite", `{"key":"`+key+`","type":"list","value":["a","b"]}`)
+		if result.Created != 1 {
+			t.Errorf("result = %+v", result)
+		}
+		if got := list(t); len(got) != 2 || got[0] != "a" {
+			t.Errorf("list = %q, want [a b]", got)
+		}
+	})
+
+	t.Run("overwrite with bad value keeps original", func(t *testing.T) {
+		reset(t)
+		result := run(t, "overwrite", `{"key":"`+key+`","type":"list","value":[]}`+"\n"+
+			`{"key":"`+key+`","type":"hash","value":"not a hash"}`+"\n"+
+			`{"key":"`+key+`","type":"stream","value":[{"id":"5-0","fields":{"f":"v"}},{"id":"1-0","fields":{"f":"v"}}]}`)
+		if result.Failed != 3 || result.Created != 0 {
+			t.Errorf("result = %+v", result)
+		}
+		if got := list(t); len(got) != 1 || got[0] != "original" {
+			t.Errorf("list = %q, want the original value kept", got)
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		reset(t)
+		result := run(t, "merge", `{"key":"`+key+`","type":"list","value":["more"]}`)
+		if result.Created != 1 {
+			t.Errorf("result = %+v", result)
+		}
+		if got := list(t); len(got) != 2 || got[1] != "more" {
+			t.Errorf("list = %q, want [original more]", got)
+		}
+	})
+
+	t.Run("merge into another type", func(t *testing.T) {
+		reset(t)
+		result := run(t, "merge", `{"key":"`+key+`","type":"set","value":["x"]}`)
+		if result.Failed != 1 || len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error, "type") {
+			t.Errorf("result = %+v, want a mapped WRONGTYPE error", result)
+		}
+		if got := list(t); len(got) != 1 {
+			t.Errorf("list = %q, want it untouched", got)
+		}
+	})
+}
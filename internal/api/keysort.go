@@ -0,0 +1,118 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+// keySortFields are the accepted values of the keys "sort" param
+var keySortFields = map[string]bool{"name": true, "ttl": true, "type": true, "size": true}
+
+// sortKeyStats orders stats by field ("name", "ttl", "type", or "size"), breaking
+// ties by key name. Keys without expiry sort after every TTL, like in the UI.
+func sortKeyStats(stats []valkey.KeyStat, field string, desc bool) {
+	ttlRank := func(ttl int64) int64 {
+		if ttl < 0 {
+			return 1<<63 - 1
+		}
+		return ttl
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if desc {
+			a, b = b, a
+		}
+		switch field {
+		case "ttl":
+			if ttlRank(a.TTL) != ttlRank(b.TTL) {
+				return ttlRank(a.TTL) < ttlRank(b.TTL)
+			}
+		case "type":
+			if a.Type != b.Type {
+				return a.Type < b.Type
+			}
+		case "size":
+			if a.Memory != b.Memory {
+				return a.Memory < b.Memory
+			}
+		}
+		return a.Key < b.Key
+	})
+}
+
+// handleKeysSorted serves handleKeys when a sort is requested. Sorting needs the whole
+// result set, so it scans up to maxScanKeys (or MaxKeys if lower) matching keys,
+// fetches metadata in pipelined batches, sorts, and pages with cursor as an offset.
+// When the cap is hit, truncated is set and only the scanned keys are sorted.
+func (h *Handler) handleKeysSorted(w http.ResponseWriter, r *http.Request, pattern string, match func(string) bool, typeFilter, sortBy string, offset uint64, count int64) {
+	if !keySortFields[sortBy] {
+		jsonError(w, "Invalid sort (expected name, ttl, type, or size)", http.StatusBadRequest)
+		return
+	}
+	order := r.URL.Query().Get("order")
+	if order != "" && order != "asc" && order != "desc" {
+		jsonError(w, "Invalid order (expected asc or desc)", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	keys, err := h.scanKeys(ctx, pattern)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	truncated := int64(len(keys)) >= maxScanKeys || (h.cfg.MaxKeys > 0 && int64(len(keys)) >= h.cfg.MaxKeys)
+
+	if match != nil {
+		filtered := keys[:0]
+		for _, key := range keys {
+			if match(key) {
+				filtered = append(filtered, key)
+			}
+		}
+		keys = filtered
+	}
+
+	var stats []valkey.KeyStat
+	for start := 0; start < len(keys); start += 1000 {
+		end := min(start+1000, len(keys))
+		stats = append(stats, h.client.KeyStats(ctx, keys[start:end], sortBy == "size")...)
+	}
+
+	// KeyStats already tells HyperLogLogs apart from strings
+	if typeFilter != "" {
+		filtered := stats[:0]
+		for _, s := range stats {
+			if s.Type == typeFilter {
+				filtered = append(filtered, s)
+			}
+		}
+		stats = filtered
+	}
+
+	sortKeyStats(stats, sortBy, order == "desc")
+
+	total := uint64(len(stats))
+	start := min(offset, total)
+	end := min(start+uint64(count), total)
+	var nextCursor uint64
+	if end < total {
+		nextCursor = end
+	}
+
+	metas := make([]keyMeta, 0, end-start)
+	for _, s := range stats[start:end] {
+		metas = append(metas, keyMeta{Key: s.Key, Type: s.Type, TTL: s.TTL, Memory: s.Memory})
+	}
+
+	jsonResponse(w, map[string]any{
+		"keys":      metas,
+		"cursor":    nextCursor,
+		"total":     total,
+		"truncated": truncated,
+	})
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+func TestSortKeyStats(t *testing.T) {
+	stats := func() []valkey.KeyStat {
+		return []valkey.KeyStat{
+			{Key: "c", Type: "hash", TTL: -1, Memory: 300},
+			{Key: "a", Type: "string", TTL: 60, Memory: 100},
+			{Key: "b", Type: "hash", TTL: 10, Memory: 200},
+		}
+	}
+
+	tests := []struct {
+		field string
+		desc  bool
+		want  []string
+	}{
+		{"name", false, []string{"a", "b", "c"}},
+		{"name", true, []string{"c", "b", "a"}},
+		{"ttl", false, []string{"b", "a", "c"}}, // no expiry sorts last
+		{"ttl", true, []string{"c", "a", "b"}},
+		{"type", false, []string{"b", "c", "a"}}, // ties broken by name
+		{"size", true, []string{"c", "b", "a"}},
+	}
+
+	for _, tt := range tests {
+		s := stats()
+		sortKeyStats(s, tt.field, tt.desc)
+		for i, key := range tt.want {
+			if s[i].Key != key {
+				t.Errorf("sort %s desc=%v: got %v, want %v", tt.field, tt.desc, keysOf(s), tt.want)
+				break
+			}
+		}
+	}
+}
+
+func keysOf(stats []valkey.KeyStat) []string {
+	keys := make([]string, len(stats))
+	for i, s := range stats {
+		keys[i] = s.Key
+	}
+	return keys
+}
@@ -0,0 +1,24 @@
+package api
+
+import "net/http"
+
+// limitScan wraps an expensive scan-based handler so that at most
+// cfg.MaxConcurrentScans run at once. Excess requests are rejected with 429
+// rather than queued, so a few heavy users can't starve the server.
+func (h *Handler) limitScan(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.scanSem == nil {
+			next(w, r)
+			return
+		}
+
+		select {
+		case h.scanSem <- struct{}{}:
+			defer func() { <-h.scanSem }()
+			next(w, r)
+		default:
+			w.Header().Set("Retry-After", "1")
+			jsonError(w, "Too many concurrent scan requests, try again shortly", http.StatusTooManyRequests)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+func TestLimitScan(t *testing.T) {
+	const limit = 2
+	const requests = 5
+
+	h := New(&config.Config{MaxConcurrentScans: limit}, nil)
+
+	started := make(chan struct{}, requests)
+	release := make(chan struct{})
+	handler := h.limitScan(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		jsonResponse(w, map[string]string{"status": "ok"})
+	})
+
+	codes := make(chan int, requests)
+	var wg sync.WaitGroup
+
+	// Fill every slot and wait until the handlers are running
+	for i := 0; i < limit; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest("GET", "/api/keys", nil))
+			codes <- rec.Code
+		}()
+	}
+	for i := 0; i < limit; i++ {
+		<-started
+	}
+
+	// Everything beyond the limit is throttled
+	for i := 0; i < requests-limit; i++ {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest("GET", "/api/keys", nil))
+		if rec.Code != http.StatusTooManyRequests {
+			t.Errorf("expected 429 for excess request, got %d", rec.Code)
+		}
+		if rec.Header().Get("Retry-After") == "" {
+			t.Error("expected Retry-After header on throttled request")
+		}
+	}
+
+	close(release)
+	wg.Wait()
+	close(codes)
+
+	for code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("expected 200 for admitted request, got %d", code)
+		}
+	}
+
+	// Slots are released once requests finish
+	rec := httptest.NewRecorder()
+	h.limitScan(func(w http.ResponseWriter, r *http.Request) {
+		jsonResponse(w, map[string]string{"status": "ok"})
+	})(rec, httptest.NewRequest("GET", "/api/keys", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 after slots released, got %d", rec.Code)
+	}
+}
+
+func TestLimitScanUnlimited(t *testing.T) {
+	h := New(&config.Config{}, nil)
+	if h.scanSem != nil {
+		t.Fatal("expected no semaphore when MaxConcurrentScans is 0")
+	}
+
+	rec := httptest.NewRecorder()
+	h.limitScan(func(w http.ResponseWriter, r *http.Request) {
+		jsonResponse(w, map[string]string{"status": "ok"})
+	})(rec, httptest.NewRequest("GET", "/api/keys", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+// metaChunkSize is how many keys each metadata worker pipelines per batch
+const metaChunkSize = 100
+
+// defaultMetaConcurrency is used when MetaConcurrency isn't configured
+const defaultMetaConcurrency = 4
+
+// fetchKeyMeta gathers type and TTL for keys. Keys are split into pipelined chunks and
+// up to MetaConcurrency chunks run at once, so large key sets don't pay one round trip
+// per key and a single request can't flood the server. Order of keys is preserved.
+// With the metadata cache on, only keys missing from it are fetched.
+func (h *Handler) fetchKeyMeta(ctx context.Context, keys []string) []keyMeta {
+	if h.metaCache == nil {
+		return h.fetchKeyMetaUncached(ctx, keys)
+	}
+
+	now := time.Now()
+	cached := make(map[string]keyMeta, len(keys))
+	var missing []string
+	for _, key := range keys {
+		if m, ok := h.metaCache.get(key, now); ok {
+			cached[key] = m
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	for _, m := range h.fetchKeyMetaUncached(ctx, missing) {
+		h.metaCache.put(m, now)
+		cached[m.Key] = m
+	}
+
+	metas := make([]keyMeta, 0, len(keys))
+	for _, key := range keys {
+		if m, ok := cached[key]; ok {
+			metas = append(metas, m)
+		}
+	}
+	return metas
+}
+
+func (h *Handler) fetchKeyMetaUncached(ctx context.Context, keys []string) []keyMeta {
+	workers := h.cfg.MetaConcurrency
+	if workers < 1 {
+		workers = defaultMetaConcurrency
+	}
+
+	chunks := (len(keys) + metaChunkSize - 1) / metaChunkSize
+	results := make([][]valkey.KeyStat, chunks)
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for i := range chunks {
+		start := i * metaChunkSize
+		end := min(start+metaChunkSize, len(keys))
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = h.client.KeyMetaBatch(ctx, keys[start:end])
+		}()
+	}
+	wg.Wait()
+
+	metas := make([]keyMeta, 0, len(keys))
+	for _, chunk := range results {
+		for _, s := range chunk {
+			metas = append(metas, keyMeta{Key: s.Key, Type: s.Type, TTL: s.TTL})
+		}
+	}
+	return metas
+}
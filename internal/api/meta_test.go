@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+// BenchmarkKeyMeta compares per-key TYPE/TTL lookups with the pooled, pipelined
+// fetchKeyMeta on 1000 keys. This requires a running Valkey/Redis instance.
+func BenchmarkKeyMeta(b *testing.B) {
+	cfg := &config.Config{
+		ValkeyURL: "localhost:6379",
+		ValkeyDB:  15, // Use DB 15 for testing
+	}
+
+	client, err := valkey.New(cfg)
+	if err != nil {
+		b.Skip("Valkey not available:", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("bench:meta:%d", i)
+		if err := client.Set(ctx, keys[i], "v", 0); err != nil {
+			b.Fatalf("Set failed: %v", err)
+		}
+	}
+	defer func() {
+		_, _ = client.Del(ctx, keys...)
+	}()
+
+	h := New(cfg, client)
+
+	b.Run("serial", func(b *testing.B) {
+		for range b.N {
+			for _, key := range keys {
+				_, _ = client.Type(ctx, key)
+				_, _ = client.TTL(ctx, key)
+			}
+		}
+	})
+
+	b.Run("pooled", func(b *testing.B) {
+		for range b.N {
+			if metas := h.fetchKeyMeta(ctx, keys); len(metas) != len(keys) {
+				b.Fatalf("expected %d metas, got %d", len(keys), len(metas))
+			}
+		}
+	})
+}
+
+// BenchmarkPrefixes builds the prefix tree over a 10k-key hierarchical namespace,
+// comparing a TYPE round trip per leaf with the pipelined handler. This requires
+// a running Valkey/Redis instance.
+func BenchmarkPrefixes(b *testing.B) {
+	cfg := &config.Config{
+		ValkeyURL: "localhost:6379",
+		ValkeyDB:  15, // Use DB 15 for testing
+	}
+
+	client, err := valkey.New(cfg)
+	if err != nil {
+		b.Skip("Valkey not available:", err)
+	}
+	defer client.Close()
+
+	// 10 tenants x 1000 leaves
+	ctx := context.Background()
+	keys := make([]string, 0, 10000)
+	for tenant := range 10 {
+		for id := range 1000 {
+			keys = append(keys, fmt.Sprintf("bench:tree:t%d:%d", tenant, id))
+		}
+	}
+	for _, key := range keys {
+		if err := client.Set(ctx, key, "v", 0); err != nil {
+			b.Fatalf("Set failed: %v", err)
+		}
+	}
+	defer func() {
+		for start := 0; start < len(keys); start += 1000 {
+			_, _ = client.Del(ctx, keys[start:min(start+1000, len(keys))]...)
+		}
+	}()
+
+	h := New(cfg, client)
+	leafPrefix := "bench:tree:t0:"
+
+	b.Run("serial", func(b *testing.B) {
+		for range b.N {
+			leaves, err := h.scanKeys(ctx, leafPrefix+"*")
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, key := range leaves {
+				_, _ = client.Type(ctx, key)
+			}
+		}
+	})
+
+	b.Run("pipelined", func(b *testing.B) {
+		for range b.N {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/prefixes?prefix="+leafPrefix, nil))
+			if rec.Code != http.StatusOK {
+				b.Fatalf("expected 200, got %d", rec.Code)
+			}
+		}
+	})
+
+	// Grouping all 10k keys into tenant counts needs no TYPE calls at all
+	b.Run("groups", func(b *testing.B) {
+		for range b.N {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/prefixes?prefix=bench:tree:", nil))
+			if rec.Code != http.StatusOK {
+				b.Fatalf("expected 200, got %d", rec.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// metaCacheSize caps how many keys the metadata cache remembers
+const metaCacheSize = 10000
+
+// metaCache is a small LRU of key type/TTL, so paging and refreshing the key
+// list doesn't re-issue TYPE/TTL for keys seen a moment ago. Entries expire
+// after ttl and are dropped early on writes and keyspace events.
+type metaCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	order   *list.List // front = most recently used
+	entries map[string]*list.Element
+}
+
+type metaCacheEntry struct {
+	meta    keyMeta
+	fetched time.Time
+}
+
+func newMetaCache(ttl time.Duration) *metaCache {
+	return &metaCache{ttl: ttl, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get returns the cached metadata for key, with its TTL counted down by the
+// time spent in the cache
+func (c *metaCache) get(key string, now time.Time) (keyMeta, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return keyMeta{}, false
+	}
+	e := el.Value.(*metaCacheEntry)
+	age := now.Sub(e.fetched)
+	if age >= c.ttl {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return keyMeta{}, false
+	}
+	c.order.MoveToFront(el)
+
+	m := e.meta
+	if m.TTL > 0 {
+		m.TTL = max(m.TTL-int64(age/time.Second), 1)
+	}
+	return m, true
+}
+
+func (c *metaCache) put(m keyMeta, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[m.Key]; ok {
+		el.Value = &metaCacheEntry{meta: m, fetched: now}
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[m.Key] = c.order.PushFront(&metaCacheEntry{meta: m, fetched: now})
+	if c.order.Len() > metaCacheSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*metaCacheEntry).meta.Key)
+	}
+}
+
+func (c *metaCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+}
+
+func (c *metaCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	clear(c.entries)
+}
+
+// InvalidateKey drops key from the metadata cache, e.g. on a keyspace event
+func (h *Handler) InvalidateKey(key string) {
+	if h.metaCache != nil {
+		h.metaCache.remove(key)
+	}
+}
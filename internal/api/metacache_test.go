@@ -0,0 +1,54 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMetaCache(t *testing.T) {
+	c := newMetaCache(10 * time.Second)
+	now := time.Now()
+
+	c.put(keyMeta{Key: "a", Type: "string", TTL: 60}, now)
+	c.put(keyMeta{Key: "b", Type: "hash", TTL: -1}, now)
+
+	// TTLs count down while cached; keys without expiry stay -1
+	if m, ok := c.get("a", now.Add(3*time.Second)); !ok || m.TTL != 57 {
+		t.Errorf("get(a) = %+v, %v; want TTL 57", m, ok)
+	}
+	if m, ok := c.get("b", now.Add(3*time.Second)); !ok || m.TTL != -1 {
+		t.Errorf("get(b) = %+v, %v; want TTL -1", m, ok)
+	}
+
+	// Entries expire after the cache TTL
+	if _, ok := c.get("a", now.Add(10*time.Second)); ok {
+		t.Error("expected a to expire")
+	}
+
+	c.remove("b")
+	if _, ok := c.get("b", now); ok {
+		t.Error("expected b to be removed")
+	}
+}
+
+func TestMetaCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newMetaCache(time.Minute)
+	now := time.Now()
+
+	for i := range metaCacheSize {
+		c.put(keyMeta{Key: fmt.Sprint(i), Type: "string"}, now)
+	}
+	c.get("0", now) // touch the oldest so it survives
+	c.put(keyMeta{Key: "new", Type: "string"}, now)
+
+	if _, ok := c.get("0", now); !ok {
+		t.Error("recently used key was evicted")
+	}
+	if _, ok := c.get("1", now); ok {
+		t.Error("least recently used key was kept")
+	}
+	if len(c.entries) != metaCacheSize {
+		t.Errorf("cache holds %d entries, want %d", len(c.entries), metaCacheSize)
+	}
+}
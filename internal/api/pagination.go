@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/base64"
+	"strconv"
+)
+
+// pageBounds clamps page to [1, totalPages] and returns the inclusive index range for it.
+// An empty collection has a single empty page, so out-of-range requests land on the
+// last page rather than returning nothing.
+func pageBounds(page, pageSize, total int64) (clamped, totalPages, start, stop int64) {
+	totalPages = (total + pageSize - 1) / pageSize
+	if totalPages < 1 {
+		totalPages = 1
+	}
+
+	clamped = page
+	if clamped < 1 {
+		clamped = 1
+	}
+	if clamped > totalPages {
+		clamped = totalPages
+	}
+
+	start = (clamped - 1) * pageSize
+	stop = start + pageSize - 1
+	return clamped, totalPages, start, stop
+}
+
+// encodeCursor wraps a raw position (scan cursor or stream ID) in the opaque
+// nextCursor token returned to clients. No position means no more pages.
+func encodeCursor(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor unwraps a token from encodeCursor; empty means start from the beginning
+func decodeCursor(token string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	return string(raw), err
+}
+
+// encodeScanCursor returns the token for an SSCAN/HSCAN cursor, where 0 means the scan is done
+func encodeScanCursor(cursor uint64) string {
+	if cursor == 0 {
+		return ""
+	}
+	return encodeCursor(strconv.FormatUint(cursor, 10))
+}
+
+// decodeScanCursor parses a token from encodeScanCursor
+func decodeScanCursor(token string) (uint64, error) {
+	raw, err := decodeCursor(token)
+	if err != nil || raw == "" {
+		return 0, err
+	}
+	return strconv.ParseUint(raw, 10, 64)
+}
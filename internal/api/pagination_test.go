@@ -0,0 +1,66 @@
+package api
+
+import "testing"
+
+func TestPageBounds(t *testing.T) {
+	tests := []struct {
+		name           string
+		page, pageSize int64
+		total          int64
+		wantPage       int64
+		wantTotalPages int64
+		wantStart      int64
+		wantStop       int64
+	}{
+		{"first page", 1, 10, 95, 1, 10, 0, 9},
+		{"middle page", 3, 10, 95, 3, 10, 20, 29},
+		{"last partial page", 10, 10, 95, 10, 10, 90, 99},
+		{"beyond the end clamps to last page", 50, 10, 95, 10, 10, 90, 99},
+		{"exact multiple", 5, 10, 50, 5, 5, 40, 49},
+		{"beyond exact multiple", 6, 10, 50, 5, 5, 40, 49},
+		{"empty collection", 3, 10, 0, 1, 1, 0, 9},
+		{"zero page", 0, 10, 95, 1, 10, 0, 9},
+		{"single item", 2, 100, 1, 1, 1, 0, 99},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, totalPages, start, stop := pageBounds(tt.page, tt.pageSize, tt.total)
+			if page != tt.wantPage {
+				t.Errorf("page = %d, want %d", page, tt.wantPage)
+			}
+			if totalPages != tt.wantTotalPages {
+				t.Errorf("totalPages = %d, want %d", totalPages, tt.wantTotalPages)
+			}
+			if start != tt.wantStart || stop != tt.wantStop {
+				t.Errorf("range = [%d, %d], want [%d, %d]", start, stop, tt.wantStart, tt.wantStop)
+			}
+		})
+	}
+}
+
+func TestCursorToken(t *testing.T) {
+	if encodeScanCursor(0) != "" {
+		t.Error("expected a finished scan to have no token")
+	}
+
+	for _, cursor := range []uint64{1, 4096, 1<<64 - 1} {
+		got, err := decodeScanCursor(encodeScanCursor(cursor))
+		if err != nil || got != cursor {
+			t.Errorf("round trip of %d = %d, %v", cursor, got, err)
+		}
+	}
+
+	if got, err := decodeScanCursor(""); err != nil || got != 0 {
+		t.Errorf("empty token = %d, %v; want 0, nil", got, err)
+	}
+	for _, bad := range []string{"!!", encodeScanCursor(5) + "$", encodeCursor("not-a-number")} {
+		if _, err := decodeScanCursor(bad); err == nil {
+			t.Errorf("expected error for token %q", bad)
+		}
+	}
+
+	if got, err := decodeCursor(encodeCursor("1700000000000-3")); err != nil || got != "1700000000000-3" {
+		t.Errorf("stream ID round trip = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// referenceResult describes a hash field that holds the name of another key
+type referenceResult struct {
+	SourceKey string `json:"sourceKey"`
+	RefKey    string `json:"refKey"`
+	Exists    bool   `json:"exists"`
+}
+
+// handleCheckReferences scans hashes matching sourcePattern, reads refField from each,
+// and reports references that point to keys which don't exist
+func (h *Handler) handleCheckReferences(w http.ResponseWriter, r *http.Request) {
+	markReadOnly(r)
+	var body struct {
+		SourcePattern string `json:"sourcePattern"`
+		RefField      string `json:"refField"`
+		IncludeValid  bool   `json:"includeValid"` // also list references that resolve
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if body.SourcePattern == "" {
+		jsonError(w, "sourcePattern is required", http.StatusBadRequest)
+		return
+	}
+	if body.RefField == "" {
+		jsonError(w, "refField is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	sources, err := h.scanKeys(ctx, h.applyPrefixToPattern(body.SourcePattern))
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	refs := h.client.HGetBatch(ctx, sources, body.RefField)
+
+	// References outside the allowed prefix are not checked, so they can't leak key existence
+	toCheck := make([]string, 0, len(refs))
+	outOfScope := 0
+	for _, ref := range refs {
+		if !h.keyAllowed(ref) {
+			outOfScope++
+			continue
+		}
+		toCheck = append(toCheck, ref)
+	}
+
+	exists, err := h.client.ExistsBatch(ctx, toCheck)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	results := make([]referenceResult, 0)
+	broken := 0
+	for source, ref := range refs {
+		ok, checked := exists[ref]
+		if !checked {
+			continue
+		}
+		if !ok {
+			broken++
+		}
+		if !ok || body.IncludeValid {
+			results = append(results, referenceResult{SourceKey: source, RefKey: ref, Exists: ok})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].SourceKey < results[j].SourceKey
+	})
+
+	jsonResponse(w, map[string]any{
+		"results":    results,
+		"scanned":    len(sources),
+		"references": len(refs),
+		"broken":     broken,
+		"outOfScope": outOfScope,
+		"truncated":  len(sources) >= maxScanKeys || (h.cfg.MaxKeys > 0 && int64(len(sources)) >= h.cfg.MaxKeys),
+	})
+}
+
+// maxExistsKeys caps how many keys one exists check may ask about
+const maxExistsKeys = 1000
+
+// handleKeysExists reports which of the given keys exist. With countOnly it issues a
+// single variadic EXISTS and returns just the count (duplicates count once each time
+// they appear, as EXISTS does); otherwise it pipelines one EXISTS per key to build a map.
+func (h *Handler) handleKeysExists(w http.ResponseWriter, r *http.Request) {
+	markReadOnly(r)
+	var body struct {
+		Keys      []string `json:"keys"`
+		CountOnly bool     `json:"countOnly"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(body.Keys) == 0 {
+		jsonError(w, "No keys specified", http.StatusBadRequest)
+		return
+	}
+	if len(body.Keys) > maxExistsKeys {
+		jsonError(w, "Too many keys (max 1000)", http.StatusBadRequest)
+		return
+	}
+
+	for _, key := range body.Keys {
+		if h.checkKeyPrefix(w, key) {
+			return
+		}
+	}
+
+	if body.CountOnly {
+		count, err := h.client.Exists(r.Context(), body.Keys...)
+		if err != nil {
+			errorResponse(w, err)
+			return
+		}
+		jsonResponse(w, map[string]any{"count": count})
+		return
+	}
+
+	exists, err := h.client.ExistsBatch(r.Context(), body.Keys)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	count := 0
+	for _, ok := range exists {
+		if ok {
+			count++
+		}
+	}
+
+	jsonResponse(w, map[string]any{
+		"exists": exists,
+		"count":  count,
+	})
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+// maxSampleKeys caps n for key sampling
+const maxSampleKeys = 1000
+
+// handleSampleKeys returns up to n random keys with type and TTL. Without a prefix
+// it calls RANDOMKEY repeatedly; with one, RANDOMKEY would mostly miss, so it takes
+// a reservoir sample over a prefix-matched SCAN (bounded like the key list).
+func (h *Handler) handleSampleKeys(w http.ResponseWriter, r *http.Request) {
+	n := 20
+	if s := r.URL.Query().Get("n"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 1 || v > maxSampleKeys {
+			jsonError(w, "n must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+		n = v
+	}
+
+	ctx := r.Context()
+	var keys []string
+
+	if !h.cfg.KeyScoped() {
+		seen := make(map[string]bool, n)
+		// Duplicates get likely as n approaches the key count, so give up after a few misses per slot
+		for attempts := 0; len(keys) < n && attempts < n*5; attempts++ {
+			key, err := h.client.RandomKey(ctx)
+			if err != nil {
+				errorResponse(w, err)
+				return
+			}
+			if key == "" {
+				break // empty database
+			}
+			if seen[key] || valkey.IsTrashKey(key) {
+				continue
+			}
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	} else {
+		var i int
+		err := h.scanBatches(ctx, escapeGlob(h.cfg.Prefix)+"*", maxScanKeys, func(batch []string) error {
+			for _, key := range batch {
+				if len(keys) < n {
+					keys = append(keys, key)
+				} else if j := rand.IntN(i + 1); j < n {
+					keys[j] = key
+				}
+				i++
+			}
+			return nil
+		})
+		if err != nil {
+			errorResponse(w, err)
+			return
+		}
+	}
+
+	jsonResponse(w, map[string]any{
+		"keys": h.fetchKeyMeta(ctx, keys),
+	})
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+func TestKeyScopeErrors(t *testing.T) {
+	// Every case is rejected before the (nil) client is used
+	h := New(&config.Config{
+		Prefix:        "app",
+		AllowPatterns: []string{"app1:*", "app2:*"},
+		DenyPatterns:  []string{"*:secret:*"},
+	}, nil)
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"other:1", "Key does not match required prefix"},
+		{"app3:1", "Key does not match any allowed pattern"},
+		{"app1:secret:token", "Key matches a denied pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/key/"+tt.key, nil))
+			if rec.Code != http.StatusForbidden {
+				t.Fatalf("expected 403, got %d", rec.Code)
+			}
+			var body map[string]string
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body["error"] != tt.want {
+				t.Errorf("error = %q, want %q", body["error"], tt.want)
+			}
+		})
+	}
+}
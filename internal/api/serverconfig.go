@@ -0,0 +1,225 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// evictionPolicies are the values maxmemory-policy accepts
+var evictionPolicies = map[string]bool{
+	"noeviction":      true,
+	"allkeys-lru":     true,
+	"allkeys-lfu":     true,
+	"allkeys-random":  true,
+	"volatile-lru":    true,
+	"volatile-lfu":    true,
+	"volatile-random": true,
+	"volatile-ttl":    true,
+}
+
+// memorySizePattern matches maxmemory values: bytes, optionally with a unit suffix
+var memorySizePattern = regexp.MustCompile(`(?i)^\d+(b|k|kb|m|mb|g|gb)?$`)
+
+// checkConfigWrite returns true and sends 403 if server config changes aren't allowed
+func (h *Handler) checkConfigWrite(w http.ResponseWriter, r *http.Request) bool {
+	if h.checkReadOnly(w, r) {
+		return true
+	}
+	if !h.cfg.EnableConfig {
+		jsonError(w, "Server config changes are disabled (start with --enable-config)", http.StatusForbidden)
+		return true
+	}
+	return false
+}
+
+// memoryConfig reads the eviction settings and current usage
+func (h *Handler) memoryConfig(r *http.Request) (map[string]any, error) {
+	ctx := r.Context()
+
+	params, err := h.client.ConfigGet(ctx, "maxmemory*")
+	if err != nil {
+		return nil, err
+	}
+	maxMemory, _ := strconv.ParseInt(params["maxmemory"], 10, 64)
+
+	resp := map[string]any{
+		"maxmemory":       maxMemory, // bytes, 0 = no limit
+		"maxmemoryPolicy": params["maxmemory-policy"],
+	}
+	if stats, err := h.client.GetMemoryStats(ctx); err == nil {
+		resp["usedMemory"] = stats.UsedMemory
+		resp["usedMemoryHuman"] = stats.UsedMemoryHuman
+	}
+	return resp, nil
+}
+
+func (h *Handler) handleGetMemoryConfig(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.memoryConfig(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	jsonResponse(w, resp)
+}
+
+// handleSetMemoryConfig updates maxmemory and/or maxmemory-policy in a single
+// CONFIG SET, so either both change or neither does
+func (h *Handler) handleSetMemoryConfig(w http.ResponseWriter, r *http.Request) {
+	if h.checkConfigWrite(w, r) {
+		return
+	}
+
+	var body struct {
+		MaxMemory *string `json:"maxmemory"` // e.g. "0", "104857600", "512mb"
+		Policy    *string `json:"maxmemoryPolicy"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	params := make(map[string]string)
+	if body.MaxMemory != nil {
+		if !memorySizePattern.MatchString(*body.MaxMemory) {
+			jsonError(w, "maxmemory must be a byte count, optionally with a unit (kb, mb, gb)", http.StatusBadRequest)
+			return
+		}
+		params["maxmemory"] = *body.MaxMemory
+	}
+	if body.Policy != nil {
+		if !evictionPolicies[*body.Policy] {
+			jsonError(w, "Unknown maxmemoryPolicy", http.StatusBadRequest)
+			return
+		}
+		params["maxmemory-policy"] = *body.Policy
+	}
+	if len(params) == 0 {
+		jsonError(w, "Nothing to update", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.client.ConfigSet(r.Context(), params); err != nil {
+		jsonError(w, "CONFIG SET failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Report what the server now has, which may normalize the values
+	resp, err := h.memoryConfig(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	resp["ok"] = true
+	jsonResponse(w, resp)
+}
+
+// secretParams are redacted from CONFIG GET results
+var secretParams = map[string]bool{
+	"requirepass": true,
+	"masterauth":  true,
+}
+
+// protectedParams can't be changed from the UI: credentials could lock kvweb out, and
+// file locations are the classic way to turn CONFIG SET into writing arbitrary files
+var protectedParams = map[string]bool{
+	"requirepass": true,
+	"masterauth":  true,
+	"masteruser":  true,
+	"dir":         true,
+	"dbfilename":  true,
+	"logfile":     true,
+	"aclfile":     true,
+	"pidfile":     true,
+	"unixsocket":  true,
+}
+
+type configParam struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// handleGetServerConfig lists server parameters matching pattern (default "*"), sorted by name
+func (h *Handler) handleGetServerConfig(w http.ResponseWriter, r *http.Request) {
+	pattern := r.URL.Query().Get("pattern")
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	params, err := h.client.ConfigGet(r.Context(), pattern)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	list := make([]configParam, 0, len(params))
+	for name, value := range params {
+		if secretParams[name] && value != "" {
+			value = "********"
+		}
+		list = append(list, configParam{Name: name, Value: value})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	jsonResponse(w, map[string]any{
+		"parameters": list,
+		"writable":   h.cfg.EnableConfig && !h.cfg.ReadOnly,
+	})
+}
+
+// handleSetServerConfig applies {"parameters": {"name": "value", ...}} in one atomic CONFIG SET
+func (h *Handler) handleSetServerConfig(w http.ResponseWriter, r *http.Request) {
+	if h.checkConfigWrite(w, r) {
+		return
+	}
+
+	var body struct {
+		Parameters map[string]string `json:"parameters"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(body.Parameters) == 0 {
+		jsonError(w, "Nothing to update", http.StatusBadRequest)
+		return
+	}
+
+	params := make(map[string]string, len(body.Parameters))
+	for name, value := range body.Parameters {
+		name = strings.ToLower(name)
+		if protectedParams[name] {
+			jsonError(w, fmt.Sprintf("%s can't be changed from kvweb", name), http.StatusForbidden)
+			return
+		}
+		if name == "maxmemory-policy" && !evictionPolicies[value] {
+			jsonError(w, "Unknown maxmemory-policy", http.StatusBadRequest)
+			return
+		}
+		params[name] = value
+	}
+
+	ctx := r.Context()
+	if err := h.client.ConfigSet(ctx, params); err != nil {
+		jsonError(w, "CONFIG SET failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Keep live updates in step when notifications are switched through the generic editor
+	if events, ok := params["notify-keyspace-events"]; ok {
+		if events != "" && h.onNotificationsEnabled != nil {
+			h.onNotificationsEnabled()
+		} else if events == "" && h.onNotificationsDisabled != nil {
+			h.onNotificationsDisabled()
+		}
+	}
+
+	jsonResponse(w, map[string]any{"ok": true, "updated": len(params)})
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+// maxTransactionOps caps how many operations one transaction may contain
+const maxTransactionOps = 100
+
+// transactionOp is one operation in a POST /api/transaction body
+type transactionOp struct {
+	Op     string  `json:"op"` // set, del, hset, zadd, or expire
+	Key    string  `json:"key"`
+	Field  string  `json:"field"`
+	Member string  `json:"member"`
+	Value  string  `json:"value"`
+	Score  float64 `json:"score"`
+	TTL    int64   `json:"ttl"` // seconds; optional for set, required for expire
+}
+
+// txOpTypes maps ops that create values to the type they write
+var txOpTypes = map[string]string{"set": "string", "hset": "hash", "zadd": "zset"}
+
+// validate checks op and converts it for the client, returning a user-facing error message
+func (op transactionOp) validate() (valkey.TxOp, string) {
+	tx := valkey.TxOp{Op: op.Op, Key: op.Key, Field: op.Field, Member: op.Member, Value: op.Value, Score: op.Score}
+	if op.Key == "" {
+		return tx, "key is required"
+	}
+	if op.TTL < 0 {
+		return tx, "ttl cannot be negative"
+	}
+	tx.TTL = time.Duration(op.TTL) * time.Second
+
+	switch op.Op {
+	case "set", "del":
+	case "hset":
+		if op.Field == "" {
+			return tx, "field is required"
+		}
+	case "zadd":
+		if op.Member == "" {
+			return tx, "member is required"
+		}
+	case "expire":
+		if op.TTL == 0 {
+			return tx, "ttl is required"
+		}
+	default:
+		return tx, "unsupported op (expected set, del, hset, zadd, or expire)"
+	}
+	return tx, ""
+}
+
+// handleTransaction runs an ordered list of writes atomically with MULTI/EXEC.
+// Every op is validated first; one bad op rejects the whole request before
+// anything is sent. A del op counts as destructive for --require-confirm-header.
+func (h *Handler) handleTransaction(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Ops []transactionOp `json:"ops"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if slices.ContainsFunc(body.Ops, func(op transactionOp) bool { return op.Op == "del" }) && h.checkConfirm(w, r) {
+		return
+	}
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	if len(body.Ops) == 0 {
+		jsonError(w, "At least one op is required", http.StatusBadRequest)
+		return
+	}
+	if len(body.Ops) > maxTransactionOps {
+		jsonError(w, fmt.Sprintf("Too many ops (max %d)", maxTransactionOps), http.StatusBadRequest)
+		return
+	}
+
+	ops := make([]valkey.TxOp, len(body.Ops))
+	keys := make([]string, len(body.Ops))
+	for i, op := range body.Ops {
+		tx, msg := op.validate()
+		if msg != "" {
+			jsonError(w, fmt.Sprintf("Op %d: %s", i, msg), http.StatusBadRequest)
+			return
+		}
+		if msg := h.keyScopeError(op.Key); msg != "" {
+			jsonError(w, fmt.Sprintf("Op %d: %s", i, msg), http.StatusForbidden)
+			return
+		}
+		if keyType, ok := txOpTypes[op.Op]; ok && !h.typeWritable(keyType) {
+			jsonError(w, fmt.Sprintf("Op %d: writing %s keys is disabled", i, keyType), http.StatusForbidden)
+			return
+		}
+		if msg := h.valueSizeError(op.Field, op.Member, op.Value); msg != "" {
+			jsonError(w, fmt.Sprintf("Op %d: %s", i, msg), http.StatusRequestEntityTooLarge)
+			return
+		}
+		ops[i] = tx
+		keys[i] = op.Key
+	}
+	if h.checkKeysWritable(w, r.Context(), keys...) {
+		return
+	}
+
+	results, err := h.client.Transaction(r.Context(), ops)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, map[string]any{"results": results})
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+func TestTransactionValidation(t *testing.T) {
+	// Every case must be rejected before the (nil) client is used
+	h := New(&config.Config{Prefix: "app:"}, nil)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"no ops", `{"ops":[]}`, http.StatusBadRequest},
+		{"unknown op", `{"ops":[{"op":"lpush","key":"app:a"}]}`, http.StatusBadRequest},
+		{"missing key", `{"ops":[{"op":"del"}]}`, http.StatusBadRequest},
+		{"hset without field", `{"ops":[{"op":"hset","key":"app:h","value":"v"}]}`, http.StatusBadRequest},
+		{"zadd without member", `{"ops":[{"op":"zadd","key":"app:z","score":1}]}`, http.StatusBadRequest},
+		{"expire without ttl", `{"ops":[{"op":"expire","key":"app:a"}]}`, http.StatusBadRequest},
+		{"negative ttl", `{"ops":[{"op":"set","key":"app:a","ttl":-1}]}`, http.StatusBadRequest},
+		{"later op outside prefix", `{"ops":[{"op":"set","key":"app:a","value":"1"},{"op":"del","key":"other"}]}`, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("POST", "/api/transaction", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
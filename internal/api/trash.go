@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// deleteKeys deletes keys, first backing them up to the trash when soft delete is enabled
+func (h *Handler) deleteKeys(ctx context.Context, keys ...string) (int64, error) {
+	if h.cfg.SoftDeleteTTL > 0 {
+		return h.client.SoftDelete(ctx, time.Duration(h.cfg.SoftDeleteTTL)*time.Second, keys...)
+	}
+	return h.client.Del(ctx, keys...)
+}
+
+func (h *Handler) handleTrashList(w http.ResponseWriter, r *http.Request) {
+	if h.cfg.SoftDeleteTTL <= 0 {
+		jsonError(w, "Soft delete is disabled", http.StatusNotFound)
+		return
+	}
+
+	entries, err := h.client.TrashList(r.Context(), escapeGlob(h.cfg.Prefix)+"*")
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	if h.cfg.KeyPatterns() {
+		allowed := entries[:0]
+		for _, e := range entries {
+			if h.keyAllowed(e.Key) {
+				allowed = append(allowed, e)
+			}
+		}
+		entries = allowed
+	}
+
+	jsonResponse(w, map[string]any{
+		"keys":      entries,
+		"retention": h.cfg.SoftDeleteTTL,
+	})
+}
+
+func (h *Handler) handleTrashRestore(w http.ResponseWriter, r *http.Request) {
+	if h.checkReadOnly(w, r) {
+		return
+	}
+
+	if h.cfg.SoftDeleteTTL <= 0 {
+		jsonError(w, "Soft delete is disabled", http.StatusNotFound)
+		return
+	}
+
+	key := r.PathValue("key")
+	if h.checkKeyPrefix(w, key) {
+		return
+	}
+
+	if err := h.client.RestoreFromTrash(r.Context(), key); err != nil {
+		// Check for specific error messages from Lua script
+		switch err.Error() {
+		case "Nothing to restore":
+			jsonError(w, "Nothing to restore", http.StatusNotFound)
+		case "Key already exists":
+			jsonError(w, "Key already exists", http.StatusConflict)
+		default:
+			errorResponse(w, err)
+		}
+		return
+	}
+
+	jsonResponse(w, map[string]string{"status": "ok"})
+}
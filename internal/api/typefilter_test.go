@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+// newTypeFilterHandler connects to a local Valkey/Redis instance on DB 15 and
+// creates a plain string and a HyperLogLog, skipping the test if none is available
+func newTypeFilterHandler(t *testing.T) *Handler {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	cfg := &config.Config{
+		ValkeyURL: "localhost:6379",
+		ValkeyDB:  15, // Use DB 15 for testing
+		Prefix:    "test:typefilter:",
+	}
+	client, err := valkey.New(cfg)
+	if err != nil {
+		t.Skip("Valkey not available:", err)
+	}
+	t.Cleanup(client.Close)
+
+	ctx := context.Background()
+	if err := client.Set(ctx, "test:typefilter:plain", "hello", 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := client.PFAdd(ctx, "test:typefilter:visitors", "a", "b"); err != nil {
+		t.Fatalf("PFAdd failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = client.Del(context.Background(), "test:typefilter:plain", "test:typefilter:visitors")
+	})
+
+	return New(cfg, client)
+}
+
+func TestKeysTypeFilter(t *testing.T) {
+	h := newTypeFilterHandler(t)
+
+	tests := []struct {
+		typeFilter string
+		want       []string
+	}{
+		{"string", []string{"test:typefilter:plain"}},
+		{"hyperloglog", []string{"test:typefilter:visitors"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeFilter, func(t *testing.T) {
+			var keys []string
+			cursor := "0"
+			for {
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/keys?count=1000&type="+tt.typeFilter+"&cursor="+cursor, nil))
+				var resp struct {
+					Keys   []string    `json:"keys"`
+					Cursor json.Number `json:"cursor"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("decode: %v (%s)", err, rec.Body.String())
+				}
+				keys = append(keys, resp.Keys...)
+				if cursor = resp.Cursor.String(); cursor == "0" {
+					break
+				}
+			}
+			slices.Sort(keys)
+			if !slices.Equal(keys, tt.want) {
+				t.Errorf("keys = %q, want %q", keys, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeysSortedTypeFilter(t *testing.T) {
+	h := newTypeFilterHandler(t)
+
+	tests := []struct {
+		typeFilter string
+		want       string
+	}{
+		{"string", "test:typefilter:plain"},
+		{"hyperloglog", "test:typefilter:visitors"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeFilter, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/keys?sort=name&count=100&type="+tt.typeFilter, nil))
+			var resp struct {
+				Keys []keyMeta `json:"keys"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode: %v (%s)", err, rec.Body.String())
+			}
+			if len(resp.Keys) != 1 || resp.Keys[0].Key != tt.want || resp.Keys[0].Type != tt.typeFilter {
+				t.Errorf("keys = %+v, want only %s as %s", resp.Keys, tt.want, tt.typeFilter)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package api
+
+import "fmt"
+
+// largeCollectionThreshold is the element count above which the key view warns
+const largeCollectionThreshold = 100_000
+
+// heavyEncodings maps types to the encoding the server converts them to once
+// they outgrow the compact listpack/intset forms, which uses far more memory
+var heavyEncodings = map[string]string{
+	"hash": "hashtable",
+	"set":  "hashtable",
+	"zset": "skiplist",
+	"list": "linkedlist",
+}
+
+// keyWarnings flags performance footguns for the key view from metadata
+// handleGetKey already has. encoding may be empty if it wasn't fetched.
+func keyWarnings(keyType, encoding string, length int64) []string {
+	var warnings []string
+	switch keyType {
+	case "string", "hyperloglog":
+		if length > maxStringPreview {
+			warnings = append(warnings, fmt.Sprintf("string value exceeds preview limit (%d KB)", maxStringPreview>>10))
+		}
+	default:
+		if length > largeCollectionThreshold {
+			warnings = append(warnings, fmt.Sprintf("large collection (%d elements); commands reading it all are slow", length))
+		}
+	}
+	if heavy, ok := heavyEncodings[keyType]; ok && encoding == heavy {
+		warnings = append(warnings, fmt.Sprintf("encoding converted to %s (memory heavy)", heavy))
+	}
+	return warnings
+}
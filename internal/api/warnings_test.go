@@ -0,0 +1,40 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyType  string
+		encoding string
+		length   int64
+		want     []string // substrings, in order
+	}{
+		{"small compact hash", "hash", "listpack", 10, nil},
+		{"converted hash", "hash", "hashtable", 10, []string{"hashtable"}},
+		{"large converted set", "set", "hashtable", 200_000, []string{"large collection", "hashtable"}},
+		{"converted zset", "zset", "skiplist", 500, []string{"skiplist"}},
+		{"intset is compact", "set", "intset", 500, nil},
+		{"stream size only", "stream", "", 150_000, []string{"large collection"}},
+		{"small string", "string", "", 100, nil},
+		{"huge string", "string", "", maxStringPreview + 1, []string{"preview limit"}},
+		{"string at the limit", "string", "", maxStringPreview, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := keyWarnings(tt.keyType, tt.encoding, tt.length)
+			if len(got) != len(tt.want) {
+				t.Fatalf("keyWarnings() = %q, want %d warnings", got, len(tt.want))
+			}
+			for i, sub := range tt.want {
+				if !strings.Contains(got[i], sub) {
+					t.Errorf("warning %d = %q, want it to mention %q", i, got[i], sub)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+// typeWritable reports whether keys of keyType may be written. HyperLogLog and
+// bitmap values are strings and geo sets are sorted sets, so they follow those.
+func (h *Handler) typeWritable(keyType string) bool {
+	return len(h.cfg.WritableTypes) == 0 || slices.Contains(h.cfg.WritableTypes, keyType)
+}
+
+// writableTypes lists the types the UI may edit, for /api/config
+func (h *Handler) writableTypes() []string {
+	switch {
+	case h.cfg.ReadOnly:
+		return []string{}
+	case len(h.cfg.WritableTypes) == 0:
+		return config.KeyTypes
+	}
+	return h.cfg.WritableTypes
+}
+
+// checkTypeWritable returns true and sends a 403 if keyType may not be written
+func (h *Handler) checkTypeWritable(w http.ResponseWriter, keyType string) bool {
+	if !h.typeWritable(keyType) {
+		jsonError(w, "Writing "+keyType+" keys is disabled", http.StatusForbidden)
+		return true
+	}
+	return false
+}
+
+// checkKeysWritable returns true and sends a 403 if any existing key has a type
+// that may not be written. Missing keys and keys outside the configured scope
+// pass; the handler creates or rejects them.
+func (h *Handler) checkKeysWritable(w http.ResponseWriter, ctx context.Context, keys ...string) bool {
+	if len(h.cfg.WritableTypes) == 0 {
+		return false
+	}
+	for _, key := range keys {
+		if !h.keyAllowed(key) {
+			continue
+		}
+		keyType, err := h.client.Type(ctx, key)
+		if err != nil {
+			errorResponse(w, err)
+			return true
+		}
+		if keyType != "none" && h.checkTypeWritable(w, keyType) {
+			return true
+		}
+	}
+	return false
+}
+
+// writableAs wraps a handler that writes keyType values into the path key
+func (h *Handler) writableAs(keyType string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.checkTypeWritable(w, keyType) {
+			return
+		}
+		next(w, r)
+	}
+}
+
+// writableKey wraps a handler that can change the path key whatever its type
+// (delete, expire, rename, overwrite)
+func (h *Handler) writableKey(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.checkKeysWritable(w, r.Context(), r.PathValue("key")) {
+			return
+		}
+		next(w, r)
+	}
+}
+
+// writableAll wraps a handler that can write keys of any type it can't predict
+// (restore from a dump or the trash, flush), so every type must be writable
+func (h *Handler) writableAll(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(h.cfg.WritableTypes) > 0 {
+			jsonError(w, "Disabled while only some types are writable", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+func TestWritableTypes(t *testing.T) {
+	// Every case is rejected before the (nil) client is used
+	h := New(&config.Config{WritableTypes: []string{"string", "hash"}}, nil)
+
+	tests := []struct {
+		name, method, path, body string
+	}{
+		{"list push", "POST", "/api/key/k/list", `{"value":"v"}`},
+		{"zset add", "POST", "/api/key/k/zset", `{"member":"m","score":1}`},
+		{"geo add", "POST", "/api/key/k/geo", `{"member":"m","longitude":1,"latitude":1}`},
+		{"stream add", "POST", "/api/key/k/stream", `{"fields":{"f":"v"}}`},
+		{"flush", "POST", "/api/flush", ``},
+		{"restore", "POST", "/api/key/k/restore", `{}`},
+		{"transaction zadd", "POST", "/api/transaction", `{"ops":[{"op":"zadd","key":"k","member":"m"}]}`},
+		{"console write", "POST", "/api/exec", `{"command":"LPUSH k v"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWritableTypesReported(t *testing.T) {
+	tests := []struct {
+		cfg  config.Config
+		want []string
+	}{
+		{config.Config{}, config.KeyTypes},
+		{config.Config{WritableTypes: []string{"string"}}, []string{"string"}},
+		{config.Config{ReadOnly: true, WritableTypes: []string{"string"}}, []string{}},
+	}
+
+	for _, tt := range tests {
+		h := New(&tt.cfg, nil)
+		if got := h.writableTypes(); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("writableTypes() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestMaxValueSize(t *testing.T) {
+	h := New(&config.Config{MaxValueSize: 8}, nil)
+
+	// Oversized writes are rejected before touching the (nil) client
+	tests := []struct {
+		method, path, body string
+	}{
+		{"PUT", "/api/key/k", `{"value":"123456789"}`},
+		{"POST", "/api/key/k/set", `{"member":"123456789"}`},
+		{"POST", "/api/key/k/hash", `{"field":"f","value":"123456789"}`},
+		{"POST", "/api/key/k/hash", `{"fields":{"f":"123456789"}}`},
+		{"POST", "/api/key/k/zset", `{"member":"123456789","score":1}`},
+		{"POST", "/api/key/k/list", `{"value":"123456789"}`},
+		{"POST", "/api/key/k/stream", `{"fields":{"f":"123456789"}}`},
+		{"POST", "/api/key/k/append", `{"value":"123456789"}`},
+		{"PATCH", "/api/key/k/range", `{"offset":4,"value":"12345"}`},
+		{"POST", "/api/key/k/hll", `{"elements":["a","123456789"]}`},
+		{"POST", "/api/key/k/geo", `{"member":"123456789","longitude":1,"latitude":1}`},
+		{"POST", "/api/key/k/restore", `{"payload":"MTIzNDU2Nzg5"}`},
+		{"POST", "/api/exec", `{"command":"SET k 123456789"}`},
+		{"POST", "/api/transaction", `{"ops":[{"op":"set","key":"a","value":"1"},{"op":"hset","key":"k","field":"f","value":"123456789"}]}`},
+		{"POST", "/api/import", `{"key":"k","type":"hash","value":{"f":"123456789"}}`},
+		{"POST", "/api/import", `{"key":"k","type":"stream","value":[{"fields":{"123456789":"v"}}]}`},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("%s %s %s: got %d, want 413", tt.method, tt.path, tt.body, rec.Code)
+		}
+	}
+}
+
+func TestWritableTypesBitField(t *testing.T) {
+	// Rejected before the (nil) client is used
+	h := New(&config.Config{WritableTypes: []string{"hash"}}, nil)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/api/key/k/bitfield", strings.NewReader(`{"ops":["GET u8 0","INCRBY u8 0 1"]}`)))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("bitfield write: expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestWritableTypesBitFieldGet(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	cfg := &config.Config{
+		ValkeyURL:     "localhost:6379",
+		ValkeyDB:      15, // Use DB 15 for testing
+		WritableTypes: []string{"hash"},
+	}
+	client, err := valkey.New(cfg)
+	if err != nil {
+		t.Skip("Valkey not available:", err)
+	}
+	defer client.Close()
+
+	h := New(cfg, client)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/api/key/test:writable:bits/bitfield", strings.NewReader(`{"ops":["GET u8 0"]}`)))
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET-only bitfield: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
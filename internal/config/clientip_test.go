@@ -0,0 +1,72 @@
+package config
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.5")
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies: %v", err)
+	}
+	cfg := &Config{TrustedProxies: proxies}
+
+	tests := []struct {
+		name   string
+		peer   string
+		fwd    string
+		realIP string
+		want   string
+	}{
+		{"direct client", "203.0.113.9:5000", "", "", "203.0.113.9"},
+		{"untrusted peer ignores headers", "203.0.113.9:5000", "198.51.100.1", "198.51.100.2", "203.0.113.9"},
+		{"trusted proxy", "10.0.0.2:5000", "198.51.100.1", "", "198.51.100.1"},
+		{"chained proxies", "10.0.0.2:5000", "198.51.100.1, 192.168.1.5", "", "198.51.100.1"},
+		{"spoofed leftmost entry", "10.0.0.2:5000", "1.2.3.4, 198.51.100.1", "", "198.51.100.1"},
+		{"all hops trusted", "10.0.0.2:5000", "10.0.0.3, 10.0.0.4", "", "10.0.0.3"},
+		{"real ip header", "192.168.1.5:5000", "", "198.51.100.1", "198.51.100.1"},
+		{"trusted proxy without headers", "10.0.0.2:5000", "", "", "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.peer
+			if tt.fwd != "" {
+				r.Header.Set("X-Forwarded-For", tt.fwd)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+			if got := cfg.ClientIP(r); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesInvalid(t *testing.T) {
+	for _, s := range []string{"not-an-ip", "10.0.0.0/33"} {
+		if _, err := ParseTrustedProxies(s); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestClientIPMultipleForwardedHeaders(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8")
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies: %v", err)
+	}
+	cfg := &Config{TrustedProxies: proxies}
+
+	// The client sends its own line; the proxy appends a second one
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.0.0.2:5000"
+	r.Header.Add("X-Forwarded-For", "1.2.3.4")
+	r.Header.Add("X-Forwarded-For", "198.51.100.1")
+	if got := cfg.ClientIP(r); got != "198.51.100.1" {
+		t.Errorf("ClientIP() = %q, want the proxy-added address", got)
+	}
+}
@@ -1,17 +1,33 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
 
 // Config holds all application configuration
 type Config struct {
 	// HTTP server settings
-	Host string
-	Port int
+	Host     string
+	Port     int
+	BasePath string // Subpath kvweb is served under behind a proxy, e.g. "/kvweb" ("" = root)
+
+	// Proxies whose X-Forwarded-For/X-Real-IP headers are believed (empty = none)
+	TrustedProxies []netip.Prefix
 
 	// Valkey/Redis connection
 	ValkeyURL      string
+	ValkeyUsername string // ACL user ("" = default user)
 	ValkeyPassword string
 	ValkeyDB       int
+	Retries        int  // Retries for read-only commands on transient errors (0 = none)
+	Cluster        bool // Require Valkey Cluster mode (detected and handled either way)
+
+	// Sentinel discovery: comma-separated Sentinel addresses ("" = connect to
+	// ValkeyURL directly) and the name of the primary they monitor
+	Sentinel       string
+	SentinelMaster string
 
 	// UI settings
 	OpenBrowser bool
@@ -20,11 +36,70 @@ type Config struct {
 	ReadOnly     bool
 	Prefix       string // Only show/allow keys matching this prefix
 	DisableFlush bool   // Block FLUSHDB even in write mode
+	DryRun       bool   // Log writes and return a synthetic success instead of running them
 	MaxKeys      int64  // Limit SCAN count to prevent UI overload (0 = no limit)
+	MaxValueSize int64  // Reject written values larger than this many bytes (0 = no limit)
+	ScanCount    int64  // Default SCAN COUNT hint (0 = built-in defaults)
 	CORSOrigin   string // Allowed CORS origin (default: same-origin only)
 
+	// Only show/allow keys matching one of these globs, on top of Prefix (empty = all)
+	AllowPatterns []string
+	// Hide and block keys matching any of these globs, even if otherwise allowed
+	DenyPatterns []string
+
+	// Only allow writes to keys of these types, from KeyTypes (empty = all)
+	WritableTypes []string
+
+	// Parallel pipelined batches when fetching key metadata (0 = default of 4)
+	MetaConcurrency int
+
+	// Seconds to cache key type/TTL for the key list and prefix tree (0 = disabled)
+	MetaCacheTTL int64
+
+	// Expensive scan-based endpoints allowed to run at once (0 = no limit)
+	MaxConcurrentScans int
+
+	// Seconds before a request's Valkey calls are cancelled with 504 (0 = no limit)
+	OpTimeout int64
+
+	// Allow closing other server connections via CLIENT KILL
+	AllowClientKill bool
+
+	// Allow changing server parameters (CONFIG SET) from the UI
+	EnableConfig bool
+
+	// Expose DEBUG OBJECT for keys (the server must also allow DEBUG)
+	EnableDebug bool
+
+	// Expose ACL users and their rules at /api/acl (password hashes are redacted)
+	EnableACL bool
+
+	// Stream MONITOR output at /api/monitor (requires MonitorToken)
+	EnableMonitor   bool
+	MonitorToken    string
+	MonitorDuration int64 // seconds before a MONITOR session stops (0 = default of 60)
+
+	// Require X-Kvweb-Confirm: yes on destructive requests (delete, flush, rename-over)
+	RequireConfirm bool
+
+	// Soft delete: back up deleted keys for this many seconds (0 = disabled)
+	SoftDeleteTTL int64
+
 	// WebSocket settings
 	Notifications bool // Auto-enable Valkey keyspace notifications for live updates
+	WSCompress    bool // Negotiate permessage-deflate on the WebSocket
+
+	// Send HTTP responses uncompressed even when the client accepts gzip
+	NoCompression bool
+
+	// Append successful write requests to this file as JSON lines (empty = disabled)
+	AuditLog string
+
+	// Serve Prometheus metrics at /metrics
+	Metrics bool
+
+	// Log every HTTP request (method, path, status, size, duration, client)
+	AccessLog bool
 
 	// Development
 	Dev bool // Skip serving embedded frontend
@@ -35,6 +110,10 @@ type Config struct {
 	Dirty   bool
 }
 
+// KeyTypes are the value types WritableTypes may list. HyperLogLog and bitmap
+// values are strings and geo sets are sorted sets.
+var KeyTypes = []string{"string", "list", "set", "hash", "zset", "stream"}
+
 // New creates a new Config with default values
 func New() *Config {
 	return &Config{
@@ -42,7 +121,48 @@ func New() *Config {
 		Port:      8080,
 		ValkeyURL: "localhost:6379",
 		ValkeyDB:  0,
+		Retries:   2,
+	}
+}
+
+// KeyScoped reports whether only some keys may be shown (Prefix or patterns set)
+func (c *Config) KeyScoped() bool {
+	return c.Prefix != "" || c.KeyPatterns()
+}
+
+// KeyPatterns reports whether allow or deny patterns are set. Unlike Prefix,
+// these can't be pushed into SCAN MATCH, so scan results must be filtered.
+func (c *Config) KeyPatterns() bool {
+	return len(c.AllowPatterns) > 0 || len(c.DenyPatterns) > 0
+}
+
+// KeyAllowed reports whether key is within Prefix, matches an allow pattern,
+// and matches no deny pattern
+func (c *Config) KeyAllowed(key string) bool {
+	return strings.HasPrefix(key, c.Prefix) && c.keyPermitted(key) && !c.KeyDenied(key)
+}
+
+// KeyDenied reports whether key matches a deny pattern
+func (c *Config) KeyDenied(key string) bool {
+	for _, pattern := range c.DenyPatterns {
+		if matchGlob(pattern, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyPermitted reports whether key matches an allow pattern (or none are set)
+func (c *Config) keyPermitted(key string) bool {
+	if len(c.AllowPatterns) == 0 {
+		return true
+	}
+	for _, pattern := range c.AllowPatterns {
+		if matchGlob(pattern, key) {
+			return true
+		}
 	}
+	return false
 }
 
 // Addr returns the HTTP server address
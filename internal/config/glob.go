@@ -0,0 +1,81 @@
+package config
+
+// matchGlob reports whether s matches pattern using the same rules as KEYS and
+// SCAN MATCH: * (any run), ? (one byte), [abc], [^a-z], and \ to escape
+func matchGlob(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 0 && pattern[0] == '*' {
+				pattern = pattern[1:]
+			}
+			if pattern == "" {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if matchGlob(pattern, s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if s == "" {
+				return false
+			}
+		case '[':
+			if s == "" {
+				return false
+			}
+			n, ok := matchClass(pattern[1:], s[0])
+			if !ok {
+				return false
+			}
+			pattern = pattern[n:]
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if s == "" || s[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern = pattern[1:]
+		s = s[1:]
+	}
+	return s == ""
+}
+
+// matchClass matches c against the bracket expression at the start of p (just
+// after the '['). It returns how many bytes of p the expression used,
+// including the closing ']', and whether c matched.
+func matchClass(p string, c byte) (int, bool) {
+	i := 0
+	negate := len(p) > 0 && p[0] == '^'
+	if negate {
+		i++
+	}
+	matched := false
+	for i < len(p) && p[i] != ']' {
+		switch {
+		case p[i] == '\\' && i+1 < len(p):
+			matched = matched || p[i+1] == c
+			i += 2
+		case i+2 < len(p) && p[i+1] == '-':
+			lo, hi := p[i], p[i+2]
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			matched = matched || (c >= lo && c <= hi)
+			i += 3
+		default:
+			matched = matched || p[i] == c
+			i++
+		}
+	}
+	if i < len(p) {
+		i++ // closing ]
+	}
+	return i, matched != negate
+}
@@ -0,0 +1,79 @@
+package config
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*", "", true},
+		{"*", "anything", true},
+		{"app1:*", "app1:user:1", true},
+		{"app1:*", "app2:user:1", false},
+		{"app1:*", "app1", false},
+		{"*:secret:*", "app:secret:token", true},
+		{"*:secret:*", "app:secrets", false},
+		{"user:?", "user:1", true},
+		{"user:?", "user:12", false},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-c]llo", "hbllo", true},
+		{"h[c-a]llo", "hbllo", true},
+		{"h[a-c]llo", "hdllo", false},
+		{`h\*llo`, "h*llo", true},
+		{`h\*llo`, "hello", false},
+		{`h[\]]llo`, "h]llo", true},
+		{"a**b", "axyzb", true},
+		{"a*b*c", "abxbc", true},
+		{"a*b*c", "abxbd", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestKeyAllowed(t *testing.T) {
+	cfg := &Config{AllowPatterns: []string{"app1:*", "app2:*"}}
+	for key, want := range map[string]bool{
+		"app1:a":  true,
+		"app2:b":  true,
+		"app3:c":  false,
+		"xapp1:a": false,
+	} {
+		if got := cfg.KeyAllowed(key); got != want {
+			t.Errorf("KeyAllowed(%q) = %v, want %v", key, got, want)
+		}
+	}
+
+	// The prefix and the patterns must both match
+	cfg.Prefix = "app1:"
+	if cfg.KeyAllowed("app2:b") {
+		t.Error("KeyAllowed(app2:b) = true outside the prefix")
+	}
+}
+
+func TestKeyDenied(t *testing.T) {
+	cfg := &Config{AllowPatterns: []string{"app:*"}, DenyPatterns: []string{"*:secret:*", "app:internal:*"}}
+	for key, want := range map[string]bool{
+		"app:user:1":     true,
+		"app:secret:key": false,
+		"app:internal:x": false,
+		"other:user:1":   false,
+	} {
+		if got := cfg.KeyAllowed(key); got != want {
+			t.Errorf("KeyAllowed(%q) = %v, want %v", key, got, want)
+		}
+	}
+
+	// Deny patterns apply without any allow patterns too
+	cfg.AllowPatterns = nil
+	if !cfg.KeyAllowed("other:user:1") || cfg.KeyAllowed("other:secret:1") {
+		t.Error("deny patterns without allow patterns filtered the wrong keys")
+	}
+}
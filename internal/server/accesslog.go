@@ -0,0 +1,55 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// logRequests wraps next, logging each request once it completes. Only the
+// path is logged, not the query, so tokens like /api/monitor's stay out of logs.
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		lw := &loggingWriter{ResponseWriter: w}
+		next.ServeHTTP(lw, r)
+		if lw.status == 0 {
+			lw.status = http.StatusOK
+		}
+		log.Printf("%s %s %s %d %dB %s", s.cfg.ClientIP(r), r.Method, r.URL.Path, lw.status, lw.bytes, time.Since(start).Round(time.Microsecond))
+	})
+}
+
+// loggingWriter records the status and body size of a response
+type loggingWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (lw *loggingWriter) WriteHeader(status int) {
+	if lw.status == 0 {
+		lw.status = status
+	}
+	lw.ResponseWriter.WriteHeader(status)
+}
+
+func (lw *loggingWriter) Write(p []byte) (int, error) {
+	if lw.status == 0 {
+		lw.status = http.StatusOK
+	}
+	n, err := lw.ResponseWriter.Write(p)
+	lw.bytes += int64(n)
+	return n, err
+}
+
+func (lw *loggingWriter) Flush() {
+	if f, ok := lw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets WebSocket upgrades reach the connection's Hijacker
+func (lw *loggingWriter) Unwrap() http.ResponseWriter {
+	return lw.ResponseWriter
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+func TestLogRequests(t *testing.T) {
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	proxies, _ := config.ParseTrustedProxies("10.0.0.0/8")
+	s := &Server{cfg: &config.Config{TrustedProxies: proxies}}
+	h := s.logRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest("GET", "/api/monitor?token=secret", nil)
+	req.RemoteAddr = "10.0.0.1:5000"
+	req.Header.Set("X-Forwarded-For", "203.0.113.9")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := out.String()
+	if want := "203.0.113.9 GET /api/monitor 418 5B"; !strings.Contains(line, want) {
+		t.Errorf("log line %q does not contain %q", line, want)
+	}
+	if strings.Contains(line, "secret") {
+		t.Errorf("log line %q leaks the query string", line)
+	}
+}
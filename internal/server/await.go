@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/natrimmer/kvweb/internal/valkey"
+	"github.com/natrimmer/kvweb/internal/ws"
+)
+
+const (
+	defaultAwaitTimeout = 30 * time.Second
+	maxAwaitTimeout     = 10 * time.Minute
+)
+
+// awaiter is a client waiting for a key to be created
+type awaiter struct {
+	client *ws.Client
+	done   chan struct{}
+}
+
+// handleClientMessage dispatches messages sent by WebSocket clients
+func (s *Server) handleClientMessage(ctx context.Context, c *ws.Client, msg ws.ClientMessage) {
+	switch msg.Type {
+	case "await_key":
+		s.awaitKey(ctx, c, msg)
+	case "subscribe":
+		if msg.Key == "" && msg.Prefix == "" {
+			c.SendMessage(ws.Message{Type: "error", Data: ws.ErrorData{Msg: "subscribe requires a key or prefix"}})
+			return
+		}
+		c.Subscribe(msg.Key, msg.Prefix, msg.Values)
+		c.SendMessage(ws.Message{Type: "subscribed", Data: ws.SubscriptionData{Key: msg.Key, Prefix: msg.Prefix, WithValues: msg.Values}})
+	case "unsubscribe":
+		c.Subscribe("", "", false)
+		c.SendMessage(ws.Message{Type: "subscribed", Data: ws.SubscriptionData{}})
+	case "pubsub_subscribe":
+		s.pubsubSubscribe(ctx, c, msg)
+	case "pubsub_unsubscribe":
+		s.pubsubUnsubscribe(c)
+	case "stream_tail":
+		s.streamTail(ctx, c, msg)
+	case "stream_untail":
+		s.streamUntail(c)
+	default:
+		c.SendMessage(ws.Message{Type: "error", Data: ws.ErrorData{Msg: "unknown message type: " + msg.Type}})
+	}
+}
+
+// awaitKey notifies the client with key_appeared once the key exists, or await_timeout
+func (s *Server) awaitKey(ctx context.Context, c *ws.Client, msg ws.ClientMessage) {
+	if msg.Key == "" {
+		c.SendMessage(ws.Message{Type: "error", Data: ws.ErrorData{Msg: "await_key requires a key"}})
+		return
+	}
+	if !s.cfg.KeyAllowed(msg.Key) {
+		c.SendMessage(ws.Message{Type: "error", Data: ws.ErrorData{Msg: "Key is outside the allowed prefix or patterns"}})
+		return
+	}
+
+	timeout := defaultAwaitTimeout
+	if msg.TimeoutMs > 0 {
+		timeout = min(time.Duration(msg.TimeoutMs)*time.Millisecond, maxAwaitTimeout)
+	}
+
+	// Register before checking existence so a key created in between isn't missed
+	a := &awaiter{client: c, done: make(chan struct{})}
+	s.awaitMu.Lock()
+	s.awaiters[msg.Key] = append(s.awaiters[msg.Key], a)
+	s.awaitMu.Unlock()
+
+	exists, err := s.client.Exists(ctx, msg.Key)
+	if err == nil && exists > 0 {
+		s.resolveAwaiters(msg.Key)
+		return
+	}
+
+	if !s.liveUpdates.Load() {
+		s.removeAwaiter(msg.Key, a)
+		c.SendMessage(ws.Message{Type: "error", Data: ws.ErrorData{Msg: "await_key requires live updates (keyspace notifications)"}})
+		return
+	}
+
+	go func() {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case <-a.done:
+		case <-timer.C:
+			if s.removeAwaiter(msg.Key, a) {
+				c.SendMessage(ws.Message{Type: "await_timeout", Data: ws.KeyData{Key: msg.Key}})
+			}
+		case <-ctx.Done():
+			s.removeAwaiter(msg.Key, a)
+		}
+	}()
+}
+
+// notifyAwaiters resolves waiters for a key when an event shows it now exists
+func (s *Server) notifyAwaiters(event valkey.KeyEvent) {
+	switch event.Operation {
+	case "del", "expired", "evicted", "rename_from":
+		return // Key removed, not created
+	}
+	s.resolveAwaiters(event.Key)
+}
+
+// resolveAwaiters sends key_appeared to every client waiting for key
+func (s *Server) resolveAwaiters(key string) {
+	s.awaitMu.Lock()
+	waiting := s.awaiters[key]
+	delete(s.awaiters, key)
+	s.awaitMu.Unlock()
+
+	for _, a := range waiting {
+		close(a.done)
+		a.client.SendMessage(ws.Message{Type: "key_appeared", Data: ws.KeyData{Key: key}})
+	}
+}
+
+// removeAwaiter unregisters a waiter, returning false if it was already resolved
+func (s *Server) removeAwaiter(key string, a *awaiter) bool {
+	s.awaitMu.Lock()
+	defer s.awaitMu.Unlock()
+
+	waiting := s.awaiters[key]
+	for i, w := range waiting {
+		if w == a {
+			s.awaiters[key] = append(waiting[:i], waiting[i+1:]...)
+			if len(s.awaiters[key]) == 0 {
+				delete(s.awaiters, key)
+			}
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// minCompressSize is the smallest response worth gzipping; below it the
+// framing overhead outweighs the savings
+const minCompressSize = 1024
+
+var gzipWriters = sync.Pool{
+	New: func() any { return gzip.NewWriter(nil) },
+}
+
+// compress gzips responses of at least minCompressSize bytes for clients that
+// accept it. WebSocket upgrades, range requests, and bodies that are already
+// compressed pass through untouched.
+func compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !acceptsGzip(r) || r.Header.Get("Upgrade") != "" || r.Header.Get("Range") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+		cw := &compressWriter{ResponseWriter: w, status: http.StatusOK, head: r.Method == http.MethodHead}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding allows gzip
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			return strings.ReplaceAll(params, " ", "") != "q=0"
+		}
+	}
+	return false
+}
+
+// compressible reports whether a response with these headers should be gzipped
+func compressible(h http.Header) bool {
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	ct := h.Get("Content-Type")
+	switch {
+	case strings.HasPrefix(ct, "text/"),
+		strings.HasPrefix(ct, "application/json"),
+		strings.HasPrefix(ct, "application/javascript"),
+		strings.HasPrefix(ct, "application/x-ndjson"),
+		strings.HasPrefix(ct, "image/svg+xml"):
+		return true
+	}
+	return false
+}
+
+// compressWriter buffers the start of a response until it knows whether it is
+// big and compressible enough, then either gzips or passes everything through
+type compressWriter struct {
+	http.ResponseWriter
+	status      int
+	head        bool
+	wroteHeader bool
+	decided     bool
+	buf         bytes.Buffer
+	gz          *gzip.Writer
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = status
+	// Bodiless responses have nothing to compress
+	if cw.head || status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.decided {
+		cw.buf.Write(p)
+		if cw.buf.Len() < minCompressSize {
+			return len(p), nil
+		}
+		if cw.Header().Get("Content-Type") == "" {
+			cw.Header().Set("Content-Type", http.DetectContentType(cw.buf.Bytes()))
+		}
+		if err := cw.decide(compressible(cw.Header())); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if cw.gz != nil {
+		return cw.gz.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// decide sends the headers and anything buffered, compressed or not
+func (cw *compressWriter) decide(gzipped bool) error {
+	cw.decided = true
+	if gzipped {
+		h := cw.Header()
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		// The compressed bytes differ, so a strong validator must become weak
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+		cw.gz = gzipWriters.Get().(*gzip.Writer)
+		cw.gz.Reset(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if cw.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if cw.gz != nil {
+		_, err = cw.gz.Write(cw.buf.Bytes())
+	} else {
+		_, err = cw.ResponseWriter.Write(cw.buf.Bytes())
+	}
+	cw.buf.Reset()
+	return err
+}
+
+// close flushes a small buffered response as-is or finishes the gzip stream
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		if !cw.wroteHeader && cw.buf.Len() == 0 {
+			return // nothing written; let the server send its default response
+		}
+		_ = cw.decide(false)
+	}
+	if cw.gz != nil {
+		_ = cw.gz.Close()
+		gzipWriters.Put(cw.gz)
+		cw.gz = nil
+	}
+}
+
+// Flush sends what has been written so far, giving up on compression if the
+// handler flushes before the size threshold is reached
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			cw.WriteHeader(http.StatusOK)
+		}
+		if !cw.decided {
+			_ = cw.decide(false)
+		}
+	}
+	if cw.gz != nil {
+		_ = cw.gz.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/api"
+	"github.com/natrimmer/kvweb/internal/config"
+	"github.com/natrimmer/kvweb/internal/valkey"
+)
+
+func TestCompress(t *testing.T) {
+	big := `{"value":"` + strings.Repeat("a", 4096) + `"}`
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		accept      string
+		upgrade     bool
+		want        bool
+	}{
+		{"large json", "application/json", big, "gzip, deflate, br", false, true},
+		{"small json", "application/json", `{"ok":true}`, "gzip", false, false},
+		{"no gzip accepted", "application/json", big, "br", false, false},
+		{"gzip refused", "application/json", big, "gzip;q=0", false, false},
+		{"already compressed image", "image/png", big, "gzip", false, false},
+		{"websocket upgrade", "application/json", big, "gzip", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = io.WriteString(w, tt.body)
+			}))
+
+			req := httptest.NewRequest("GET", "/api/key/k", nil)
+			req.Header.Set("Accept-Encoding", tt.accept)
+			if tt.upgrade {
+				req.Header.Set("Upgrade", "websocket")
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			gzipped := rec.Header().Get("Content-Encoding") == "gzip"
+			if gzipped != tt.want {
+				t.Fatalf("gzipped = %v, want %v", gzipped, tt.want)
+			}
+
+			body := rec.Body.String()
+			if gzipped {
+				zr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				data, err := io.ReadAll(zr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = string(data)
+			}
+			if body != tt.body {
+				t.Errorf("body mismatch: got %d bytes, want %d", len(body), len(tt.body))
+			}
+		})
+	}
+}
+
+func TestCompressKeepsStatusAndWeakensETag(t *testing.T) {
+	h := compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("ETag", `"abc"`)
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, strings.Repeat("<p>missing</p>", 200))
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+	if got := rec.Header().Get("ETag"); got != `W/"abc"` {
+		t.Errorf("ETag = %q, want weak", got)
+	}
+}
+
+// A gzipped GET weakens the value's ETag; echoing it back in If-Match must
+// still let the save through
+func TestCompressedETagIfMatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	cfg := &config.Config{
+		ValkeyURL: "localhost:6379",
+		ValkeyDB:  15, // Use DB 15 for testing
+	}
+	client, err := valkey.New(cfg)
+	if err != nil {
+		t.Skip("Valkey not available:", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	const key = "test:compress:etag"
+	if err := client.Set(ctx, key, strings.Repeat("a", 4096), 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	defer func() { _, _ = client.Del(ctx, key) }()
+
+	h := compress(api.New(cfg, client))
+
+	get := httptest.NewRequest("GET", "/api/key/"+key, nil)
+	get.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, get)
+	etag := rec.Header().Get("ETag")
+	if rec.Header().Get("Content-Encoding") != "gzip" || !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("GET Content-Encoding = %q, ETag = %q; want a gzipped response with a weak ETag",
+			rec.Header().Get("Content-Encoding"), etag)
+	}
+
+	put := httptest.NewRequest("PUT", "/api/key/"+key, strings.NewReader(`{"value":"b"}`))
+	put.Header.Set("Content-Type", "application/json")
+	put.Header.Set("If-Match", etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, put)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT with If-Match %s: status = %d, body = %s", etag, rec.Code, rec.Body.String())
+	}
+
+	// The ETag is now stale, so a second save must be refused
+	rec = httptest.NewRecorder()
+	put = httptest.NewRequest("PUT", "/api/key/"+key, strings.NewReader(`{"value":"c"}`))
+	put.Header.Set("If-Match", etag)
+	h.ServeHTTP(rec, put)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("PUT with stale If-Match: status = %d, want 412", rec.Code)
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"strconv"
+)
+
+// hitTracker turns the cumulative keyspace_hits/keyspace_misses counters into a
+// ratio over the last stats interval
+type hitTracker struct {
+	hits, misses int64
+	seen         bool
+}
+
+// update records the latest totals and returns the hit ratio since the previous
+// call, or nil when there is no previous sample or no lookups happened in between
+func (t *hitTracker) update(hits, misses int64) *float64 {
+	prevHits, prevMisses, seen := t.hits, t.misses, t.seen
+	t.hits, t.misses, t.seen = hits, misses, true
+
+	// A drop means CONFIG RESETSTAT or a server restart; start over from here
+	if !seen || hits < prevHits || misses < prevMisses {
+		return nil
+	}
+
+	dh, dm := hits-prevHits, misses-prevMisses
+	if dh+dm == 0 {
+		return nil
+	}
+	ratio := float64(dh) / float64(dh+dm)
+	return &ratio
+}
+
+// keyspaceCounters reads keyspace_hits and keyspace_misses from INFO stats
+func (s *Server) keyspaceCounters(ctx context.Context) (hits, misses int64, err error) {
+	info, err := s.client.InfoMap(ctx, "stats")
+	if err != nil {
+		return 0, 0, err
+	}
+	hits, _ = strconv.ParseInt(info["stats"]["keyspace_hits"], 10, 64)
+	misses, _ = strconv.ParseInt(info["stats"]["keyspace_misses"], 10, 64)
+	return hits, misses, nil
+}
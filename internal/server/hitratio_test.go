@@ -0,0 +1,30 @@
+package server
+
+import "testing"
+
+func TestHitTracker(t *testing.T) {
+	var tr hitTracker
+
+	if r := tr.update(100, 100); r != nil {
+		t.Errorf("first sample: expected nil, got %v", *r)
+	}
+
+	// 30 hits, 10 misses since the last tick
+	r := tr.update(130, 110)
+	if r == nil || *r != 0.75 {
+		t.Errorf("expected 0.75, got %v", r)
+	}
+
+	if r := tr.update(130, 110); r != nil {
+		t.Errorf("idle interval: expected nil, got %v", *r)
+	}
+
+	// Counters reset (CONFIG RESETSTAT): no ratio until the next interval
+	if r := tr.update(5, 0); r != nil {
+		t.Errorf("after reset: expected nil, got %v", *r)
+	}
+	r = tr.update(5, 5)
+	if r == nil || *r != 0 {
+		t.Errorf("expected 0, got %v", r)
+	}
+}
@@ -0,0 +1,37 @@
+package server
+
+import "sync"
+
+// latencyHistorySize is how many ping samples are kept (5 minutes at the 5s stats interval)
+const latencyHistorySize = 60
+
+// latencyRing is a fixed-size buffer of recent ping round-trip times in milliseconds
+type latencyRing struct {
+	mu      sync.Mutex
+	samples [latencyHistorySize]float64
+	next    int
+	count   int
+}
+
+// add records a sample, overwriting the oldest once full
+func (r *latencyRing) add(ms float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples[r.next] = ms
+	r.next = (r.next + 1) % latencyHistorySize
+	if r.count < latencyHistorySize {
+		r.count++
+	}
+}
+
+// snapshot returns the recorded samples, oldest first
+func (r *latencyRing) snapshot() []float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]float64, 0, r.count)
+	start := (r.next - r.count + latencyHistorySize) % latencyHistorySize
+	for i := 0; i < r.count; i++ {
+		out = append(out, r.samples[(start+i)%latencyHistorySize])
+	}
+	return out
+}
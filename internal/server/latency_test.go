@@ -0,0 +1,32 @@
+package server
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLatencyRing(t *testing.T) {
+	var r latencyRing
+
+	if got := r.snapshot(); len(got) != 0 {
+		t.Fatalf("expected empty snapshot, got %v", got)
+	}
+
+	r.add(1)
+	r.add(2)
+	if got := r.snapshot(); !slices.Equal(got, []float64{1, 2}) {
+		t.Errorf("snapshot = %v, want [1 2]", got)
+	}
+
+	// Overfill by 3: the oldest samples drop off and order stays oldest-first
+	for i := 3; i <= latencyHistorySize+3; i++ {
+		r.add(float64(i))
+	}
+	got := r.snapshot()
+	if len(got) != latencyHistorySize {
+		t.Fatalf("expected %d samples, got %d", latencyHistorySize, len(got))
+	}
+	if got[0] != 4 || got[len(got)-1] != latencyHistorySize+3 {
+		t.Errorf("snapshot spans %v..%v, want 4..%d", got[0], got[len(got)-1], latencyHistorySize+3)
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// infoMetric maps an INFO field to a Prometheus metric
+type infoMetric struct {
+	section string
+	field   string
+	name    string
+	kind    string // gauge or counter
+	help    string
+}
+
+var infoMetrics = []infoMetric{
+	{"memory", "used_memory", "kvweb_valkey_used_memory_bytes", "gauge", "Memory allocated by the server"},
+	{"clients", "connected_clients", "kvweb_valkey_connected_clients", "gauge", "Client connections to the server"},
+	{"stats", "instantaneous_ops_per_sec", "kvweb_valkey_ops_per_sec", "gauge", "Commands processed per second"},
+	{"stats", "keyspace_hits", "kvweb_valkey_keyspace_hits_total", "counter", "Successful key lookups"},
+	{"stats", "keyspace_misses", "kvweb_valkey_keyspace_misses_total", "counter", "Failed key lookups"},
+}
+
+// countRequests wraps next, counting every HTTP request for /metrics
+func (s *Server) countRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleMetrics serves server and kvweb metrics in the Prometheus text format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	info, err := s.client.InfoMap(r.Context(), "all")
+	up := 1
+	if err != nil {
+		log.Printf("Metrics: INFO error: %v", err)
+		up = 0
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeMetrics(w, info, up, s.requests.Load(), s.wsHub.ClientCount())
+}
+
+// writeMetrics renders the exposition. INFO-derived metrics are omitted when info is nil.
+func writeMetrics(w io.Writer, info map[string]map[string]string, up int, requests int64, wsClients int) {
+	writeMetric(w, "kvweb_valkey_up", "gauge", "Whether the last INFO query succeeded", strconv.Itoa(up))
+
+	for _, m := range infoMetrics {
+		value, ok := info[m.section][m.field]
+		if !ok {
+			continue
+		}
+		writeMetric(w, m.name, m.kind, m.help, value)
+	}
+
+	// Keyspace lines look like "db0:keys=10,expires=2,avg_ttl=0"
+	if keyspace := info["keyspace"]; len(keyspace) > 0 {
+		dbs := make([]string, 0, len(keyspace))
+		for db := range keyspace {
+			dbs = append(dbs, db)
+		}
+		sort.Strings(dbs)
+
+		_, _ = fmt.Fprintf(w, "# HELP kvweb_valkey_db_keys Keys per database\n# TYPE kvweb_valkey_db_keys gauge\n")
+		for _, db := range dbs {
+			for _, pair := range strings.Split(keyspace[db], ",") {
+				if keys, ok := strings.CutPrefix(pair, "keys="); ok {
+					_, _ = fmt.Fprintf(w, "kvweb_valkey_db_keys{db=%q} %s\n", strings.TrimPrefix(db, "db"), keys)
+				}
+			}
+		}
+	}
+
+	writeMetric(w, "kvweb_http_requests_total", "counter", "HTTP requests served by kvweb", strconv.FormatInt(requests, 10))
+	writeMetric(w, "kvweb_websocket_clients", "gauge", "Connected WebSocket clients", strconv.Itoa(wsClients))
+}
+
+func writeMetric(w io.Writer, name, kind, help, value string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %s\n", name, help, name, kind, name, value)
+}
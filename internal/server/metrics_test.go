@@ -0,0 +1,51 @@
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	info := map[string]map[string]string{
+		"memory":   {"used_memory": "1048576"},
+		"stats":    {"keyspace_hits": "90", "keyspace_misses": "10"},
+		"keyspace": {"db0": "keys=12,expires=3,avg_ttl=0", "db2": "keys=5,expires=0,avg_ttl=0"},
+	}
+
+	var b strings.Builder
+	writeMetrics(&b, info, 1, 7, 2)
+	out := b.String()
+
+	for _, want := range []string{
+		"kvweb_valkey_up 1\n",
+		"# TYPE kvweb_valkey_used_memory_bytes gauge\nkvweb_valkey_used_memory_bytes 1048576\n",
+		"# TYPE kvweb_valkey_keyspace_hits_total counter\nkvweb_valkey_keyspace_hits_total 90\n",
+		"kvweb_valkey_keyspace_misses_total 10\n",
+		"kvweb_valkey_db_keys{db=\"0\"} 12\n",
+		"kvweb_valkey_db_keys{db=\"2\"} 5\n",
+		"kvweb_http_requests_total 7\n",
+		"kvweb_websocket_clients 2\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+
+	// Fields absent from INFO are skipped rather than reported as zero
+	if strings.Contains(out, "kvweb_valkey_connected_clients") {
+		t.Error("expected connected_clients to be omitted")
+	}
+}
+
+func TestWriteMetricsDown(t *testing.T) {
+	var b strings.Builder
+	writeMetrics(&b, nil, 0, 0, 0)
+	out := b.String()
+
+	if !strings.Contains(out, "kvweb_valkey_up 0\n") {
+		t.Errorf("expected kvweb_valkey_up 0, got\n%s", out)
+	}
+	if strings.Contains(out, "kvweb_valkey_db_keys") {
+		t.Error("expected no keyspace metrics without INFO")
+	}
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/natrimmer/kvweb/internal/ws"
+)
+
+// defaultMonitorDuration applies when --monitor-duration isn't set
+const defaultMonitorDuration = 60 * time.Second
+
+// monitorAuthorized checks the monitor token from ?token= (browsers can't set headers
+// on WebSocket requests) or an Authorization: Bearer header
+func (s *Server) monitorAuthorized(r *http.Request) bool {
+	token := r.URL.Query().Get("token")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token = bearer
+	}
+	return s.cfg.MonitorToken != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.MonitorToken)) == 1
+}
+
+// handleMonitor streams MONITOR output over a WebSocket. MONITOR makes the server
+// copy every command to this connection, which can cut throughput by half on a busy
+// instance, so only one session runs at a time and it stops after the configured
+// duration (or ?seconds=, if shorter).
+func (s *Server) handleMonitor(w http.ResponseWriter, r *http.Request) {
+	if !s.cfg.EnableMonitor {
+		http.Error(w, "MONITOR is disabled (start with --enable-monitor)", http.StatusForbidden)
+		return
+	}
+	// MONITOR shows every key on the server, which --prefix and key patterns must not leak
+	if s.cfg.KeyScoped() {
+		http.Error(w, "MONITOR is unavailable when --prefix or key patterns are set", http.StatusForbidden)
+		return
+	}
+	if !s.monitorAuthorized(r) {
+		http.Error(w, "Invalid monitor token", http.StatusUnauthorized)
+		return
+	}
+
+	duration := defaultMonitorDuration
+	if s.cfg.MonitorDuration > 0 {
+		duration = time.Duration(s.cfg.MonitorDuration) * time.Second
+	}
+	if v := r.URL.Query().Get("seconds"); v != "" {
+		secs, err := strconv.Atoi(v)
+		if err != nil || secs < 1 {
+			http.Error(w, "seconds must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		duration = min(duration, time.Duration(secs)*time.Second)
+	}
+
+	if !s.monitorActive.CompareAndSwap(false, true) {
+		http.Error(w, "A MONITOR session is already running", http.StatusConflict)
+		return
+	}
+
+	conn, err := websocket.Accept(w, r, s.acceptOptions())
+	if err != nil {
+		s.monitorActive.Store(false)
+		log.Printf("Monitor WebSocket accept error: %v", err)
+		return
+	}
+
+	client := ws.NewClient(s.monitorHub, conn)
+	s.monitorHub.Register(client)
+
+	ctx := r.Context()
+	monitorCtx, cancel := context.WithTimeout(ctx, duration)
+	log.Printf("MONITOR session started from %s for %s", s.cfg.ClientIP(r), duration)
+
+	go func() {
+		defer cancel()
+		defer s.monitorActive.Store(false)
+
+		err := s.client.Monitor(monitorCtx, func(line string) {
+			// A slow browser gets dropped by Send rather than buffering without bound
+			client.SendMessage(ws.Message{Type: "monitor", Data: ws.MonitorData{Line: line}})
+		})
+
+		reason := "duration elapsed"
+		if err != nil {
+			reason = err.Error()
+		} else if ctx.Err() != nil {
+			reason = "client disconnected"
+		}
+		log.Printf("MONITOR session ended: %s", reason)
+		client.SendMessage(ws.Message{Type: "monitor_stopped", Data: ws.StatusData{Msg: reason}})
+	}()
+
+	go client.WritePump(ctx)
+	client.ReadPump(ctx) // Blocks until disconnect
+}
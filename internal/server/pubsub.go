@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/natrimmer/kvweb/internal/ws"
+)
+
+const (
+	// maxPubSubSubscriptions caps concurrent pub/sub watchers; each holds its own server connection
+	maxPubSubSubscriptions = 16
+
+	// maxPubSubMessageSize caps message payloads forwarded to the browser
+	maxPubSubMessageSize = 4096
+)
+
+// pubsubWatch is one client's pub/sub subscription
+type pubsubWatch struct {
+	cancel context.CancelFunc
+}
+
+// pubsubSubscribe starts forwarding messages on msg.Channel to the client, replacing
+// any pub/sub subscription it already had
+func (s *Server) pubsubSubscribe(ctx context.Context, c *ws.Client, msg ws.ClientMessage) {
+	if msg.Channel == "" {
+		c.SendMessage(ws.Message{Type: "error", Data: ws.ErrorData{Msg: "pubsub_subscribe requires a channel"}})
+		return
+	}
+
+	s.pubsubMu.Lock()
+	if old, ok := s.pubsubs[c]; ok {
+		old.cancel()
+		delete(s.pubsubs, c)
+	}
+	if len(s.pubsubs) >= maxPubSubSubscriptions {
+		s.pubsubMu.Unlock()
+		c.SendMessage(ws.Message{Type: "error", Data: ws.ErrorData{Msg: "Too many pub/sub subscriptions"}})
+		return
+	}
+	subCtx, cancel := context.WithCancel(ctx)
+	watch := &pubsubWatch{cancel: cancel}
+	s.pubsubs[c] = watch
+	s.pubsubMu.Unlock()
+
+	messages := s.client.Subscribe(subCtx, msg.Channel, msg.Pattern)
+	c.SendMessage(ws.Message{Type: "pubsub_subscribed", Data: ws.PubSubSubscriptionData{Channel: msg.Channel, Pattern: msg.Pattern}})
+
+	go func() {
+		for m := range messages {
+			// Keyspace notification channels name keys, which --prefix and key patterns must not leak
+			if s.cfg.KeyScoped() && strings.HasPrefix(m.Channel, "__key") {
+				continue
+			}
+
+			data := ws.PubSubData{Channel: m.Channel, Pattern: m.Pattern, Message: m.Message}
+			if len(data.Message) > maxPubSubMessageSize {
+				data.Message = data.Message[:maxPubSubMessageSize]
+				data.Truncated = true
+			}
+			c.SendMessage(ws.Message{Type: "pubsub_message", Data: data})
+		}
+
+		// The server closed the subscription if nobody cancelled it
+		unexpected := subCtx.Err() == nil
+		cancel()
+
+		s.pubsubMu.Lock()
+		// Only clean up if this subscription hasn't been replaced by a newer one
+		if s.pubsubs[c] == watch {
+			delete(s.pubsubs, c)
+		}
+		s.pubsubMu.Unlock()
+
+		if unexpected {
+			c.SendMessage(ws.Message{Type: "error", Data: ws.ErrorData{Msg: "pub/sub subscription to " + msg.Channel + " ended"}})
+		}
+	}()
+}
+
+// pubsubUnsubscribe stops the client's pub/sub subscription, if any
+func (s *Server) pubsubUnsubscribe(c *ws.Client) {
+	s.pubsubMu.Lock()
+	if watch, ok := s.pubsubs[c]; ok {
+		watch.cancel()
+		delete(s.pubsubs, c)
+	}
+	s.pubsubMu.Unlock()
+
+	c.SendMessage(ws.Message{Type: "pubsub_subscribed", Data: ws.PubSubSubscriptionData{}})
+}
@@ -4,9 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -20,23 +21,40 @@ import (
 
 // Server represents the HTTP server
 type Server struct {
-	cfg         *config.Config
-	client      *valkey.Client
-	http        *http.Server
-	wsHub       *ws.Hub
-	apiHandler  *api.Handler
-	keyEvents   <-chan valkey.KeyEvent
-	liveUpdates atomic.Bool
-	cancelFunc  context.CancelFunc
-	ctx         context.Context
+	cfg           *config.Config
+	client        *valkey.Client
+	http          *http.Server
+	wsHub         *ws.Hub
+	apiHandler    *api.Handler
+	keyEvents     <-chan valkey.KeyEvent
+	liveUpdates   atomic.Bool
+	cancelFunc    context.CancelFunc
+	ctx           context.Context
+	awaiters      map[string][]*awaiter // Clients waiting for a key to be created
+	awaitMu       sync.Mutex
+	requests      atomic.Int64                // HTTP requests served, for /metrics
+	latency       latencyRing                 // Recent ping round-trip times for the stats chart
+	hits          hitTracker                  // Keyspace hit/miss totals from the previous stats tick
+	pubsubs       map[*ws.Client]*pubsubWatch // Active pub/sub watchers, one per client
+	pubsubMu      sync.Mutex
+	tails         map[*ws.Client]*streamTailWatch // Active stream tails, one per client
+	tailsMu       sync.Mutex
+	monitorHub    *ws.Hub     // Clients of the MONITOR stream, kept apart from regular broadcasts
+	monitorActive atomic.Bool // Only one MONITOR session runs at a time
 }
 
 // New creates a new Server
 func New(cfg *config.Config, client *valkey.Client) *Server {
 	s := &Server{
-		cfg:    cfg,
-		client: client,
-		wsHub:  ws.NewHub(),
+		cfg:      cfg,
+		client:   client,
+		wsHub:    ws.NewHub(),
+		awaiters: make(map[string][]*awaiter),
+		pubsubs:  make(map[*ws.Client]*pubsubWatch),
+		tails:    make(map[*ws.Client]*streamTailWatch),
+	}
+	if cfg.EnableMonitor {
+		s.monitorHub = ws.NewHub()
 	}
 
 	mux := http.NewServeMux()
@@ -45,11 +63,20 @@ func New(cfg *config.Config, client *valkey.Client) *Server {
 	s.apiHandler = api.New(cfg, client)
 	s.apiHandler.SetOnNotificationsEnabled(s.enableLiveUpdates)
 	s.apiHandler.SetOnNotificationsDisabled(s.disableLiveUpdates)
+	s.apiHandler.SetOnProgress(s.broadcastProgress)
 	mux.Handle("/api/", s.apiHandler)
 
 	// WebSocket for real-time updates
 	mux.HandleFunc("/ws", s.handleWebSocket)
 
+	// MONITOR stream; handleMonitor rejects requests unless --enable-monitor is set
+	mux.HandleFunc("GET /api/monitor", s.handleMonitor)
+
+	// Prometheus scrape endpoint
+	if cfg.Metrics {
+		mux.HandleFunc("GET /metrics", s.handleMetrics)
+	}
+
 	// Static files (embedded Svelte app) — skip in dev mode
 	if cfg.Dev {
 		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -58,12 +85,30 @@ func New(cfg *config.Config, client *valkey.Client) *Server {
 			_, _ = fmt.Fprintf(w, "kvweb backend (dev mode)\n\nFrontend is at http://localhost:5173\nThis port only serves /api and /ws")
 		})
 	} else {
-		mux.Handle("/", static.Handler())
+		mux.Handle("/", static.Handler(cfg.BasePath))
+	}
+
+	var handler http.Handler = mux
+	if cfg.BasePath != "" {
+		// Routes are registered at the root; strip the proxy subpath before them.
+		// The mux redirects the bare base path to its trailing-slash form.
+		root := http.NewServeMux()
+		root.Handle(cfg.BasePath+"/", http.StripPrefix(cfg.BasePath, mux))
+		handler = root
+	}
+	if !cfg.NoCompression {
+		handler = compress(handler)
+	}
+	if cfg.Metrics {
+		handler = s.countRequests(handler)
+	}
+	if cfg.AccessLog {
+		handler = s.logRequests(handler)
 	}
 
 	s.http = &http.Server{
 		Addr:         cfg.Addr(),
-		Handler:      mux,
+		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 0, // Disable for WebSocket
 		IdleTimeout:  60 * time.Second,
@@ -72,27 +117,34 @@ func New(cfg *config.Config, client *valkey.Client) *Server {
 	return s
 }
 
-// initNotifications checks and optionally enables keyspace notifications
-func (s *Server) initNotifications(ctx context.Context) {
-	// Check current setting
+// ensureNotifications returns the server's keyspace notification setting,
+// first enabling notifications if --notifications is set and they're off
+func (s *Server) ensureNotifications(ctx context.Context) (string, error) {
 	current, err := s.client.GetNotifyKeyspaceEvents(ctx)
 	if err != nil {
-		log.Printf("Warning: Could not check keyspace notifications: %v", err)
-		return
+		return "", fmt.Errorf("could not check keyspace notifications: %w", err)
 	}
 
-	// Auto-enable if flag set and not already enabled
 	if s.cfg.Notifications && current == "" {
 		// K = Keyspace events, E = Keyevent events
 		// A = all commands (includes HyperLogLog which has no dedicated flag)
 		// g = generic (DEL, EXPIRE, RENAME), e = expired, x = evicted
 		if err := s.client.SetNotifyKeyspaceEvents(ctx, "KEAgex"); err != nil {
-			log.Printf("Warning: Could not enable keyspace notifications: %v", err)
-			return
+			return "", fmt.Errorf("could not enable keyspace notifications: %w", err)
 		}
 		current = "KEAgex"
 		log.Println("Enabled Valkey keyspace notifications")
 	}
+	return current, nil
+}
+
+// initNotifications checks and optionally enables keyspace notifications
+func (s *Server) initNotifications(ctx context.Context) {
+	current, err := s.ensureNotifications(ctx)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return
+	}
 
 	// Start subscriber if notifications are enabled
 	if current != "" {
@@ -118,6 +170,9 @@ func (s *Server) Start() error {
 
 	// Start WebSocket hub
 	go s.wsHub.Run()
+	if s.monitorHub != nil {
+		go s.monitorHub.Run()
+	}
 
 	// Start event broadcaster if live updates enabled
 	if s.liveUpdates.Load() {
@@ -176,6 +231,19 @@ func (s *Server) disableLiveUpdates() {
 	})
 }
 
+// broadcastProgress sends scan progress to all connected clients
+func (s *Server) broadcastProgress(op string, scanned, total int64, done bool) {
+	s.wsHub.Broadcast(ws.Message{
+		Type: "progress",
+		Data: ws.ProgressData{Op: op, Scanned: scanned, Total: total, Done: done},
+	})
+}
+
+// SetAuditLog records successful write requests to w (see api.Handler.SetAuditLog)
+func (s *Server) SetAuditLog(w io.Writer) {
+	s.apiHandler.SetAuditLog(w)
+}
+
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown() error {
 	if s.cancelFunc != nil {
@@ -186,24 +254,34 @@ func (s *Server) Shutdown() error {
 	return s.http.Shutdown(ctx)
 }
 
-// runEventBroadcaster broadcasts keyspace events to all WebSocket clients
+// runEventBroadcaster broadcasts keyspace events to all WebSocket clients.
+// If the subscription drops (e.g. Valkey restarted), it resubscribes with backoff.
 func (s *Server) runEventBroadcaster(ctx context.Context) {
 	for {
 		select {
 		case event, ok := <-s.keyEvents:
 			if !ok {
-				return
+				if ctx.Err() != nil || !s.liveUpdates.Load() || !s.resubscribe(ctx) {
+					return
+				}
+				continue
 			}
-			// Filter by prefix if configured
-			if s.cfg.Prefix != "" && !strings.HasPrefix(event.Key, s.cfg.Prefix) {
+			s.apiHandler.InvalidateKey(event.Key)
+			// Filter by prefix and allow patterns if configured
+			if !s.cfg.KeyAllowed(event.Key) {
 				continue
 			}
+			s.notifyAwaiters(event)
+			data := ws.KeyEventData{
+				Op:  event.Operation,
+				Key: event.Key,
+			}
+			if s.wsHub.WantsValues(event.Key) {
+				s.addSnapshot(ctx, &data)
+			}
 			s.wsHub.Broadcast(ws.Message{
 				Type: "key_event",
-				Data: ws.KeyEventData{
-					Op:  event.Operation,
-					Key: event.Key,
-				},
+				Data: data,
 			})
 		case <-ctx.Done():
 			return
@@ -211,6 +289,123 @@ func (s *Server) runEventBroadcaster(ctx context.Context) {
 	}
 }
 
+// maxEventValueSize caps string value snapshots included in key events
+const maxEventValueSize = 1024
+
+// addSnapshot attaches the key's length, and for strings a capped value, to a key event.
+// Removal events are skipped since there's nothing left to read.
+func (s *Server) addSnapshot(ctx context.Context, data *ws.KeyEventData) {
+	switch data.Op {
+	case "del", "expired", "evicted", "rename_from":
+		return
+	}
+
+	stats := s.client.KeyStats(ctx, []string{data.Key}, false)
+	if len(stats) == 0 {
+		return
+	}
+	data.Length = &stats[0].Length
+
+	if stats[0].Type == "string" {
+		value, err := s.client.GetRange(ctx, data.Key, 0, maxEventValueSize-1)
+		if err == nil {
+			data.Value = &value
+			data.Truncated = stats[0].Length > maxEventValueSize
+		}
+	}
+}
+
+// Resubscribe backoff bounds
+const (
+	resubscribeMinDelay = time.Second
+	resubscribeMaxDelay = 30 * time.Second
+)
+
+// resubscribe re-establishes the keyspace subscription after it closed unexpectedly,
+// retrying with exponential backoff. Clients get a status message when the
+// connection is lost and again when it's restored. Returns false if the server is
+// shutting down or live updates were disabled in the meantime.
+func (s *Server) resubscribe(ctx context.Context) bool {
+	log.Println("Keyspace subscription lost, reconnecting")
+	s.wsHub.Broadcast(ws.Message{
+		Type: "status",
+		Data: ws.StatusData{Live: false, Msg: "Connection to Valkey lost, reconnecting"},
+	})
+
+	delay := resubscribeMinDelay
+	for {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return false
+		}
+		if !s.liveUpdates.Load() {
+			return false
+		}
+
+		// valkey-go reconnects on its own; wait until the server answers before
+		// subscribing, since a failed subscribe just closes the channel again.
+		// After a Sentinel failover the new primary may need notifications
+		// turned on; CONFIG SET isn't replicated.
+		if err := s.client.Ping(ctx); err == nil {
+			if s.client.IsSentinel() {
+				log.Printf("Valkey primary is %s", s.client.Primary())
+				if _, err := s.ensureNotifications(ctx); err != nil {
+					log.Printf("Warning: %v", err)
+				}
+			}
+			events, err := s.client.SubscribeKeyspace(ctx, s.cfg.ValkeyDB)
+			if err == nil {
+				s.keyEvents = events
+				log.Println("Keyspace subscription restored")
+				s.wsHub.Broadcast(ws.Message{
+					Type: "status",
+					Data: ws.StatusData{Live: true, Msg: "Reconnected to Valkey"},
+				})
+				return true
+			}
+		}
+
+		delay = min(delay*2, resubscribeMaxDelay)
+	}
+}
+
+// collectStats gathers the stats payload. A failed ping marks the database as
+// disconnected and skips the other queries, which would fail the same way.
+func (s *Server) collectStats(ctx context.Context) ws.StatsData {
+	statsData := ws.StatsData{
+		NotificationsOn: s.liveUpdates.Load(),
+		DBConnected:     true,
+	}
+
+	start := time.Now()
+	if err := s.client.Ping(ctx); err != nil {
+		statsData.DBConnected = false
+		statsData.LastError = err.Error()
+		statsData.LatencyHistory = s.latency.snapshot()
+		return statsData
+	}
+	statsData.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+
+	dbSize, err := s.client.DBSize(ctx)
+	if err != nil {
+		log.Printf("Stats broadcast: DBSize error: %v", err)
+	}
+	statsData.DBSize = dbSize
+
+	memStats, err := s.client.GetMemoryStats(ctx)
+	if err != nil {
+		log.Printf("Stats broadcast: GetMemoryStats error: %v", err)
+	}
+	if memStats != nil {
+		statsData.UsedMemory = memStats.UsedMemory
+		statsData.UsedMemoryHuman = memStats.UsedMemoryHuman
+	}
+
+	statsData.LatencyHistory = s.latency.snapshot()
+	return statsData
+}
+
 // runStatsBroadcaster periodically broadcasts stats to all WebSocket clients
 func (s *Server) runStatsBroadcaster(ctx context.Context) {
 	ticker := time.NewTicker(5 * time.Second)
@@ -219,28 +414,21 @@ func (s *Server) runStatsBroadcaster(ctx context.Context) {
 	for {
 		select {
 		case <-ticker.C:
-			dbSize, err := s.client.DBSize(ctx)
-			if err != nil {
-				log.Printf("Stats broadcast: DBSize error: %v", err)
-			}
-			memStats, err := s.client.GetMemoryStats(ctx)
-			if err != nil {
-				log.Printf("Stats broadcast: GetMemoryStats error: %v", err)
-			}
-
-			statsData := ws.StatsData{
-				DBSize:          dbSize,
-				NotificationsOn: s.liveUpdates.Load(),
-			}
-
-			if memStats != nil {
-				statsData.UsedMemory = memStats.UsedMemory
-				statsData.UsedMemoryHuman = memStats.UsedMemoryHuman
+			stats := s.collectStats(ctx)
+			// Only ticks feed the history so WebSocket connects don't skew the spacing
+			if stats.DBConnected {
+				s.latency.add(stats.LatencyMs)
+				stats.LatencyHistory = s.latency.snapshot()
+
+				if hits, misses, err := s.keyspaceCounters(ctx); err != nil {
+					log.Printf("Stats broadcast: INFO stats error: %v", err)
+				} else {
+					stats.HitRatio = s.hits.update(hits, misses)
+				}
 			}
-
 			s.wsHub.Broadcast(ws.Message{
 				Type: "stats",
-				Data: statsData,
+				Data: stats,
 			})
 		case <-ctx.Done():
 			return
@@ -248,19 +436,30 @@ func (s *Server) runStatsBroadcaster(ctx context.Context) {
 	}
 }
 
-// handleWebSocket handles WebSocket connections for real-time updates
-func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+// acceptOptions builds the WebSocket handshake options from config
+func (s *Server) acceptOptions() *websocket.AcceptOptions {
 	opts := &websocket.AcceptOptions{}
 	if s.cfg.CORSOrigin != "" {
 		opts.OriginPatterns = []string{s.cfg.CORSOrigin}
 	}
-	conn, err := websocket.Accept(w, r, opts)
+	if s.cfg.WSCompress {
+		// Context takeover keeps the deflate window across messages, so small,
+		// similar JSON events compress well at the cost of ~32KB per connection
+		opts.CompressionMode = websocket.CompressionContextTakeover
+	}
+	return opts
+}
+
+// handleWebSocket handles WebSocket connections for real-time updates
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, s.acceptOptions())
 	if err != nil {
 		log.Printf("WebSocket accept error: %v", err)
 		return
 	}
 
 	client := ws.NewClient(s.wsHub, conn)
+	client.OnMessage(s.handleClientMessage)
 	s.wsHub.Register(client)
 
 	// Send initial status
@@ -273,22 +472,9 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Send initial stats
-	dbSize, _ := s.client.DBSize(r.Context())
-	memStats, _ := s.client.GetMemoryStats(r.Context())
-
-	statsData := ws.StatsData{
-		DBSize:          dbSize,
-		NotificationsOn: s.liveUpdates.Load(),
-	}
-
-	if memStats != nil {
-		statsData.UsedMemory = memStats.UsedMemory
-		statsData.UsedMemoryHuman = memStats.UsedMemoryHuman
-	}
-
 	stats := ws.Message{
 		Type: "stats",
-		Data: statsData,
+		Data: s.collectStats(r.Context()),
 	}
 	if data, err := json.Marshal(stats); err == nil {
 		client.Send(data)
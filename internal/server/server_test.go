@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/coder/websocket"
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+func TestWebSocketCompression(t *testing.T) {
+	tests := []struct {
+		name     string
+		compress bool
+		want     bool
+	}{
+		{"enabled", true, true},
+		{"disabled", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{cfg: &config.Config{WSCompress: tt.compress}}
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, err := websocket.Accept(w, r, s.acceptOptions())
+				if err != nil {
+					return
+				}
+				conn.Close(websocket.StatusNormalClosure, "")
+			}))
+			defer srv.Close()
+
+			conn, resp, err := websocket.Dial(context.Background(), srv.URL, &websocket.DialOptions{
+				CompressionMode: websocket.CompressionContextTakeover,
+			})
+			if err != nil {
+				t.Fatalf("Dial error: %v", err)
+			}
+			defer conn.CloseNow()
+
+			ext := resp.Header.Get("Sec-WebSocket-Extensions")
+			got := strings.Contains(ext, "permessage-deflate")
+			if got != tt.want {
+				t.Errorf("Sec-WebSocket-Extensions = %q, negotiated = %v, want %v", ext, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/natrimmer/kvweb/internal/valkey"
+	"github.com/natrimmer/kvweb/internal/ws"
+)
+
+const (
+	// maxStreamTails caps concurrent stream tails; each blocked XREAD holds a server connection
+	maxStreamTails = 16
+
+	// streamTailBlock is how long each XREAD waits before re-checking the key
+	streamTailBlock = 5 * time.Second
+
+	// streamTailBatch caps entries sent per stream_entries message
+	streamTailBatch = 100
+)
+
+// streamTailWatch is one client's stream tail
+type streamTailWatch struct {
+	cancel context.CancelFunc
+}
+
+// streamTail starts pushing entries added to the stream msg.Key to the client,
+// replacing any tail it already had
+func (s *Server) streamTail(ctx context.Context, c *ws.Client, msg ws.ClientMessage) {
+	if msg.Key == "" {
+		c.SendMessage(ws.Message{Type: "error", Data: ws.ErrorData{Msg: "stream_tail requires a key"}})
+		return
+	}
+	if !s.cfg.KeyAllowed(msg.Key) {
+		c.SendMessage(ws.Message{Type: "error", Data: ws.ErrorData{Msg: "Key is outside the allowed prefix or patterns"}})
+		return
+	}
+
+	s.tailsMu.Lock()
+	if old, ok := s.tails[c]; ok {
+		old.cancel()
+		delete(s.tails, c)
+	}
+	if len(s.tails) >= maxStreamTails {
+		s.tailsMu.Unlock()
+		c.SendMessage(ws.Message{Type: "error", Data: ws.ErrorData{Msg: "Too many stream tails"}})
+		return
+	}
+	tailCtx, cancel := context.WithCancel(ctx)
+	watch := &streamTailWatch{cancel: cancel}
+	s.tails[c] = watch
+	s.tailsMu.Unlock()
+
+	c.SendMessage(ws.Message{Type: "stream_tail", Data: ws.StreamTailData{Key: msg.Key}})
+
+	go func() {
+		reason := s.runStreamTail(tailCtx, c, msg.Key)
+		cancel()
+
+		s.tailsMu.Lock()
+		// Only clean up if this tail hasn't been replaced by a newer one
+		if s.tails[c] == watch {
+			delete(s.tails, c)
+		}
+		s.tailsMu.Unlock()
+
+		if reason != "" {
+			c.SendMessage(ws.Message{Type: "stream_tail", Data: ws.StreamTailData{Reason: reason}})
+		}
+	}()
+}
+
+// runStreamTail forwards new entries until ctx is cancelled, returning "", or
+// until the key stops being a stream, returning the reason
+func (s *Server) runStreamTail(ctx context.Context, c *ws.Client, key string) string {
+	keyType, err := s.client.Type(ctx, key)
+	if err != nil {
+		return tailEndReason(ctx, err)
+	}
+	if keyType != "stream" {
+		return "key is not a stream"
+	}
+
+	// Start after the current last entry so only new entries are sent
+	lastID := "0-0"
+	latest, err := s.client.XRevRange(ctx, key, "+", "-", 1)
+	if err != nil {
+		return tailEndReason(ctx, err)
+	}
+	if len(latest) > 0 {
+		lastID = latest[0].ID
+	}
+
+	for {
+		entries, err := s.client.XReadBlock(ctx, key, lastID, streamTailBatch, streamTailBlock)
+		if err != nil {
+			if valkey.IsWrongType(err) {
+				return "key changed type"
+			}
+			return tailEndReason(ctx, err)
+		}
+
+		if len(entries) == 0 {
+			// XREAD keeps blocking on a deleted key, so check on every timeout
+			keyType, err := s.client.Type(ctx, key)
+			if err != nil {
+				return tailEndReason(ctx, err)
+			}
+			switch keyType {
+			case "stream":
+				continue
+			case "none":
+				return "key deleted"
+			default:
+				return "key changed type"
+			}
+		}
+
+		lastID = entries[len(entries)-1].ID
+		data := ws.StreamEntriesData{Key: key, Entries: make([]ws.StreamEntryData, len(entries))}
+		for i, e := range entries {
+			data.Entries[i] = ws.StreamEntryData{ID: e.ID, Fields: e.Fields}
+		}
+		c.SendMessage(ws.Message{Type: "stream_entries", Data: data})
+	}
+}
+
+// tailEndReason returns "" when the tail was cancelled, otherwise the error
+func tailEndReason(ctx context.Context, err error) string {
+	if ctx.Err() != nil {
+		return ""
+	}
+	return "error: " + err.Error()
+}
+
+// streamUntail stops the client's stream tail, if any
+func (s *Server) streamUntail(c *ws.Client) {
+	s.tailsMu.Lock()
+	if watch, ok := s.tails[c]; ok {
+		watch.cancel()
+		delete(s.tails, c)
+	}
+	s.tailsMu.Unlock()
+
+	c.SendMessage(ws.Message{Type: "stream_tail", Data: ws.StreamTailData{}})
+}
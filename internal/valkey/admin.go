@@ -0,0 +1,195 @@
+package valkey
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// SlowLogEntry is a single SLOWLOG GET record
+type SlowLogEntry struct {
+	ID         int64    `json:"id"`
+	Timestamp  int64    `json:"timestamp"`  // unix seconds
+	DurationUs int64    `json:"durationUs"` // execution time in microseconds
+	Args       []string `json:"args"`
+	ClientAddr string   `json:"clientAddr,omitempty"`
+	ClientName string   `json:"clientName,omitempty"`
+}
+
+// SlowLogGet returns up to count of the most recent slow log entries
+func (c *Client) SlowLogGet(ctx context.Context, count int64) ([]SlowLogEntry, error) {
+	raw, err := c.do(ctx, c.client.B().SlowlogGet().Count(count).Build()).ToArray()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]SlowLogEntry, 0, len(raw))
+	for _, r := range raw {
+		fields, err := r.ToArray()
+		if err != nil || len(fields) < 4 {
+			continue
+		}
+		var e SlowLogEntry
+		e.ID, _ = fields[0].AsInt64()
+		e.Timestamp, _ = fields[1].AsInt64()
+		e.DurationUs, _ = fields[2].AsInt64()
+		e.Args, _ = fields[3].AsStrSlice()
+		if len(fields) >= 6 {
+			e.ClientAddr, _ = fields[4].ToString()
+			e.ClientName, _ = fields[5].ToString()
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// SlowLogReset clears the slow log
+func (c *Client) SlowLogReset(ctx context.Context) error {
+	return c.do(ctx, c.client.B().SlowlogReset().Build()).Error()
+}
+
+// ClientInfo is a single connection from CLIENT LIST
+type ClientInfo struct {
+	ID    int64  `json:"id"`
+	Addr  string `json:"addr"`
+	Name  string `json:"name,omitempty"`
+	Age   int64  `json:"age"`  // seconds since connect
+	Idle  int64  `json:"idle"` // seconds since last command
+	Flags string `json:"flags,omitempty"`
+	DB    int64  `json:"db"`
+	Cmd   string `json:"cmd,omitempty"` // last command run
+}
+
+// ClientList returns the connections currently open to the server
+func (c *Client) ClientList(ctx context.Context) ([]ClientInfo, error) {
+	raw, err := c.do(ctx, c.client.B().ClientList().Build()).ToString()
+	if err != nil {
+		return nil, err
+	}
+	return parseClientList(raw), nil
+}
+
+// parseClientList parses CLIENT LIST output: one connection per line of space-separated key=value pairs
+func parseClientList(raw string) []ClientInfo {
+	clients := []ClientInfo{}
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var ci ClientInfo
+		for _, pair := range strings.Fields(line) {
+			k, v, ok := strings.Cut(pair, "=")
+			if !ok {
+				continue
+			}
+			switch k {
+			case "id":
+				ci.ID, _ = strconv.ParseInt(v, 10, 64)
+			case "addr":
+				ci.Addr = v
+			case "name":
+				ci.Name = v
+			case "age":
+				ci.Age, _ = strconv.ParseInt(v, 10, 64)
+			case "idle":
+				ci.Idle, _ = strconv.ParseInt(v, 10, 64)
+			case "flags":
+				ci.Flags = v
+			case "db":
+				ci.DB, _ = strconv.ParseInt(v, 10, 64)
+			case "cmd":
+				ci.Cmd = v
+			}
+		}
+		clients = append(clients, ci)
+	}
+	return clients
+}
+
+// ClientKill closes the connection with the given client ID
+func (c *Client) ClientKill(ctx context.Context, id int64) error {
+	return c.do(ctx, c.client.B().ClientKill().Id(id).Build()).Error()
+}
+
+// DebugObject returns the fields of DEBUG OBJECT (encoding, serializedlength, lru, ...).
+// Many servers disable DEBUG (enable-debug-command), in which case this returns their error.
+func (c *Client) DebugObject(ctx context.Context, key string) (map[string]string, error) {
+	raw, err := c.do(ctx, c.client.B().DebugObject().Key(key).Build()).ToString()
+	if err != nil {
+		return nil, err
+	}
+	return parseDebugObject(raw), nil
+}
+
+// parseDebugObject parses "Value at:0x7f... refcount:1 encoding:listpack serializedlength:20 ..."
+func parseDebugObject(raw string) map[string]string {
+	fields := make(map[string]string)
+	raw = strings.TrimPrefix(raw, "Value ")
+	for _, pair := range strings.Fields(raw) {
+		if k, v, ok := strings.Cut(pair, ":"); ok {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// AclUser is one user's settings from ACL GETUSER. Passwords holds SHA-256
+// hashes, so callers showing it to people should redact them.
+type AclUser struct {
+	Flags     []string `json:"flags"`
+	Passwords []string `json:"passwords"`
+	Commands  string   `json:"commands"`
+	Keys      string   `json:"keys"`
+	Channels  string   `json:"channels"`
+}
+
+// AclList returns the configured users as ACL LIST rule lines
+// (e.g. "user default on nopass ~* &* +@all")
+func (c *Client) AclList(ctx context.Context) ([]string, error) {
+	return c.do(ctx, c.client.B().AclList().Build()).AsStrSlice()
+}
+
+// AclGetUser returns a user's flags, password hashes, and permissions, or nil
+// if the user doesn't exist
+func (c *Client) AclGetUser(ctx context.Context, username string) (*AclUser, error) {
+	fields, err := c.do(ctx, c.client.B().AclGetuser().Username(username).Build()).AsMap()
+	if valkey.IsValkeyNil(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user := &AclUser{Flags: []string{}, Passwords: []string{}}
+	if f, ok := fields["flags"]; ok {
+		user.Flags, _ = f.AsStrSlice()
+	}
+	if f, ok := fields["passwords"]; ok {
+		user.Passwords, _ = f.AsStrSlice()
+	}
+	user.Commands = aclField(fields["commands"])
+	user.Keys = aclField(fields["keys"])
+	user.Channels = aclField(fields["channels"])
+	return user, nil
+}
+
+// aclField reads a GETUSER permission field. Valkey sends a rule string;
+// Redis 6 sent keys and channels as a list of patterns.
+func aclField(msg valkey.ValkeyMessage) string {
+	if s, err := msg.ToString(); err == nil {
+		return s
+	}
+	if list, err := msg.AsStrSlice(); err == nil {
+		return strings.Join(list, " ")
+	}
+	return ""
+}
+
+// AclWhoAmI returns the ACL user kvweb's connection is authenticated as
+func (c *Client) AclWhoAmI(ctx context.Context) (string, error) {
+	return c.do(ctx, c.client.B().AclWhoami().Build()).ToString()
+}
@@ -0,0 +1,56 @@
+package valkey
+
+import (
+	"testing"
+)
+
+func TestParseClientList(t *testing.T) {
+	raw := "id=3 addr=127.0.0.1:52555 laddr=127.0.0.1:6379 fd=8 name=kvweb age=12 idle=0 flags=N db=0 cmd=client|list\n" +
+		"id=7 addr=10.0.0.2:40100 fd=9 name= age=300 idle=295 flags=P db=2 cmd=subscribe\n"
+
+	clients := parseClientList(raw)
+	if len(clients) != 2 {
+		t.Fatalf("expected 2 clients, got %d", len(clients))
+	}
+
+	want := ClientInfo{ID: 3, Addr: "127.0.0.1:52555", Name: "kvweb", Age: 12, Idle: 0, Flags: "N", DB: 0, Cmd: "client|list"}
+	if clients[0] != want {
+		t.Errorf("clients[0] = %+v, want %+v", clients[0], want)
+	}
+
+	if clients[1].Name != "" || clients[1].Idle != 295 || clients[1].DB != 2 {
+		t.Errorf("clients[1] = %+v", clients[1])
+	}
+}
+
+func TestParseClientListEmpty(t *testing.T) {
+	if clients := parseClientList(""); len(clients) != 0 {
+		t.Errorf("expected no clients, got %d", len(clients))
+	}
+}
+
+func TestParseDebugObject(t *testing.T) {
+	fields := parseDebugObject("Value at:0x7f3a2c0 refcount:1 encoding:listpack serializedlength:24 lru:1234 lru_seconds_idle:7")
+
+	want := map[string]string{
+		"at":               "0x7f3a2c0",
+		"refcount":         "1",
+		"encoding":         "listpack",
+		"serializedlength": "24",
+		"lru":              "1234",
+		"lru_seconds_idle": "7",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("fields[%q] = %q, want %q", k, fields[k], v)
+		}
+	}
+}
+
+func TestEncodeCommand(t *testing.T) {
+	got := encodeCommand([]string{"AUTH", "p@ss word"})
+	want := "*2\r\n$4\r\nAUTH\r\n$9\r\np@ss word\r\n"
+	if got != want {
+		t.Errorf("encodeCommand() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+package valkey
+
+import (
+	"context"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// KeyStat holds the size information used by keyspace analysis
+type KeyStat struct {
+	Key    string `json:"key"`
+	Type   string `json:"type"`
+	Length int64  `json:"length"` // element count (bytes for strings)
+	Memory int64  `json:"memory"` // bytes from MEMORY USAGE, 0 if not requested
+	TTL    int64  `json:"ttl"`    // seconds, -1 = no expiry
+}
+
+// KeyStats returns type, TTL, length, and optionally memory for each key in two
+// pipelined round trips. Like KeyMetaBatch, strings carrying the HyperLogLog
+// magic header are reported as "hyperloglog". Keys that vanish between SCAN
+// and the lookup are omitted.
+func (c *Client) KeyStats(ctx context.Context, keys []string, withMemory bool) []KeyStat {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	perKey := 3
+	if withMemory {
+		perKey = 4
+	}
+
+	cmds := make([]valkey.Completed, 0, len(keys)*perKey)
+	for _, key := range keys {
+		// GETRANGE fails with WRONGTYPE on non-strings, which is ignored below;
+		// sending it up front saves a round trip for the HyperLogLog check
+		cmds = append(cmds,
+			c.client.B().Type().Key(key).Build(),
+			c.client.B().Ttl().Key(key).Build(),
+			c.client.B().Getrange().Key(key).Start(0).End(3).Build(),
+		)
+		if withMemory {
+			cmds = append(cmds, c.client.B().MemoryUsage().Key(key).Build())
+		}
+	}
+	results := c.client.DoMulti(ctx, cmds...)
+
+	stats := make([]KeyStat, 0, len(keys))
+	for i, key := range keys {
+		keyType, err := results[i*perKey].ToString()
+		if err != nil || keyType == "none" {
+			continue
+		}
+		ttl, _ := results[i*perKey+1].ToInt64()
+		if keyType == "string" {
+			if header, err := results[i*perKey+2].ToString(); err == nil && IsHyperLogLog(header) {
+				keyType = "hyperloglog"
+			}
+		}
+		stat := KeyStat{Key: key, Type: keyType, TTL: ttl}
+		if withMemory {
+			stat.Memory, _ = results[i*perKey+3].ToInt64()
+		}
+		stats = append(stats, stat)
+	}
+
+	// Second round trip: the length command depends on the type
+	lenCmds := make([]valkey.Completed, 0, len(stats))
+	lenIdx := make([]int, 0, len(stats))
+	for i, s := range stats {
+		var cmd valkey.Completed
+		switch s.Type {
+		case "string", "hyperloglog":
+			cmd = c.client.B().Strlen().Key(s.Key).Build()
+		case "list":
+			cmd = c.client.B().Llen().Key(s.Key).Build()
+		case "set":
+			cmd = c.client.B().Scard().Key(s.Key).Build()
+		case "hash":
+			cmd = c.client.B().Hlen().Key(s.Key).Build()
+		case "zset":
+			cmd = c.client.B().Zcard().Key(s.Key).Build()
+		case "stream":
+			cmd = c.client.B().Xlen().Key(s.Key).Build()
+		default:
+			continue
+		}
+		lenCmds = append(lenCmds, cmd)
+		lenIdx = append(lenIdx, i)
+	}
+	if len(lenCmds) > 0 {
+		for j, r := range c.client.DoMulti(ctx, lenCmds...) {
+			stats[lenIdx[j]].Length, _ = r.ToInt64()
+		}
+	}
+
+	return stats
+}
+
+// KeyMetaBatch returns type and TTL for each key in pipelined round trips.
+// Strings carrying the HyperLogLog magic header are reported as "hyperloglog".
+// Keys that vanish between SCAN and the lookup are omitted.
+func (c *Client) KeyMetaBatch(ctx context.Context, keys []string) []KeyStat {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	cmds := make([]valkey.Completed, 0, len(keys)*2)
+	for _, key := range keys {
+		cmds = append(cmds,
+			c.client.B().Type().Key(key).Build(),
+			c.client.B().Ttl().Key(key).Build(),
+		)
+	}
+	results := c.client.DoMulti(ctx, cmds...)
+
+	stats := make([]KeyStat, 0, len(keys))
+	var strIdx []int
+	for i, key := range keys {
+		keyType, err := results[i*2].ToString()
+		if err != nil || keyType == "none" {
+			continue
+		}
+		ttl, _ := results[i*2+1].ToInt64()
+		if keyType == "string" {
+			strIdx = append(strIdx, len(stats))
+		}
+		stats = append(stats, KeyStat{Key: key, Type: keyType, TTL: ttl})
+	}
+
+	// HyperLogLog is stored as a string, so check the first 4 bytes of each string
+	strKeys := make([]string, len(strIdx))
+	for j, i := range strIdx {
+		strKeys[j] = stats[i].Key
+	}
+	for j, hll := range c.hllHeaders(ctx, strKeys) {
+		if hll {
+			stats[strIdx[j]].Type = "hyperloglog"
+		}
+	}
+
+	return stats
+}
+
+// hllHeaders reports, in one pipelined round trip, which of the string keys
+// start with the HyperLogLog magic header. Keys that can't be read report false.
+func (c *Client) hllHeaders(ctx context.Context, keys []string) []bool {
+	hll := make([]bool, len(keys))
+	if len(keys) == 0 {
+		return hll
+	}
+	cmds := make([]valkey.Completed, len(keys))
+	for i, key := range keys {
+		cmds[i] = c.client.B().Getrange().Key(key).Start(0).End(3).Build()
+	}
+	for i, r := range c.client.DoMulti(ctx, cmds...) {
+		header, err := r.ToString()
+		hll[i] = err == nil && IsHyperLogLog(header)
+	}
+	return hll
+}
+
+// FilterHyperLogLog keeps the string keys that are HyperLogLogs (hll true) or
+// plain strings (hll false), checking every header in one pipelined round trip
+func (c *Client) FilterHyperLogLog(ctx context.Context, keys []string, hll bool) []string {
+	filtered := make([]string, 0, len(keys))
+	for i, isHLL := range c.hllHeaders(ctx, keys) {
+		if isHLL == hll {
+			filtered = append(filtered, keys[i])
+		}
+	}
+	return filtered
+}
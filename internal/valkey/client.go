@@ -2,7 +2,11 @@ package valkey
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -13,8 +17,12 @@ import (
 
 // Client wraps the Valkey client with application-specific methods
 type Client struct {
-	client valkey.Client
-	cfg    *config.Config
+	client       valkey.Client
+	cfg          *config.Config
+	opts         valkey.ClientOption // resolved connection options, for raw connections (MONITOR)
+	cluster      bool                // server is a Valkey Cluster; keyspace-wide commands fan out to every primary
+	primaryCache primaryCache
+	sentinel     bool // primary is discovered through Sentinel and may change on failover
 }
 
 // New creates a new Valkey client
@@ -28,12 +36,16 @@ func New(cfg *config.Config) (*Client, error) {
 			return nil, fmt.Errorf("failed to parse URL: %w", err)
 		}
 	} else {
+		// Several comma-separated addresses seed a cluster client
 		opts = valkey.ClientOption{
-			InitAddress: []string{cfg.ValkeyURL},
+			InitAddress: splitAddresses(cfg.ValkeyURL),
 		}
 	}
 
 	// CLI flags override URL-provided values
+	if cfg.ValkeyUsername != "" {
+		opts.Username = cfg.ValkeyUsername
+	}
 	if cfg.ValkeyPassword != "" {
 		opts.Password = cfg.ValkeyPassword
 	}
@@ -42,8 +54,18 @@ func New(cfg *config.Config) (*Client, error) {
 		opts.SelectDB = cfg.ValkeyDB
 	}
 
+	// With Sentinel the seed addresses are the sentinels; the driver asks them
+	// for the primary and reconnects when they announce a failover
+	if cfg.Sentinel != "" {
+		opts.InitAddress = splitAddresses(cfg.Sentinel)
+		opts.Sentinel.MasterSet = cfg.SentinelMaster
+	}
+
 	client, err := valkey.NewClient(opts)
 	if err != nil {
+		if authErr := authError(err, opts.Username); authErr != nil {
+			return nil, authErr
+		}
 		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
 
@@ -53,15 +75,58 @@ func New(cfg *config.Config) (*Client, error) {
 
 	if err := client.Do(ctx, client.B().Ping().Build()).Error(); err != nil {
 		client.Close()
+		if authErr := authError(err, opts.Username); authErr != nil {
+			return nil, authErr
+		}
 		return nil, fmt.Errorf("failed to ping server: %w", err)
 	}
 
+	// The driver picks a cluster client on its own when the server is one;
+	// kvweb needs to know too, to scan every primary
+	cluster, err := detectCluster(ctx, client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to check cluster mode: %w", err)
+	}
+	if cfg.Cluster && !cluster {
+		client.Close()
+		return nil, fmt.Errorf("--cluster is set but the server is not in cluster mode")
+	}
+	if cluster && cfg.ValkeyDB != 0 {
+		client.Close()
+		return nil, fmt.Errorf("cluster mode only has database 0")
+	}
+
 	return &Client{
-		client: client,
-		cfg:    cfg,
+		client:   client,
+		cfg:      cfg,
+		opts:     opts,
+		cluster:  cluster,
+		sentinel: cfg.Sentinel != "",
 	}, nil
 }
 
+// authError turns a NOAUTH or WRONGPASS reply into an error naming the
+// credentials to check, or returns nil for any other error
+func authError(err error, username string) error {
+	if username == "" {
+		username = "default"
+	}
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "WRONGPASS"):
+		return fmt.Errorf("authentication failed for user %q: wrong username or password (%w)", username, err)
+	case strings.Contains(msg, "NOAUTH"):
+		return fmt.Errorf("server requires authentication: set -password or VALKEY_PASSWORD, and -username for ACL users (%w)", err)
+	}
+	return nil
+}
+
+// IsNil reports whether err is a nil reply (missing key or field)
+func IsNil(err error) bool {
+	return valkey.IsValkeyNil(err)
+}
+
 // Close closes the client connection
 func (c *Client) Close() {
 	c.client.Close()
@@ -74,7 +139,7 @@ func (c *Client) Raw() valkey.Client {
 
 // Ping tests the connection
 func (c *Client) Ping(ctx context.Context) error {
-	return c.client.Do(ctx, c.client.B().Ping().Build()).Error()
+	return c.do(ctx, c.client.B().Ping().Build()).Error()
 }
 
 // Info returns server information
@@ -83,17 +148,53 @@ func (c *Client) Info(ctx context.Context, section string) (string, error) {
 	if section != "" {
 		cmd.Section(section)
 	}
-	return c.client.Do(ctx, cmd.Build()).ToString()
+	return c.do(ctx, cmd.Build()).ToString()
+}
+
+// RandomKey returns a random key from the current database, or "" if it is empty
+func (c *Client) RandomKey(ctx context.Context) (string, error) {
+	key, err := c.do(ctx, c.client.B().Randomkey().Build()).ToString()
+	if valkey.IsValkeyNil(err) {
+		return "", nil
+	}
+	return key, err
 }
 
-// DBSize returns the number of keys in the current database
+// DBSize returns the number of keys in the current database (summed over
+// primaries in a cluster)
 func (c *Client) DBSize(ctx context.Context) (int64, error) {
-	return c.client.Do(ctx, c.client.B().Dbsize().Build()).ToInt64()
+	if !c.cluster {
+		return c.do(ctx, c.client.B().Dbsize().Build()).ToInt64()
+	}
+	results, err := c.onPrimaries(ctx, func(n valkey.Client) valkey.Completed { return n.B().Dbsize().Build() })
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, r := range results {
+		size, err := r.ToInt64()
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return total, nil
 }
 
-// Keys returns keys matching the pattern
-func (c *Client) Keys(ctx context.Context, pattern string, cursor uint64, count int64) ([]string, uint64, error) {
-	result := c.client.Do(ctx, c.client.B().Scan().Cursor(cursor).Match(pattern).Count(count).Build())
+// Keys returns keys matching the pattern.
+// If scanType is non-empty, SCAN's TYPE option filters keys server-side.
+// In a cluster the cursor walks each primary in turn (see clusterKeys).
+func (c *Client) Keys(ctx context.Context, pattern string, cursor uint64, count int64, scanType string) ([]string, uint64, error) {
+	if c.cluster {
+		return c.clusterKeys(ctx, pattern, cursor, count, scanType)
+	}
+	cmd := c.client.B().Scan().Cursor(cursor).Match(pattern).Count(count)
+	var result valkey.ValkeyResult
+	if scanType != "" {
+		result = c.do(ctx, cmd.Type(scanType).Build())
+	} else {
+		result = c.do(ctx, cmd.Build())
+	}
 	entry, err := result.AsScanEntry()
 	if err != nil {
 		return nil, 0, err
@@ -103,21 +204,78 @@ func (c *Client) Keys(ctx context.Context, pattern string, cursor uint64, count
 
 // Get returns the value of a key
 func (c *Client) Get(ctx context.Context, key string) (string, error) {
-	return c.client.Do(ctx, c.client.B().Get().Key(key).Build()).ToString()
+	return c.do(ctx, c.client.B().Get().Key(key).Build()).ToString()
 }
 
 // Set sets the value of a key
 func (c *Client) Set(ctx context.Context, key, value string, ttl time.Duration) error {
 	cmd := c.client.B().Set().Key(key).Value(value)
 	if ttl > 0 {
-		cmd.Ex(ttl)
+		cmd.Px(ttl)
 	}
-	return c.client.Do(ctx, cmd.Build()).Error()
+	return c.do(ctx, cmd.Build()).Error()
+}
+
+// ValueVersion returns an opaque version of a string value for SetIfUnchanged
+func ValueVersion(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:8])
+}
+
+// SetIfUnchanged sets key only while its current value still has the given
+// version (see ValueVersion). The check and SET run under WATCH/MULTI/EXEC, so
+// a write from another client in between also makes it report false. A
+// missing key never matches.
+func (c *Client) SetIfUnchanged(ctx context.Context, key, version, value string, ttl time.Duration) (bool, error) {
+	var ok bool
+	err := c.client.Dedicated(func(dc valkey.DedicatedClient) error {
+		if err := dc.Do(ctx, dc.B().Watch().Key(key).Build()).Error(); err != nil {
+			return err
+		}
+		current, err := dc.Do(ctx, dc.B().Get().Key(key).Build()).ToString()
+		if err != nil && !valkey.IsValkeyNil(err) {
+			return err
+		}
+		if err != nil || ValueVersion(current) != version {
+			return dc.Do(ctx, dc.B().Unwatch().Build()).Error()
+		}
+
+		set := dc.B().Set().Key(key).Value(value)
+		if ttl > 0 {
+			set.Px(ttl)
+		}
+		results := dc.DoMulti(ctx, dc.B().Multi().Build(), set.Build(), dc.B().Exec().Build())
+		for _, r := range results[:2] {
+			if err := r.Error(); err != nil {
+				return err
+			}
+		}
+		// EXEC replies nil when a watched key changed
+		if err := results[2].Error(); err != nil {
+			if valkey.IsValkeyNil(err) {
+				return nil
+			}
+			return err
+		}
+		ok = true
+		return nil
+	})
+	return ok, err
+}
+
+// IncrBy increments an integer key by n using INCRBY
+func (c *Client) IncrBy(ctx context.Context, key string, n int64) (int64, error) {
+	return c.do(ctx, c.client.B().Incrby().Key(key).Increment(n).Build()).ToInt64()
+}
+
+// DecrBy decrements an integer key by n using DECRBY
+func (c *Client) DecrBy(ctx context.Context, key string, n int64) (int64, error) {
+	return c.do(ctx, c.client.B().Decrby().Key(key).Decrement(n).Build()).ToInt64()
 }
 
 // IncrByFloat increments a key by a float amount (handles both int and float)
 func (c *Client) IncrByFloat(ctx context.Context, key string, amount float64) (string, error) {
-	result, err := c.client.Do(ctx, c.client.B().Incrbyfloat().Key(key).Increment(amount).Build()).AsFloat64()
+	result, err := c.do(ctx, c.client.B().Incrbyfloat().Key(key).Increment(amount).Build()).AsFloat64()
 	if err != nil {
 		return "", err
 	}
@@ -126,68 +284,160 @@ func (c *Client) IncrByFloat(ctx context.Context, key string, amount float64) (s
 
 // Del deletes keys
 func (c *Client) Del(ctx context.Context, keys ...string) (int64, error) {
-	return c.client.Do(ctx, c.client.B().Del().Key(keys...).Build()).ToInt64()
+	return c.sumBySlot(ctx, keys, func(keys []string) valkey.Completed {
+		return c.client.B().Del().Key(keys...).Build()
+	})
+}
+
+// Exists returns how many of the given keys exist
+func (c *Client) Exists(ctx context.Context, keys ...string) (int64, error) {
+	return c.sumBySlot(ctx, keys, func(keys []string) valkey.Completed {
+		return c.client.B().Exists().Key(keys...).Build()
+	})
 }
 
 // Type returns the type of a key
 func (c *Client) Type(ctx context.Context, key string) (string, error) {
-	return c.client.Do(ctx, c.client.B().Type().Key(key).Build()).ToString()
+	return c.do(ctx, c.client.B().Type().Key(key).Build()).ToString()
+}
+
+// hllMagic is the header every HyperLogLog value starts with
+const hllMagic = "HYLL"
+
+// IsHyperLogLog reports whether a string value (or its first 4 bytes) is a HyperLogLog
+func IsHyperLogLog(val string) bool {
+	return strings.HasPrefix(val, hllMagic)
+}
+
+// DetectType returns the type of a key like Type, but reports "hyperloglog" for
+// strings that carry the HyperLogLog magic header
+func (c *Client) DetectType(ctx context.Context, key string) (string, error) {
+	keyType, err := c.Type(ctx, key)
+	if err != nil || keyType != "string" {
+		return keyType, err
+	}
+	header, err := c.GetRange(ctx, key, 0, 3)
+	if err != nil {
+		return "", err
+	}
+	if IsHyperLogLog(header) {
+		return "hyperloglog", nil
+	}
+	return keyType, nil
 }
 
 // TTL returns the TTL of a key in seconds (-1 if no TTL, -2 if key doesn't exist)
 func (c *Client) TTL(ctx context.Context, key string) (int64, error) {
-	return c.client.Do(ctx, c.client.B().Ttl().Key(key).Build()).ToInt64()
+	return c.do(ctx, c.client.B().Ttl().Key(key).Build()).ToInt64()
 }
 
 // Expire sets a TTL on a key
 func (c *Client) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
-	result, err := c.client.Do(ctx, c.client.B().Expire().Key(key).Seconds(int64(ttl.Seconds())).Build()).ToInt64()
+	result, err := c.do(ctx, c.client.B().Expire().Key(key).Seconds(int64(ttl.Seconds())).Build()).ToInt64()
 	return result == 1, err
 }
 
 // Persist removes the TTL from a key
 func (c *Client) Persist(ctx context.Context, key string) (bool, error) {
-	result, err := c.client.Do(ctx, c.client.B().Persist().Key(key).Build()).ToInt64()
+	result, err := c.do(ctx, c.client.B().Persist().Key(key).Build()).ToInt64()
 	return result == 1, err
 }
 
 // Rename renames a key
 func (c *Client) Rename(ctx context.Context, key, newkey string) error {
-	return c.client.Do(ctx, c.client.B().Rename().Key(key).Newkey(newkey).Build()).Error()
+	if err := c.checkSameSlot(key, newkey); err != nil {
+		return err
+	}
+	return c.do(ctx, c.client.B().Rename().Key(key).Newkey(newkey).Build()).Error()
+}
+
+// PTTL returns the TTL of a key in milliseconds (-1 if no TTL, -2 if key doesn't exist)
+func (c *Client) PTTL(ctx context.Context, key string) (int64, error) {
+	return c.do(ctx, c.client.B().Pttl().Key(key).Build()).ToInt64()
+}
+
+// Dump returns the serialized value of a key in the server's RDB format.
+// A missing key yields a nil error checked with IsNil.
+func (c *Client) Dump(ctx context.Context, key string) (string, error) {
+	return c.do(ctx, c.client.B().Dump().Key(key).Build()).ToString()
+}
+
+// Restore creates key from a DUMP payload. ttl of 0 means no expiry. Without
+// replace, an existing key fails with a BUSYKEY error.
+func (c *Client) Restore(ctx context.Context, key string, ttl time.Duration, payload string, replace bool) error {
+	cmd := c.client.B().Restore().Key(key).Ttl(ttl.Milliseconds()).SerializedValue(payload)
+	if replace {
+		return c.do(ctx, cmd.Replace().Build()).Error()
+	}
+	return c.do(ctx, cmd.Build()).Error()
+}
+
+// MigrateTarget is the destination of a MIGRATE
+type MigrateTarget struct {
+	Host     string
+	Port     int
+	DB       int
+	Password string // optional AUTH for the target
+	Timeout  time.Duration
+	Copy     bool // keep the source key
+	Replace  bool // overwrite an existing target key
+}
+
+// Migrate moves (or with Copy, copies) key to another server. The source server
+// connects to the target itself, so the target must be reachable from it, not from kvweb.
+// A missing key returns "NOKEY" rather than an error.
+func (c *Client) Migrate(ctx context.Context, key string, t MigrateTarget) (string, error) {
+	cmd := c.client.B().Arbitrary("MIGRATE").Args(t.Host, strconv.Itoa(t.Port)).Keys(key).
+		Args(strconv.Itoa(t.DB), strconv.FormatInt(t.Timeout.Milliseconds(), 10))
+	if t.Copy {
+		cmd = cmd.Args("COPY")
+	}
+	if t.Replace {
+		cmd = cmd.Args("REPLACE")
+	}
+	if t.Password != "" {
+		cmd = cmd.Args("AUTH", t.Password)
+	}
+	return c.do(ctx, cmd.Build()).ToString()
 }
 
 // FlushDB deletes all keys in the current database
 func (c *Client) FlushDB(ctx context.Context) error {
-	return c.client.Do(ctx, c.client.B().Flushdb().Build()).Error()
+	if c.cluster {
+		_, err := c.onPrimaries(ctx, func(n valkey.Client) valkey.Completed { return n.B().Flushdb().Build() })
+		return err
+	}
+	return c.do(ctx, c.client.B().Flushdb().Build()).Error()
 }
 
 // List operations
 
 // LLen returns the length of a list
 func (c *Client) LLen(ctx context.Context, key string) (int64, error) {
-	return c.client.Do(ctx, c.client.B().Llen().Key(key).Build()).ToInt64()
+	return c.do(ctx, c.client.B().Llen().Key(key).Build()).ToInt64()
 }
 
 // LRange returns elements from a list
 func (c *Client) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
-	return c.client.Do(ctx, c.client.B().Lrange().Key(key).Start(start).Stop(stop).Build()).AsStrSlice()
+	return c.do(ctx, c.client.B().Lrange().Key(key).Start(start).Stop(stop).Build()).AsStrSlice()
 }
 
 // Set operations
 
 // SCard returns the number of members in a set
 func (c *Client) SCard(ctx context.Context, key string) (int64, error) {
-	return c.client.Do(ctx, c.client.B().Scard().Key(key).Build()).ToInt64()
+	return c.do(ctx, c.client.B().Scard().Key(key).Build()).ToInt64()
 }
 
 // SMembers returns all members of a set
 func (c *Client) SMembers(ctx context.Context, key string) ([]string, error) {
-	return c.client.Do(ctx, c.client.B().Smembers().Key(key).Build()).AsStrSlice()
+	return c.do(ctx, c.client.B().Smembers().Key(key).Build()).AsStrSlice()
 }
 
-// SScan returns members of a set using cursor-based pagination
-func (c *Client) SScan(ctx context.Context, key string, cursor uint64, count int64) ([]string, uint64, error) {
-	result := c.client.Do(ctx, c.client.B().Sscan().Key(key).Cursor(cursor).Count(count).Build())
+// SScan returns members of a set matching a glob using cursor-based pagination
+// ("" matches all; the server skips matching for "*")
+func (c *Client) SScan(ctx context.Context, key string, cursor uint64, match string, count int64) ([]string, uint64, error) {
+	result := c.do(ctx, c.client.B().Sscan().Key(key).Cursor(cursor).Match(matchAll(match)).Count(count).Build())
 	entry, err := result.AsScanEntry()
 	if err != nil {
 		return nil, 0, err
@@ -195,21 +445,98 @@ func (c *Client) SScan(ctx context.Context, key string, cursor uint64, count int
 	return entry.Elements, entry.Cursor, nil
 }
 
+// maxScanPageCalls bounds how many SSCAN/HSCAN calls one page may take
+const maxScanPageCalls = 16
+
+func matchAll(match string) string {
+	if match == "" {
+		return "*"
+	}
+	return match
+}
+
+// SScanPage continues an SSCAN from cursor until it has at least count members
+// or the scan completes. COUNT is only a hint, so a single call can return far
+// fewer members (even none) on a sparse set; looping keeps pages full without
+// restarting the scan. Members SSCAN repeats within the page are dropped.
+// With a MATCH pattern a page can come back short even though the scan
+// isn't finished, once maxScanPageCalls is reached.
+func (c *Client) SScanPage(ctx context.Context, key string, cursor uint64, match string, count int64) ([]string, uint64, error) {
+	members := make([]string, 0, count)
+	seen := make(map[string]bool, count)
+	for calls := 0; calls < maxScanPageCalls; calls++ {
+		batch, next, err := c.SScan(ctx, key, cursor, match, count-int64(len(members)))
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, m := range batch {
+			if !seen[m] {
+				seen[m] = true
+				members = append(members, m)
+			}
+		}
+		cursor = next
+		if cursor == 0 || int64(len(members)) >= count {
+			break
+		}
+	}
+	return members, cursor, nil
+}
+
+// SetOp returns the result of SINTER, SUNION, or SDIFF ("inter", "union", "diff") over keys
+func (c *Client) SetOp(ctx context.Context, op string, keys ...string) ([]string, error) {
+	if err := c.checkSameSlot(keys...); err != nil {
+		return nil, err
+	}
+	var cmd valkey.Completed
+	switch op {
+	case "inter":
+		cmd = c.client.B().Sinter().Key(keys...).Build()
+	case "union":
+		cmd = c.client.B().Sunion().Key(keys...).Build()
+	case "diff":
+		cmd = c.client.B().Sdiff().Key(keys...).Build()
+	default:
+		return nil, fmt.Errorf("unsupported set operation: %s", op)
+	}
+	return c.do(ctx, cmd).AsStrSlice()
+}
+
+// SetOpStore stores the result of SetOp in dest and returns the resulting set size
+func (c *Client) SetOpStore(ctx context.Context, op, dest string, keys ...string) (int64, error) {
+	if err := c.checkSameSlot(append([]string{dest}, keys...)...); err != nil {
+		return 0, err
+	}
+	var cmd valkey.Completed
+	switch op {
+	case "inter":
+		cmd = c.client.B().Sinterstore().Destination(dest).Key(keys...).Build()
+	case "union":
+		cmd = c.client.B().Sunionstore().Destination(dest).Key(keys...).Build()
+	case "diff":
+		cmd = c.client.B().Sdiffstore().Destination(dest).Key(keys...).Build()
+	default:
+		return 0, fmt.Errorf("unsupported set operation: %s", op)
+	}
+	return c.do(ctx, cmd).ToInt64()
+}
+
 // Hash operations
 
 // HLen returns the number of fields in a hash
 func (c *Client) HLen(ctx context.Context, key string) (int64, error) {
-	return c.client.Do(ctx, c.client.B().Hlen().Key(key).Build()).ToInt64()
+	return c.do(ctx, c.client.B().Hlen().Key(key).Build()).ToInt64()
 }
 
 // HGetAll returns all fields and values in a hash
 func (c *Client) HGetAll(ctx context.Context, key string) (map[string]string, error) {
-	return c.client.Do(ctx, c.client.B().Hgetall().Key(key).Build()).AsStrMap()
+	return c.do(ctx, c.client.B().Hgetall().Key(key).Build()).AsStrMap()
 }
 
-// HScan returns fields and values of a hash using cursor-based pagination
-func (c *Client) HScan(ctx context.Context, key string, cursor uint64, count int64) (map[string]string, uint64, error) {
-	result := c.client.Do(ctx, c.client.B().Hscan().Key(key).Cursor(cursor).Count(count).Build())
+// HScan returns fields (matching a glob, "" = all) and values of a hash using
+// cursor-based pagination
+func (c *Client) HScan(ctx context.Context, key string, cursor uint64, match string, count int64) (map[string]string, uint64, error) {
+	result := c.do(ctx, c.client.B().Hscan().Key(key).Cursor(cursor).Match(matchAll(match)).Count(count).Build())
 	entry, err := result.AsScanEntry()
 	if err != nil {
 		return nil, 0, err
@@ -224,11 +551,31 @@ func (c *Client) HScan(ctx context.Context, key string, cursor uint64, count int
 	return m, entry.Cursor, nil
 }
 
+// HScanPage continues an HSCAN from cursor until it has at least count fields
+// or the scan completes (see SScanPage)
+func (c *Client) HScanPage(ctx context.Context, key string, cursor uint64, match string, count int64) (map[string]string, uint64, error) {
+	fields := make(map[string]string, count)
+	for calls := 0; calls < maxScanPageCalls; calls++ {
+		batch, next, err := c.HScan(ctx, key, cursor, match, count-int64(len(fields)))
+		if err != nil {
+			return nil, 0, err
+		}
+		for f, v := range batch {
+			fields[f] = v
+		}
+		cursor = next
+		if cursor == 0 || int64(len(fields)) >= count {
+			break
+		}
+	}
+	return fields, cursor, nil
+}
+
 // Sorted set operations
 
 // ZCard returns the number of members in a sorted set
 func (c *Client) ZCard(ctx context.Context, key string) (int64, error) {
-	return c.client.Do(ctx, c.client.B().Zcard().Key(key).Build()).ToInt64()
+	return c.do(ctx, c.client.B().Zcard().Key(key).Build()).ToInt64()
 }
 
 // ZMember represents a member with score in a sorted set
@@ -239,7 +586,7 @@ type ZMember struct {
 
 // ZRangeWithScores returns members with scores from a sorted set
 func (c *Client) ZRangeWithScores(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
-	result, err := c.client.Do(ctx, c.client.B().Zrange().Key(key).Min(toString(start)).Max(toString(stop)).Withscores().Build()).AsZScores()
+	result, err := c.do(ctx, c.client.B().Zrange().Key(key).Min(toString(start)).Max(toString(stop)).Withscores().Build()).AsZScores()
 	if err != nil {
 		return nil, err
 	}
@@ -250,6 +597,111 @@ func (c *Client) ZRangeWithScores(ctx context.Context, key string, start, stop i
 	return members, nil
 }
 
+// ZScan returns members (matching a glob, "" = all) and scores of a sorted set
+// using cursor-based pagination, in no particular order
+func (c *Client) ZScan(ctx context.Context, key string, cursor uint64, match string, count int64) ([]ZMember, uint64, error) {
+	result := c.do(ctx, c.client.B().Zscan().Key(key).Cursor(cursor).Match(matchAll(match)).Count(count).Build())
+	entry, err := result.AsScanEntry()
+	if err != nil {
+		return nil, 0, err
+	}
+	// Flat slice [member1, score1, member2, score2, ...]
+	members := make([]ZMember, 0, len(entry.Elements)/2)
+	for i := 0; i+1 < len(entry.Elements); i += 2 {
+		score, err := strconv.ParseFloat(entry.Elements[i+1], 64)
+		if err != nil {
+			return nil, 0, err
+		}
+		members = append(members, ZMember{Member: entry.Elements[i], Score: score})
+	}
+	return members, entry.Cursor, nil
+}
+
+// ZScanPage continues a ZSCAN from cursor until it has at least count members
+// or the scan completes (see SScanPage). The page is sorted by score.
+func (c *Client) ZScanPage(ctx context.Context, key string, cursor uint64, match string, count int64) ([]ZMember, uint64, error) {
+	members := make([]ZMember, 0, count)
+	seen := make(map[string]bool, count)
+	for calls := 0; calls < maxScanPageCalls; calls++ {
+		batch, next, err := c.ZScan(ctx, key, cursor, match, count-int64(len(members)))
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, m := range batch {
+			if !seen[m.Member] {
+				seen[m.Member] = true
+				members = append(members, m)
+			}
+		}
+		cursor = next
+		if cursor == 0 || int64(len(members)) >= count {
+			break
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Score != members[j].Score {
+			return members[i].Score < members[j].Score
+		}
+		return members[i].Member < members[j].Member
+	})
+	return members, cursor, nil
+}
+
+// ZRangeByScore returns up to count members with scores between min and max, skipping offset.
+// Bounds use ZRANGEBYSCORE syntax: "-inf", "+inf", "1.5", or "(1.5" for exclusive.
+func (c *Client) ZRangeByScore(ctx context.Context, key, min, max string, offset, count int64) ([]ZMember, error) {
+	result, err := c.do(ctx, c.client.B().Zrangebyscore().Key(key).Min(min).Max(max).Withscores().Limit(offset, count).Build()).AsZScores()
+	if err != nil {
+		return nil, err
+	}
+	members := make([]ZMember, len(result))
+	for i, z := range result {
+		members[i] = ZMember{Member: z.Member, Score: z.Score}
+	}
+	return members, nil
+}
+
+// ZMemberPosition is the location of a single member in a sorted set
+type ZMemberPosition struct {
+	Rank    int64   `json:"rank"`    // 0-based, lowest score first
+	RevRank int64   `json:"revRank"` // 0-based, highest score first
+	Score   float64 `json:"score"`
+}
+
+// ZLocate returns the rank, reverse rank, and score of member in one round trip,
+// or nil if the member (or key) doesn't exist
+func (c *Client) ZLocate(ctx context.Context, key, member string) (*ZMemberPosition, error) {
+	results := c.client.DoMulti(ctx,
+		c.client.B().Zscore().Key(key).Member(member).Build(),
+		c.client.B().Zrank().Key(key).Member(member).Build(),
+		c.client.B().Zrevrank().Key(key).Member(member).Build(),
+	)
+
+	score, err := results[0].AsFloat64()
+	if valkey.IsValkeyNil(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rank, err := results[1].AsInt64()
+	if err != nil {
+		return nil, err
+	}
+	revRank, err := results[2].AsInt64()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ZMemberPosition{Rank: rank, RevRank: revRank, Score: score}, nil
+}
+
+// ZCount returns the number of members with scores between min and max
+func (c *Client) ZCount(ctx context.Context, key, min, max string) (int64, error) {
+	return c.do(ctx, c.client.B().Zcount().Key(key).Min(min).Max(max).Build()).ToInt64()
+}
+
 func toString(i int64) string {
 	return strconv.FormatInt(i, 10)
 }
@@ -272,7 +724,7 @@ type GeoMember struct {
 // GeoPos returns the coordinates of members in a geospatial index
 // Returns nil for members that don't exist
 func (c *Client) GeoPos(ctx context.Context, key string, members ...string) ([]*GeoPosition, error) {
-	result, err := c.client.Do(ctx, c.client.B().Geopos().Key(key).Member(members...).Build()).ToArray()
+	result, err := c.do(ctx, c.client.B().Geopos().Key(key).Member(members...).Build()).ToArray()
 	if err != nil {
 		return nil, err
 	}
@@ -305,14 +757,83 @@ func (c *Client) GeoPos(ctx context.Context, key string, members ...string) ([]*
 
 // GeoAdd adds a member with coordinates to a geospatial index
 func (c *Client) GeoAdd(ctx context.Context, key string, longitude, latitude float64, member string) error {
-	return c.client.Do(ctx, c.client.B().Geoadd().Key(key).LongitudeLatitudeMember().LongitudeLatitudeMember(longitude, latitude, member).Build()).Error()
+	return c.do(ctx, c.client.B().Geoadd().Key(key).LongitudeLatitudeMember().LongitudeLatitudeMember(longitude, latitude, member).Build()).Error()
+}
+
+// GeoHash returns the standard 11-character geohash for each member, or nil for
+// members that don't exist
+func (c *Client) GeoHash(ctx context.Context, key string, members ...string) ([]*string, error) {
+	result, err := c.do(ctx, c.client.B().Geohash().Key(key).Member(members...).Build()).ToArray()
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]*string, len(result))
+	for i, r := range result {
+		if r.IsNil() {
+			continue
+		}
+		if hash, err := r.ToString(); err == nil {
+			hashes[i] = &hash
+		}
+	}
+	return hashes, nil
+}
+
+// GeoSearchQuery describes a GEOSEARCH around a point. Set Radius for a circle,
+// or Width and Height for a bounding box.
+type GeoSearchQuery struct {
+	Longitude, Latitude float64
+	Radius              float64
+	Width, Height       float64
+	Unit                string // m, km, mi, or ft
+	Count               int64  // 0 = no limit
+}
+
+// GeoSearchResult is a member found by GeoSearch, with its distance from the center
+type GeoSearchResult struct {
+	GeoMember
+	Distance float64 `json:"distance"`
+}
+
+// GeoSearch returns members within a radius or box of a point, nearest first
+func (c *Client) GeoSearch(ctx context.Context, key string, q GeoSearchQuery) ([]GeoSearchResult, error) {
+	args := []string{"FROMLONLAT", formatFloat(q.Longitude), formatFloat(q.Latitude)}
+	if q.Radius > 0 {
+		args = append(args, "BYRADIUS", formatFloat(q.Radius), q.Unit)
+	} else {
+		args = append(args, "BYBOX", formatFloat(q.Width), formatFloat(q.Height), q.Unit)
+	}
+	args = append(args, "ASC")
+	if q.Count > 0 {
+		args = append(args, "COUNT", strconv.FormatInt(q.Count, 10))
+	}
+	args = append(args, "WITHCOORD", "WITHDIST")
+
+	locations, err := c.do(ctx, c.client.B().Arbitrary("GEOSEARCH").Keys(key).Args(args...).Build()).AsGeosearch()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]GeoSearchResult, len(locations))
+	for i, loc := range locations {
+		results[i] = GeoSearchResult{
+			GeoMember: GeoMember{Member: loc.Name, Longitude: loc.Longitude, Latitude: loc.Latitude},
+			Distance:  loc.Dist,
+		}
+	}
+	return results, nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
 }
 
 // Stream operations
 
 // XLen returns the number of entries in a stream
 func (c *Client) XLen(ctx context.Context, key string) (int64, error) {
-	return c.client.Do(ctx, c.client.B().Xlen().Key(key).Build()).ToInt64()
+	return c.do(ctx, c.client.B().Xlen().Key(key).Build()).ToInt64()
 }
 
 // StreamEntry represents an entry in a stream
@@ -327,7 +848,24 @@ func (c *Client) XRange(ctx context.Context, key, start, stop string, count int6
 	if count > 0 {
 		cmd.Count(count)
 	}
-	result, err := c.client.Do(ctx, cmd.Build()).AsXRange()
+	result, err := c.do(ctx, cmd.Build()).AsXRange()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]StreamEntry, len(result))
+	for i, e := range result {
+		entries[i] = StreamEntry{ID: e.ID, Fields: e.FieldValues}
+	}
+	return entries, nil
+}
+
+// XRevRange returns entries from a stream in reverse order, from end down to start
+func (c *Client) XRevRange(ctx context.Context, key, end, start string, count int64) ([]StreamEntry, error) {
+	cmd := c.client.B().Xrevrange().Key(key).End(end).Start(start)
+	if count > 0 {
+		cmd.Count(count)
+	}
+	result, err := c.do(ctx, cmd.Build()).AsXRange()
 	if err != nil {
 		return nil, err
 	}
@@ -338,6 +876,59 @@ func (c *Client) XRange(ctx context.Context, key, start, stop string, count int6
 	return entries, nil
 }
 
+// XRevRangePage is XRangePage newest-first: it returns up to pageSize entries
+// older than startBeforeID (or the newest entries if empty) and the cursor for
+// the next, older page ("" when there are none)
+func (c *Client) XRevRangePage(ctx context.Context, key string, startBeforeID string, pageSize int64) ([]StreamEntry, string, error) {
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	endID := "+"
+	fetchCount := pageSize + 1
+	if startBeforeID != "" {
+		endID = startBeforeID
+		fetchCount++
+	}
+	entries, err := c.XRevRange(ctx, key, endID, "-", fetchCount)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if startBeforeID != "" && len(entries) > 0 && entries[0].ID == startBeforeID {
+		entries = entries[1:]
+	}
+
+	var nextCursor string
+	if int64(len(entries)) > pageSize {
+		nextCursor = entries[pageSize-1].ID
+		entries = entries[:pageSize]
+	}
+
+	return entries, nextCursor, nil
+}
+
+// XReadBlock waits up to block for entries added to a stream after lastID and
+// returns at most count of them, or none if the wait timed out. valkey-go
+// sends blocking commands on their own pooled connection, so a waiting call
+// doesn't stall other requests.
+func (c *Client) XReadBlock(ctx context.Context, key, lastID string, count int64, block time.Duration) ([]StreamEntry, error) {
+	cmd := c.client.B().Xread().Count(count).Block(block.Milliseconds()).Streams().Key(key).Id(lastID).Build()
+	result, err := c.do(ctx, cmd).AsXRead()
+	if err != nil {
+		if valkey.IsValkeyNil(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	raw := result[key]
+	entries := make([]StreamEntry, len(raw))
+	for i, e := range raw {
+		entries[i] = StreamEntry{ID: e.ID, Fields: e.FieldValues}
+	}
+	return entries, nil
+}
+
 // XRangePage fetches a specific page of stream entries using ID-based pagination
 // startAfterID: if provided, starts after this ID (for cursor-based pagination)
 // If startAfterID is empty, starts from beginning
@@ -351,8 +942,12 @@ func (c *Client) XRangePage(ctx context.Context, key string, startAfterID string
 		startID = startAfterID
 	}
 
-	// Fetch pageSize + 1 entries to determine if there are more
+	// Fetch pageSize + 1 entries to determine if there are more, plus one for
+	// the start entry itself, which XRANGE includes and is dropped below
 	fetchCount := pageSize + 1
+	if startAfterID != "" {
+		fetchCount++
+	}
 	entries, err := c.XRange(ctx, key, startID, "+", fetchCount)
 	if err != nil {
 		return nil, "", err
@@ -378,21 +973,47 @@ func (c *Client) XRangePage(ctx context.Context, key string, startAfterID string
 
 // LPush prepends values to a list
 func (c *Client) LPush(ctx context.Context, key string, values ...string) error {
-	return c.client.Do(ctx, c.client.B().Lpush().Key(key).Element(values...).Build()).Error()
+	return c.do(ctx, c.client.B().Lpush().Key(key).Element(values...).Build()).Error()
 }
 
 // RPush appends values to a list
 func (c *Client) RPush(ctx context.Context, key string, values ...string) error {
-	return c.client.Do(ctx, c.client.B().Rpush().Key(key).Element(values...).Build()).Error()
+	return c.do(ctx, c.client.B().Rpush().Key(key).Element(values...).Build()).Error()
+}
+
+// LInsert inserts value before or after the first occurrence of pivot.
+// Returns the new list length, -1 if pivot wasn't found, or 0 if the key doesn't exist.
+func (c *Client) LInsert(ctx context.Context, key string, before bool, pivot, value string) (int64, error) {
+	cmd := c.client.B().Linsert().Key(key)
+	if before {
+		return c.do(ctx, cmd.Before().Pivot(pivot).Element(value).Build()).ToInt64()
+	}
+	return c.do(ctx, cmd.After().Pivot(pivot).Element(value).Build()).ToInt64()
+}
+
+// LTrim crops a list to the inclusive range [start, stop]
+func (c *Client) LTrim(ctx context.Context, key string, start, stop int64) error {
+	return c.do(ctx, c.client.B().Ltrim().Key(key).Start(start).Stop(stop).Build()).Error()
 }
 
 // LSet sets the value at an index in a list
 func (c *Client) LSet(ctx context.Context, key string, index int64, value string) error {
-	return c.client.Do(ctx, c.client.B().Lset().Key(key).Index(index).Element(value).Build()).Error()
+	return c.do(ctx, c.client.B().Lset().Key(key).Index(index).Element(value).Build()).Error()
+}
+
+// IsWrongType reports whether err is the server's WRONGTYPE reply, sent when a
+// command doesn't apply to the key's type. Script errors carry it mid-message.
+func IsWrongType(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "WRONGTYPE")
 }
 
+// ErrIndexOutOfRange is returned by LRemByIndex for an index past either end
+// of the list. Its text matches the server's LSET error.
+var ErrIndexOutOfRange = errors.New("index out of range")
+
 // LRemByIndex removes the element at the given index atomically using a Lua script
-// This prevents race conditions where the list could be modified between LSET and LREM
+// This prevents race conditions where the list could be modified between LSET and LREM.
+// Negative indices count from the tail (-1 = last element), as with LSET.
 func (c *Client) LRemByIndex(ctx context.Context, key string, index int64) error {
 	tombstoneID := strconv.FormatInt(time.Now().UnixNano(), 10)
 	result, err := scriptListRemoveByIndex.Eval(
@@ -407,6 +1028,9 @@ func (c *Client) LRemByIndex(ctx context.Context, key string, index int64) error
 
 	// Check if operation succeeded
 	success, ok := result.(int64)
+	if ok && success == -1 {
+		return ErrIndexOutOfRange
+	}
 	if !ok || success == 0 {
 		return fmt.Errorf("failed to remove list element at index %d", index)
 	}
@@ -418,17 +1042,17 @@ func (c *Client) LRemByIndex(ctx context.Context, key string, index int64) error
 
 // SAdd adds members to a set
 func (c *Client) SAdd(ctx context.Context, key string, members ...string) error {
-	return c.client.Do(ctx, c.client.B().Sadd().Key(key).Member(members...).Build()).Error()
+	return c.do(ctx, c.client.B().Sadd().Key(key).Member(members...).Build()).Error()
 }
 
 // SRem removes members from a set
 func (c *Client) SRem(ctx context.Context, key string, members ...string) error {
-	return c.client.Do(ctx, c.client.B().Srem().Key(key).Member(members...).Build()).Error()
+	return c.do(ctx, c.client.B().Srem().Key(key).Member(members...).Build()).Error()
 }
 
 // SIsMember checks if a member exists in a set
 func (c *Client) SIsMember(ctx context.Context, key string, member string) (bool, error) {
-	result, err := c.client.Do(ctx, c.client.B().Sismember().Key(key).Member(member).Build()).ToInt64()
+	result, err := c.do(ctx, c.client.B().Sismember().Key(key).Member(member).Build()).ToInt64()
 	return result == 1, err
 }
 
@@ -436,17 +1060,54 @@ func (c *Client) SIsMember(ctx context.Context, key string, member string) (bool
 
 // HSet sets a field value in a hash
 func (c *Client) HSet(ctx context.Context, key, field, value string) error {
-	return c.client.Do(ctx, c.client.B().Hset().Key(key).FieldValue().FieldValue(field, value).Build()).Error()
+	return c.do(ctx, c.client.B().Hset().Key(key).FieldValue().FieldValue(field, value).Build()).Error()
+}
+
+// HSetMulti sets several hash fields in a single HSET
+func (c *Client) HSetMulti(ctx context.Context, key string, fields map[string]string) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("at least one field is required")
+	}
+	cmd := c.client.B().Hset().Key(key).FieldValue()
+	for field, value := range fields {
+		cmd = cmd.FieldValue(field, value)
+	}
+	return c.do(ctx, cmd.Build()).Error()
+}
+
+// HSetNX sets a hash field only if it doesn't exist, returning false if it did
+func (c *Client) HSetNX(ctx context.Context, key, field, value string) (bool, error) {
+	return c.do(ctx, c.client.B().Hsetnx().Key(key).Field(field).Value(value).Build()).AsBool()
 }
 
 // HDel removes fields from a hash
 func (c *Client) HDel(ctx context.Context, key string, fields ...string) error {
-	return c.client.Do(ctx, c.client.B().Hdel().Key(key).Field(fields...).Build()).Error()
+	return c.do(ctx, c.client.B().Hdel().Key(key).Field(fields...).Build()).Error()
+}
+
+// HMGet returns the values of hash fields in order, with nil for absent fields
+func (c *Client) HMGet(ctx context.Context, key string, fields ...string) ([]*string, error) {
+	values, err := c.do(ctx, c.client.B().Hmget().Key(key).Field(fields...).Build()).ToArray()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*string, len(values))
+	for i, v := range values {
+		s, err := v.ToString()
+		if valkey.IsValkeyNil(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result[i] = &s
+	}
+	return result, nil
 }
 
 // HExists checks if a field exists in a hash
 func (c *Client) HExists(ctx context.Context, key, field string) (bool, error) {
-	result, err := c.client.Do(ctx, c.client.B().Hexists().Key(key).Field(field).Build()).ToInt64()
+	result, err := c.do(ctx, c.client.B().Hexists().Key(key).Field(field).Build()).ToInt64()
 	return result == 1, err
 }
 
@@ -454,32 +1115,71 @@ func (c *Client) HExists(ctx context.Context, key, field string) (bool, error) {
 
 // ZAdd adds a member with score to a sorted set
 func (c *Client) ZAdd(ctx context.Context, key string, member string, score float64) error {
-	return c.client.Do(ctx, c.client.B().Zadd().Key(key).ScoreMember().ScoreMember(score, member).Build()).Error()
+	return c.do(ctx, c.client.B().Zadd().Key(key).ScoreMember().ScoreMember(score, member).Build()).Error()
 }
 
 // ZIncrBy increments the score of a member in a sorted set
 func (c *Client) ZIncrBy(ctx context.Context, key string, member string, amount float64) (float64, error) {
-	return c.client.Do(ctx, c.client.B().Zincrby().Key(key).Increment(amount).Member(member).Build()).AsFloat64()
+	return c.do(ctx, c.client.B().Zincrby().Key(key).Increment(amount).Member(member).Build()).AsFloat64()
+}
+
+// ZMScore returns the scores of members in order, with nil for absent members
+func (c *Client) ZMScore(ctx context.Context, key string, members ...string) ([]*float64, error) {
+	values, err := c.do(ctx, c.client.B().Zmscore().Key(key).Member(members...).Build()).ToArray()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*float64, len(values))
+	for i, v := range values {
+		f, err := v.AsFloat64()
+		if valkey.IsValkeyNil(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result[i] = &f
+	}
+	return result, nil
 }
 
 // ZRem removes members from a sorted set
 func (c *Client) ZRem(ctx context.Context, key string, members ...string) error {
-	return c.client.Do(ctx, c.client.B().Zrem().Key(key).Member(members...).Build()).Error()
+	return c.do(ctx, c.client.B().Zrem().Key(key).Member(members...).Build()).Error()
 }
 
 // Stream write operations
 
+// XAddOptions controls optional XADD arguments
+type XAddOptions struct {
+	ID     string // explicit entry ID ("ms-seq"), empty = auto-generate with "*"
+	MaxLen int64  // cap the stream at this many entries after adding, 0 = no cap
+	Approx bool   // trim with "~" so the server may keep slightly more entries
+}
+
 // XAddMulti appends an entry with multiple fields to a stream
-func (c *Client) XAddMulti(ctx context.Context, key string, fields map[string]string) (string, error) {
+func (c *Client) XAddMulti(ctx context.Context, key string, fields map[string]string, opts XAddOptions) (string, error) {
 	if len(fields) == 0 {
 		return "", fmt.Errorf("at least one field is required")
 	}
 	// Build command with arbitrary fields using Arbitrary
-	args := []string{"XADD", key, "*"}
+	args := []string{"XADD", key}
+	if opts.MaxLen > 0 {
+		args = append(args, "MAXLEN")
+		if opts.Approx {
+			args = append(args, "~")
+		}
+		args = append(args, strconv.FormatInt(opts.MaxLen, 10))
+	}
+	id := opts.ID
+	if id == "" {
+		id = "*"
+	}
+	args = append(args, id)
 	for k, v := range fields {
 		args = append(args, k, v)
 	}
-	return c.client.Do(ctx, c.client.B().Arbitrary(args...).Build()).ToString()
+	return c.do(ctx, c.client.B().Arbitrary(args...).Build()).ToString()
 }
 
 // XDel removes one or more entries from a stream by ID
@@ -487,19 +1187,124 @@ func (c *Client) XDel(ctx context.Context, key string, ids ...string) (int64, er
 	if len(ids) == 0 {
 		return 0, fmt.Errorf("at least one ID is required")
 	}
-	return c.client.Do(ctx, c.client.B().Xdel().Key(key).Id(ids...).Build()).ToInt64()
+	return c.do(ctx, c.client.B().Xdel().Key(key).Id(ids...).Build()).ToInt64()
+}
+
+// XTrim trims a stream by strategy ("MAXLEN" or "MINID") and returns the number of entries removed.
+// With approx, trimming uses "~" and may leave slightly more entries for efficiency.
+func (c *Client) XTrim(ctx context.Context, key, strategy, threshold string, approx bool) (int64, error) {
+	cmd := c.client.B().Xtrim().Key(key)
+	var built valkey.Completed
+	switch strings.ToUpper(strategy) {
+	case "MAXLEN":
+		if approx {
+			built = cmd.Maxlen().Almost().Threshold(threshold).Build()
+		} else {
+			built = cmd.Maxlen().Threshold(threshold).Build()
+		}
+	case "MINID":
+		if approx {
+			built = cmd.Minid().Almost().Threshold(threshold).Build()
+		} else {
+			built = cmd.Minid().Threshold(threshold).Build()
+		}
+	default:
+		return 0, fmt.Errorf("unsupported trim strategy: %s", strategy)
+	}
+	return c.do(ctx, built).ToInt64()
 }
 
 // HyperLogLog operations
 
 // PFCount returns the approximate cardinality of the HyperLogLog
 func (c *Client) PFCount(ctx context.Context, key string) (int64, error) {
-	return c.client.Do(ctx, c.client.B().Pfcount().Key(key).Build()).ToInt64()
+	return c.do(ctx, c.client.B().Pfcount().Key(key).Build()).ToInt64()
 }
 
 // PFAdd adds elements to a HyperLogLog
 func (c *Client) PFAdd(ctx context.Context, key string, elements ...string) error {
-	return c.client.Do(ctx, c.client.B().Pfadd().Key(key).Element(elements...).Build()).Error()
+	return c.do(ctx, c.client.B().Pfadd().Key(key).Element(elements...).Build()).Error()
+}
+
+// PFMerge merges the source HyperLogLogs into dest (including dest's own registers)
+// and returns the resulting approximate cardinality
+func (c *Client) PFMerge(ctx context.Context, dest string, sources ...string) (int64, error) {
+	if err := c.checkSameSlot(append([]string{dest}, sources...)...); err != nil {
+		return 0, err
+	}
+	if err := c.do(ctx, c.client.B().Pfmerge().Destkey(dest).Sourcekey(sources...).Build()).Error(); err != nil {
+		return 0, err
+	}
+	return c.PFCount(ctx, dest)
+}
+
+// Bitmap operations
+
+// GetBit returns the bit value at offset in the string stored at key
+func (c *Client) GetBit(ctx context.Context, key string, offset int64) (int64, error) {
+	return c.do(ctx, c.client.B().Getbit().Key(key).Offset(offset).Build()).ToInt64()
+}
+
+// SetBit sets or clears the bit at offset and returns the previous bit value
+func (c *Client) SetBit(ctx context.Context, key string, offset int64, value int64) (int64, error) {
+	return c.do(ctx, c.client.B().Setbit().Key(key).Offset(offset).Value(value).Build()).ToInt64()
+}
+
+// BitCount returns the number of set bits. If byteRange is non-nil, only bytes
+// in [byteRange[0], byteRange[1]] are counted (negative indices count from the end).
+func (c *Client) BitCount(ctx context.Context, key string, byteRange *[2]int64) (int64, error) {
+	cmd := c.client.B().Bitcount().Key(key)
+	if byteRange != nil {
+		return c.do(ctx, cmd.Start(byteRange[0]).End(byteRange[1]).Build()).ToInt64()
+	}
+	return c.do(ctx, cmd.Build()).ToInt64()
+}
+
+// BitField runs BITFIELD with the given operations (e.g. "GET u8 0", "INCRBY u4 100 1").
+// Returns one result per GET/SET/INCRBY; nil entries are increments that hit OVERFLOW FAIL.
+func (c *Client) BitField(ctx context.Context, key string, ops []string) ([]*int64, error) {
+	args := []string{"BITFIELD", key}
+	for _, op := range ops {
+		args = append(args, strings.Fields(op)...)
+	}
+
+	result, err := c.do(ctx, c.client.B().Arbitrary(args...).Build()).ToArray()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]*int64, len(result))
+	for i, r := range result {
+		if r.IsNil() {
+			continue
+		}
+		v, err := r.AsInt64()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = &v
+	}
+	return values, nil
+}
+
+// StrLen returns the length in bytes of the string stored at key
+func (c *Client) StrLen(ctx context.Context, key string) (int64, error) {
+	return c.do(ctx, c.client.B().Strlen().Key(key).Build()).ToInt64()
+}
+
+// GetRange returns the substring between byte offsets start and end (inclusive)
+func (c *Client) GetRange(ctx context.Context, key string, start, end int64) (string, error) {
+	return c.do(ctx, c.client.B().Getrange().Key(key).Start(start).End(end).Build()).ToString()
+}
+
+// Append appends value to a string and returns the new length
+func (c *Client) Append(ctx context.Context, key, value string) (int64, error) {
+	return c.do(ctx, c.client.B().Append().Key(key).Value(value).Build()).ToInt64()
+}
+
+// SetRange overwrites part of a string starting at offset and returns the new length
+func (c *Client) SetRange(ctx context.Context, key string, offset int64, value string) (int64, error) {
+	return c.do(ctx, c.client.B().Setrange().Key(key).Offset(offset).Value(value).Build()).ToInt64()
 }
 
 // MemoryStats represents memory usage statistics
@@ -510,30 +1315,15 @@ type MemoryStats struct {
 
 // GetMemoryStats returns memory usage statistics from INFO memory
 func (c *Client) GetMemoryStats(ctx context.Context) (*MemoryStats, error) {
-	info, err := c.Info(ctx, "memory")
+	info, err := c.InfoMap(ctx, "memory")
 	if err != nil {
 		return nil, err
 	}
 
-	stats := &MemoryStats{}
-
-	// Parse the INFO response (format: "key:value\r\n")
-	lines := strings.Split(info, "\r\n")
-	for _, line := range lines {
-		parts := strings.SplitN(line, ":", 2)
-		if len(parts) != 2 {
-			continue
-		}
-		key, value := parts[0], parts[1]
-
-		switch key {
-		case "used_memory":
-			if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
-				stats.UsedMemory = parsed
-			}
-		case "used_memory_human":
-			stats.UsedMemoryHuman = value
-		}
+	memory := info["memory"]
+	stats := &MemoryStats{UsedMemoryHuman: memory["used_memory_human"]}
+	if parsed, err := strconv.ParseInt(memory["used_memory"], 10, 64); err == nil {
+		stats.UsedMemory = parsed
 	}
 
 	return stats, nil
@@ -541,23 +1331,47 @@ func (c *Client) GetMemoryStats(ctx context.Context) (*MemoryStats, error) {
 
 // Exec executes an arbitrary command and returns the result as a generic value.
 func (c *Client) Exec(ctx context.Context, args []string) (any, error) {
-	return c.client.Do(ctx, c.client.B().Arbitrary(args...).Build()).ToAny()
+	return c.do(ctx, c.client.B().Arbitrary(args...).Build()).ToAny()
 }
 
 // Config operations
 
 // GetNotifyKeyspaceEvents returns the current notify-keyspace-events setting
 func (c *Client) GetNotifyKeyspaceEvents(ctx context.Context) (string, error) {
-	result, err := c.client.Do(ctx, c.client.B().ConfigGet().Parameter("notify-keyspace-events").Build()).AsStrMap()
+	result, err := c.ConfigGet(ctx, "notify-keyspace-events")
 	if err != nil {
 		return "", err
 	}
 	return result["notify-keyspace-events"], nil
 }
 
-// SetNotifyKeyspaceEvents enables/disables keyspace notifications
+// SetNotifyKeyspaceEvents enables/disables keyspace notifications (on every
+// primary in a cluster, since each node only notifies about its own keys)
 func (c *Client) SetNotifyKeyspaceEvents(ctx context.Context, value string) error {
-	return c.client.Do(ctx, c.client.B().ConfigSet().ParameterValue().ParameterValue("notify-keyspace-events", value).Build()).Error()
+	return c.ConfigSet(ctx, map[string]string{"notify-keyspace-events": value})
+}
+
+// ConfigGet returns server parameters matching a glob pattern
+func (c *Client) ConfigGet(ctx context.Context, pattern string) (map[string]string, error) {
+	return c.do(ctx, c.client.B().ConfigGet().Parameter(pattern).Build()).AsStrMap()
+}
+
+// ConfigSet applies several parameters in one CONFIG SET, which the server applies
+// atomically: if any value is rejected, none are changed. In a cluster it is sent
+// to every primary, stopping at the first node that rejects it.
+func (c *Client) ConfigSet(ctx context.Context, params map[string]string) error {
+	build := func(n valkey.Client) valkey.Completed {
+		cmd := n.B().ConfigSet().ParameterValue()
+		for name, value := range params {
+			cmd = cmd.ParameterValue(name, value)
+		}
+		return cmd.Build()
+	}
+	if c.cluster {
+		_, err := c.onPrimaries(ctx, build)
+		return err
+	}
+	return c.do(ctx, build(c.client)).Error()
 }
 
 // Script-based atomic operations
@@ -644,9 +1458,40 @@ func (c *Client) HRename(ctx context.Context, key, oldField, newField string) (s
 	return value, nil
 }
 
+// ObjectEncoding returns the internal encoding of a key (e.g. "listpack", "hashtable")
+func (c *Client) ObjectEncoding(ctx context.Context, key string) (string, error) {
+	return c.do(ctx, c.client.B().ObjectEncoding().Key(key).Build()).ToString()
+}
+
+// ObjectRefCount returns the reference count of the value stored at key
+func (c *Client) ObjectRefCount(ctx context.Context, key string) (int64, error) {
+	return c.do(ctx, c.client.B().ObjectRefcount().Key(key).Build()).ToInt64()
+}
+
+// ObjectIdleTime returns seconds since the key was last accessed.
+// Fails when an LFU maxmemory policy is active, since idle time isn't tracked then.
+func (c *Client) ObjectIdleTime(ctx context.Context, key string) (int64, error) {
+	return c.do(ctx, c.client.B().ObjectIdletime().Key(key).Build()).ToInt64()
+}
+
+// ObjectFreq returns the logarithmic access frequency counter of key.
+// Only available when an LFU maxmemory policy is active.
+func (c *Client) ObjectFreq(ctx context.Context, key string) (int64, error) {
+	return c.do(ctx, c.client.B().ObjectFreq().Key(key).Build()).ToInt64()
+}
+
+// MaxMemoryPolicy returns the eviction policy (e.g. allkeys-lru, volatile-lfu, noeviction)
+func (c *Client) MaxMemoryPolicy(ctx context.Context) (string, error) {
+	result, err := c.ConfigGet(ctx, "maxmemory-policy")
+	if err != nil {
+		return "", err
+	}
+	return result["maxmemory-policy"], nil
+}
+
 // MemoryUsage returns the memory usage of a single key in bytes.
 func (c *Client) MemoryUsage(ctx context.Context, key string) (int64, error) {
-	return c.client.Do(ctx, c.client.B().MemoryUsage().Key(key).Build()).ToInt64()
+	return c.do(ctx, c.client.B().MemoryUsage().Key(key).Build()).ToInt64()
 }
 
 // MemoryUsageBatch returns memory usage in bytes for each key using pipelined MEMORY USAGE calls.
@@ -675,6 +1520,74 @@ func (c *Client) MemoryUsageBatch(ctx context.Context, keys []string) (map[strin
 	return usage, nil
 }
 
+// TTLBatch returns the TTL in seconds (-1 = no expiry) for each key using pipelined
+// TTL calls. Keys that no longer exist are omitted.
+func (c *Client) TTLBatch(ctx context.Context, keys []string) map[string]int64 {
+	ttls := make(map[string]int64, len(keys))
+	if len(keys) == 0 {
+		return ttls
+	}
+
+	cmds := make([]valkey.Completed, len(keys))
+	for i, key := range keys {
+		cmds[i] = c.client.B().Ttl().Key(key).Build()
+	}
+
+	for i, r := range c.client.DoMulti(ctx, cmds...) {
+		ttl, err := r.ToInt64()
+		if err != nil || ttl == -2 {
+			continue // key expired or deleted since SCAN
+		}
+		ttls[keys[i]] = ttl
+	}
+	return ttls
+}
+
+// HGetBatch returns the value of field for each key using pipelined HGET calls.
+// Keys that are missing, not hashes, or lack the field are omitted.
+func (c *Client) HGetBatch(ctx context.Context, keys []string, field string) map[string]string {
+	values := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return values
+	}
+
+	cmds := make([]valkey.Completed, len(keys))
+	for i, key := range keys {
+		cmds[i] = c.client.B().Hget().Key(key).Field(field).Build()
+	}
+
+	for i, r := range c.client.DoMulti(ctx, cmds...) {
+		v, err := r.ToString()
+		if err != nil {
+			continue // nil field or wrong type
+		}
+		values[keys[i]] = v
+	}
+	return values
+}
+
+// ExistsBatch reports whether each key exists using pipelined EXISTS calls
+func (c *Client) ExistsBatch(ctx context.Context, keys []string) (map[string]bool, error) {
+	exists := make(map[string]bool, len(keys))
+	if len(keys) == 0 {
+		return exists, nil
+	}
+
+	cmds := make([]valkey.Completed, len(keys))
+	for i, key := range keys {
+		cmds[i] = c.client.B().Exists().Key(key).Build()
+	}
+
+	for i, r := range c.client.DoMulti(ctx, cmds...) {
+		n, err := r.ToInt64()
+		if err != nil {
+			return nil, err
+		}
+		exists[keys[i]] = n > 0
+	}
+	return exists, nil
+}
+
 // KeyMetadata represents metadata about a key
 type KeyMetadata struct {
 	Type string
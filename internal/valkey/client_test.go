@@ -0,0 +1,567 @@
+package valkey
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+// newTestClient connects to a local Valkey/Redis instance on DB 15,
+// skipping the test if none is available
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	cfg := &config.Config{
+		ValkeyURL: "localhost:6379",
+		ValkeyDB:  15, // Use DB 15 for testing
+	}
+
+	client, err := New(cfg)
+	if err != nil {
+		t.Skip("Valkey not available:", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+// TestIncrement tests integer and float increments
+// This requires a running Valkey/Redis instance
+func TestIncrement(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	defer func() {
+		_, _ = client.Del(ctx, "test:counter", "test:float")
+	}()
+
+	t.Run("Integer", func(t *testing.T) {
+		key := "test:counter"
+		_, _ = client.Del(ctx, key)
+
+		n, err := client.IncrBy(ctx, key, 5)
+		if err != nil {
+			t.Fatalf("IncrBy failed: %v", err)
+		}
+		if n != 5 {
+			t.Errorf("expected 5, got %d", n)
+		}
+
+		n, err = client.DecrBy(ctx, key, 2)
+		if err != nil {
+			t.Fatalf("DecrBy failed: %v", err)
+		}
+		if n != 3 {
+			t.Errorf("expected 3, got %d", n)
+		}
+
+		// Stored value stays a plain integer string
+		val, err := client.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if val != "3" {
+			t.Errorf("expected stored value %q, got %q", "3", val)
+		}
+	})
+
+	t.Run("Float", func(t *testing.T) {
+		key := "test:float"
+		_, _ = client.Del(ctx, key)
+
+		val, err := client.IncrByFloat(ctx, key, 1.5)
+		if err != nil {
+			t.Fatalf("IncrByFloat failed: %v", err)
+		}
+		if val != "1.5" {
+			t.Errorf("expected %q, got %q", "1.5", val)
+		}
+
+		// Integer commands reject a float value
+		if _, err := client.IncrBy(ctx, key, 1); err == nil {
+			t.Error("expected IncrBy on a float value to fail")
+		}
+	})
+}
+
+// TestXAddMulti tests that every field of a stream entry is persisted
+// This requires a running Valkey/Redis instance
+func TestXAddMulti(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	key := "test:stream"
+	_, _ = client.Del(ctx, key)
+	defer func() {
+		_, _ = client.Del(ctx, key)
+	}()
+
+	fields := map[string]string{"name": "Alice", "age": "30", "city": "Paris"}
+	id, err := client.XAddMulti(ctx, key, fields, XAddOptions{})
+	if err != nil {
+		t.Fatalf("XAddMulti failed: %v", err)
+	}
+
+	entries, err := client.XRange(ctx, key, id, id, 1)
+	if err != nil {
+		t.Fatalf("XRange failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if len(entries[0].Fields) != len(fields) {
+		t.Errorf("expected %d fields, got %d", len(fields), len(entries[0].Fields))
+	}
+	for f, v := range fields {
+		if got := entries[0].Fields[f]; got != v {
+			t.Errorf("field %q: expected %q, got %q", f, v, got)
+		}
+	}
+
+	// An empty entry is rejected instead of sent to the server
+	if _, err := client.XAddMulti(ctx, key, map[string]string{}, XAddOptions{}); err == nil {
+		t.Error("expected error for entry with no fields")
+	}
+}
+
+// TestZIncrBy tests atomic score increments on sorted set members
+// This requires a running Valkey/Redis instance
+func TestZIncrBy(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	key := "test:leaderboard"
+	_, _ = client.Del(ctx, key)
+	defer func() {
+		_, _ = client.Del(ctx, key)
+	}()
+
+	// Incrementing a missing member creates it
+	score, err := client.ZIncrBy(ctx, key, "alice", 1.5)
+	if err != nil {
+		t.Fatalf("ZIncrBy failed: %v", err)
+	}
+	if score != 1.5 {
+		t.Errorf("expected score 1.5, got %v", score)
+	}
+
+	score, err = client.ZIncrBy(ctx, key, "alice", -0.5)
+	if err != nil {
+		t.Fatalf("ZIncrBy failed: %v", err)
+	}
+	if score != 1 {
+		t.Errorf("expected score 1, got %v", score)
+	}
+}
+
+func TestDumpRestore(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	src, dst := "test:dump:src", "test:dump:dst"
+	_, _ = client.Del(ctx, src, dst)
+	defer func() {
+		_, _ = client.Del(ctx, src, dst)
+	}()
+
+	if err := client.RPush(ctx, src, "a", "b", "c"); err != nil {
+		t.Fatalf("RPush failed: %v", err)
+	}
+
+	payload, err := client.Dump(ctx, src)
+	if err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+
+	if err := client.Restore(ctx, dst, 0, payload, false); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	items, err := client.LRange(ctx, dst, 0, -1)
+	if err != nil {
+		t.Fatalf("LRange failed: %v", err)
+	}
+	if len(items) != 3 || items[0] != "a" || items[2] != "c" {
+		t.Errorf("restored list = %v, want [a b c]", items)
+	}
+
+	// Restoring over an existing key needs replace
+	err = client.Restore(ctx, dst, 0, payload, false)
+	if err == nil || !strings.HasPrefix(err.Error(), "BUSYKEY") {
+		t.Errorf("expected BUSYKEY error, got %v", err)
+	}
+	if err := client.Restore(ctx, dst, 0, payload, true); err != nil {
+		t.Errorf("Restore with replace failed: %v", err)
+	}
+
+	if _, err := client.Dump(ctx, "test:dump:missing"); !IsNil(err) {
+		t.Errorf("expected nil error for missing key, got %v", err)
+	}
+}
+
+// TestFieldSnapshots tests HMGet and ZMScore, which report absent entries as nil
+func TestFieldSnapshots(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	hash, zset := "test:snap:hash", "test:snap:zset"
+	_, _ = client.Del(ctx, hash, zset)
+	defer func() {
+		_, _ = client.Del(ctx, hash, zset)
+	}()
+
+	if err := client.HSet(ctx, hash, "a", "1"); err != nil {
+		t.Fatalf("HSet failed: %v", err)
+	}
+	values, err := client.HMGet(ctx, hash, "a", "missing")
+	if err != nil {
+		t.Fatalf("HMGet failed: %v", err)
+	}
+	if len(values) != 2 || values[0] == nil || *values[0] != "1" || values[1] != nil {
+		t.Errorf("HMGet = %v, want [1 nil]", values)
+	}
+
+	if err := client.ZAdd(ctx, zset, "m", 2.5); err != nil {
+		t.Fatalf("ZAdd failed: %v", err)
+	}
+	scores, err := client.ZMScore(ctx, zset, "m", "missing")
+	if err != nil {
+		t.Fatalf("ZMScore failed: %v", err)
+	}
+	if len(scores) != 2 || scores[0] == nil || *scores[0] != 2.5 || scores[1] != nil {
+		t.Errorf("ZMScore = %v, want [2.5 nil]", scores)
+	}
+}
+
+func TestValueVersion(t *testing.T) {
+	if ValueVersion("a") != ValueVersion("a") {
+		t.Error("expected the same value to have the same version")
+	}
+	if ValueVersion("a") == ValueVersion("b") {
+		t.Error("expected different values to have different versions")
+	}
+	if ValueVersion("") == "" {
+		t.Error("expected a non-empty version for an empty value")
+	}
+}
+
+// TestSetIfUnchanged tests the WATCH-based conditional set
+// This requires a running Valkey/Redis instance
+func TestSetIfUnchanged(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	key := "test:cas"
+	_, _ = client.Del(ctx, key)
+	defer func() {
+		_, _ = client.Del(ctx, key)
+	}()
+
+	if ok, err := client.SetIfUnchanged(ctx, key, ValueVersion(""), "x", 0); err != nil || ok {
+		t.Errorf("expected missing key not to match, got ok=%v err=%v", ok, err)
+	}
+
+	if err := client.Set(ctx, key, "v1", 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	loaded := ValueVersion("v1")
+
+	ok, err := client.SetIfUnchanged(ctx, key, loaded, "v2", 0)
+	if err != nil || !ok {
+		t.Fatalf("expected matching version to save, got ok=%v err=%v", ok, err)
+	}
+
+	// The same stale version must now be rejected
+	ok, err = client.SetIfUnchanged(ctx, key, loaded, "v3", 0)
+	if err != nil || ok {
+		t.Errorf("expected stale version to be rejected, got ok=%v err=%v", ok, err)
+	}
+	if val, _ := client.Get(ctx, key); val != "v2" {
+		t.Errorf("expected value v2, got %q", val)
+	}
+}
+
+// TestTransaction tests MULTI/EXEC with per-op results
+// This requires a running Valkey/Redis instance
+func TestTransaction(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	str, hash := "test:tx:str", "test:tx:hash"
+	_, _ = client.Del(ctx, str, hash)
+	defer func() {
+		_, _ = client.Del(ctx, str, hash)
+	}()
+
+	results, err := client.Transaction(ctx, []TxOp{
+		{Op: "set", Key: str, Value: "v"},
+		{Op: "hset", Key: hash, Field: "f", Value: "1"},
+		{Op: "hset", Key: str, Field: "f", Value: "1"}, // WRONGTYPE at EXEC time
+		{Op: "expire", Key: str, TTL: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	if results[0].Result != "OK" || results[1].Result != int64(1) || results[3].Result != int64(1) {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if results[2].Error == "" {
+		t.Error("expected WRONGTYPE error for hset on a string")
+	}
+}
+
+// TestScanPagesLargeCollections pages through 10k-member collections the way
+// the key view does, checking every member is seen and the cursor terminates
+// This requires a running Valkey/Redis instance
+func TestScanPagesLargeCollections(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	const size = 10000
+	const pageSize = 100
+	set, hash := "test:pages:set", "test:pages:hash"
+	_, _ = client.Del(ctx, set, hash)
+	defer func() {
+		_, _ = client.Del(ctx, set, hash)
+	}()
+
+	members := make([]string, size)
+	fields := make(map[string]string, size)
+	for i := range members {
+		members[i] = fmt.Sprintf("m%05d", i)
+		fields[members[i]] = "v"
+	}
+	if err := client.SAdd(ctx, set, members...); err != nil {
+		t.Fatalf("SAdd failed: %v", err)
+	}
+	if err := client.HSetMulti(ctx, hash, fields); err != nil {
+		t.Fatalf("HSetMulti failed: %v", err)
+	}
+
+	t.Run("set", func(t *testing.T) {
+		seen := make(map[string]bool, size)
+		cursor, pages := uint64(0), 0
+		for {
+			page, next, err := client.SScanPage(ctx, set, cursor, "", pageSize)
+			if err != nil {
+				t.Fatalf("SScanPage failed: %v", err)
+			}
+			if next != 0 && len(page) < pageSize {
+				t.Errorf("page %d has %d members before the end, want at least %d", pages, len(page), pageSize)
+			}
+			for _, m := range page {
+				seen[m] = true
+			}
+			pages++
+			if cursor = next; cursor == 0 {
+				break
+			}
+			if pages > size {
+				t.Fatal("scan did not terminate")
+			}
+		}
+		if len(seen) != size {
+			t.Errorf("saw %d distinct members, want %d", len(seen), size)
+		}
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		seen := make(map[string]bool, size)
+		cursor, pages := uint64(0), 0
+		for {
+			page, next, err := client.HScanPage(ctx, hash, cursor, "", pageSize)
+			if err != nil {
+				t.Fatalf("HScanPage failed: %v", err)
+			}
+			for f := range page {
+				seen[f] = true
+			}
+			pages++
+			if cursor = next; cursor == 0 {
+				break
+			}
+			if pages > size {
+				t.Fatal("scan did not terminate")
+			}
+		}
+		if len(seen) != size {
+			t.Errorf("saw %d distinct fields, want %d", len(seen), size)
+		}
+	})
+	t.Run("match", func(t *testing.T) {
+		var setMatches, hashMatches []string
+		for cursor := uint64(0); ; {
+			page, next, err := client.SScanPage(ctx, set, cursor, "m0001*", pageSize)
+			if err != nil {
+				t.Fatalf("SScanPage failed: %v", err)
+			}
+			setMatches = append(setMatches, page...)
+			if cursor = next; cursor == 0 {
+				break
+			}
+		}
+		for cursor := uint64(0); ; {
+			page, next, err := client.HScanPage(ctx, hash, cursor, "m0001*", pageSize)
+			if err != nil {
+				t.Fatalf("HScanPage failed: %v", err)
+			}
+			for f := range page {
+				hashMatches = append(hashMatches, f)
+			}
+			if cursor = next; cursor == 0 {
+				break
+			}
+		}
+		// m00010 through m00019
+		if len(setMatches) != 10 || len(hashMatches) != 10 {
+			t.Errorf("matched %d set members and %d hash fields, want 10 each", len(setMatches), len(hashMatches))
+		}
+	})
+}
+
+// TestXRangePageCursor pages through a stream by cursor, checking every page
+// but the last reports more entries
+// This requires a running Valkey/Redis instance
+func TestXRangePageCursor(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	key := "test:stream:pages"
+	_, _ = client.Del(ctx, key)
+	defer func() {
+		_, _ = client.Del(ctx, key)
+	}()
+
+	for i := range 25 {
+		if _, err := client.XAddMulti(ctx, key, map[string]string{"n": fmt.Sprint(i)}, XAddOptions{}); err != nil {
+			t.Fatalf("XAdd failed: %v", err)
+		}
+	}
+
+	var seen int
+	cursor := ""
+	for pages := 1; ; pages++ {
+		entries, next, err := client.XRangePage(ctx, key, cursor, 10)
+		if err != nil {
+			t.Fatalf("XRangePage failed: %v", err)
+		}
+		seen += len(entries)
+		if next == "" {
+			if pages != 3 {
+				t.Errorf("expected 3 pages, got %d", pages)
+			}
+			break
+		}
+		if pages > 3 {
+			t.Fatal("paging did not terminate")
+		}
+		cursor = next
+	}
+	if seen != 25 {
+		t.Errorf("saw %d entries, want 25", seen)
+	}
+}
+
+// TestXRevRangePage pages a stream newest-first
+// This requires a running Valkey/Redis instance
+func TestXRevRangePage(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	key := "test:stream:rev"
+	_, _ = client.Del(ctx, key)
+	defer func() {
+		_, _ = client.Del(ctx, key)
+	}()
+
+	var ids []string
+	for i := range 15 {
+		id, err := client.XAddMulti(ctx, key, map[string]string{"n": fmt.Sprint(i)}, XAddOptions{})
+		if err != nil {
+			t.Fatalf("XAdd failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	first, next, err := client.XRevRangePage(ctx, key, "", 10)
+	if err != nil {
+		t.Fatalf("XRevRangePage failed: %v", err)
+	}
+	if len(first) != 10 || first[0].ID != ids[14] || next == "" {
+		t.Fatalf("first page: %d entries starting %v, next %q", len(first), first[0].ID, next)
+	}
+
+	second, next, err := client.XRevRangePage(ctx, key, next, 10)
+	if err != nil {
+		t.Fatalf("XRevRangePage failed: %v", err)
+	}
+	if len(second) != 5 || second[0].ID != ids[4] || second[4].ID != ids[0] || next != "" {
+		t.Errorf("second page: %d entries, next %q", len(second), next)
+	}
+}
+
+// TestZScanPage checks ZSCAN pages filter by member name and come back sorted by score
+// This requires a running Valkey/Redis instance
+func TestZScanPage(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	key := "test:zscan"
+	_, _ = client.Del(ctx, key)
+	defer func() {
+		_, _ = client.Del(ctx, key)
+	}()
+
+	for i := range 200 {
+		if err := client.ZAdd(ctx, key, fmt.Sprintf("player:%03d", i), float64(200-i)); err != nil {
+			t.Fatalf("ZAdd failed: %v", err)
+		}
+	}
+
+	var matched []ZMember
+	for cursor := uint64(0); ; {
+		page, next, err := client.ZScanPage(ctx, key, cursor, "player:01*", 50)
+		if err != nil {
+			t.Fatalf("ZScanPage failed: %v", err)
+		}
+		for i := 1; i < len(page); i++ {
+			if page[i-1].Score > page[i].Score {
+				t.Errorf("page not sorted by score: %v", page)
+				break
+			}
+		}
+		matched = append(matched, page...)
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+	// player:010 through player:019
+	if len(matched) != 10 {
+		t.Errorf("matched %d members, want 10", len(matched))
+	}
+}
+
+func TestAuthError(t *testing.T) {
+	wrongPass := fmt.Errorf("WRONGPASS invalid username-password pair or user is disabled.")
+	err := authError(wrongPass, "reader")
+	if err == nil || !strings.Contains(err.Error(), `"reader"`) {
+		t.Errorf("authError(WRONGPASS) = %v, want it to name the user", err)
+	}
+	if err := authError(wrongPass, ""); err == nil || !strings.Contains(err.Error(), `"default"`) {
+		t.Errorf("authError(WRONGPASS) without username = %v, want the default user", err)
+	}
+	if err := authError(fmt.Errorf("NOAUTH Authentication required."), ""); err == nil || !strings.Contains(err.Error(), "requires authentication") {
+		t.Errorf("authError(NOAUTH) = %v", err)
+	}
+	if err := authError(fmt.Errorf("dial tcp: connection refused"), ""); err != nil {
+		t.Errorf("authError(other) = %v, want nil", err)
+	}
+}
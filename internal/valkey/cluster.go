@@ -0,0 +1,167 @@
+package valkey
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// In cluster mode a SCAN cursor covers every primary in turn: the low
+// clusterNodeBits bits hold the primary's index and the rest that node's own
+// cursor. The low bits keep cursors small, since the API sends them as JSON
+// numbers and browsers lose precision past 2^53.
+const (
+	clusterNodeBits = 8
+	maxClusterNodes = 1 << clusterNodeBits
+	nodeIndexMask   = maxClusterNodes - 1
+)
+
+// primariesTTL is how long the list of primaries is reused before ROLE is
+// asked again, so paging through keys doesn't cost a round trip per node
+const primariesTTL = 10 * time.Second
+
+// clusterNode is one primary, by address
+type clusterNode struct {
+	addr   string
+	client valkey.Client
+}
+
+// primaryCache remembers the cluster's primaries, sorted by address so the
+// node index in a SCAN cursor means the same node from page to page
+type primaryCache struct {
+	mu      sync.Mutex
+	nodes   []clusterNode
+	fetched time.Time
+}
+
+// IsCluster reports whether the server is a Valkey Cluster
+func (c *Client) IsCluster() bool {
+	return c.cluster
+}
+
+// detectCluster asks the server whether cluster mode is enabled
+func detectCluster(ctx context.Context, client valkey.Client) (bool, error) {
+	info, err := client.Do(ctx, client.B().Info().Section("cluster").Build()).ToString()
+	if err != nil {
+		return false, err
+	}
+	return infoField(info, "cluster_enabled") == "1", nil
+}
+
+// primaries returns the cluster's primary nodes. The driver also connects to
+// replicas, which hold the same keys, so each node is asked for its ROLE.
+func (c *Client) primaries(ctx context.Context) ([]clusterNode, error) {
+	c.primaryCache.mu.Lock()
+	defer c.primaryCache.mu.Unlock()
+	if c.primaryCache.nodes != nil && time.Since(c.primaryCache.fetched) < primariesTTL {
+		return c.primaryCache.nodes, nil
+	}
+
+	var nodes []clusterNode
+	for addr, node := range c.client.Nodes() {
+		role, err := node.Do(ctx, node.B().Role().Build()).ToArray()
+		if err != nil {
+			return nil, fmt.Errorf("ROLE on %s: %w", addr, err)
+		}
+		if len(role) == 0 {
+			continue
+		}
+		if name, _ := role[0].ToString(); name == "master" {
+			nodes = append(nodes, clusterNode{addr: addr, client: node})
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no cluster primaries found")
+	}
+	if len(nodes) > maxClusterNodes {
+		return nil, fmt.Errorf("cluster has %d primaries; at most %d are supported", len(nodes), maxClusterNodes)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].addr < nodes[j].addr })
+
+	c.primaryCache.nodes = nodes
+	c.primaryCache.fetched = time.Now()
+	return nodes, nil
+}
+
+// splitClusterCursor separates a cluster SCAN cursor into primary index and node cursor
+func splitClusterCursor(cursor uint64) (int, uint64) {
+	return int(cursor & nodeIndexMask), cursor >> clusterNodeBits
+}
+
+// joinClusterCursor is the inverse of splitClusterCursor. It fails for node
+// cursors too large to shift, which Valkey doesn't produce in practice.
+func joinClusterCursor(node int, cursor uint64) (uint64, error) {
+	if cursor>>(64-clusterNodeBits) != 0 {
+		return 0, fmt.Errorf("node SCAN cursor %d too large to combine", cursor)
+	}
+	return cursor<<clusterNodeBits | uint64(node), nil
+}
+
+// clusterKeys runs one SCAN step against the primary the cursor points at,
+// moving on to the next primary when that node's scan completes. A page may
+// come back empty at a node boundary, as SCAN pages may anyway.
+func (c *Client) clusterKeys(ctx context.Context, pattern string, cursor uint64, count int64, scanType string) ([]string, uint64, error) {
+	nodes, err := c.primaries(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	index, nodeCursor := splitClusterCursor(cursor)
+	if index >= len(nodes) {
+		return nil, 0, fmt.Errorf("invalid cluster scan cursor")
+	}
+	node := nodes[index].client
+
+	cmd := node.B().Scan().Cursor(nodeCursor).Match(pattern).Count(count)
+	var result valkey.ValkeyResult
+	if scanType != "" {
+		result = node.Do(ctx, cmd.Type(scanType).Build())
+	} else {
+		result = node.Do(ctx, cmd.Build())
+	}
+	entry, err := result.AsScanEntry()
+	if err != nil {
+		return nil, 0, err
+	}
+	switch {
+	case entry.Cursor != 0:
+		next, err := joinClusterCursor(index, entry.Cursor)
+		return entry.Elements, next, err
+	case index+1 < len(nodes):
+		// Cursor 0 on a later node is just its index, never the terminal 0
+		next, err := joinClusterCursor(index+1, 0)
+		return entry.Elements, next, err
+	}
+	return entry.Elements, 0, nil
+}
+
+// onPrimaries runs cmd on every primary, for commands that only affect the node they run on
+func (c *Client) onPrimaries(ctx context.Context, build func(valkey.Client) valkey.Completed) ([]valkey.ValkeyResult, error) {
+	nodes, err := c.primaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]valkey.ValkeyResult, len(nodes))
+	for i, n := range nodes {
+		results[i] = n.client.Do(ctx, build(n.client))
+		if err := results[i].Error(); err != nil {
+			return nil, fmt.Errorf("%s: %w", n.addr, err)
+		}
+	}
+	return results, nil
+}
+
+// splitAddresses turns a comma-separated address list into seed addresses
+func splitAddresses(list string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(list, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
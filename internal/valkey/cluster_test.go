@@ -0,0 +1,40 @@
+package valkey
+
+import "testing"
+
+func TestClusterCursor(t *testing.T) {
+	tests := []struct {
+		node   int
+		cursor uint64
+	}{
+		{0, 17},
+		{1, 0},
+		{3, 123456789},
+		{maxClusterNodes - 1, 1 << 40},
+	}
+
+	for _, tt := range tests {
+		joined, err := joinClusterCursor(tt.node, tt.cursor)
+		if err != nil {
+			t.Fatalf("joinClusterCursor(%d, %d): %v", tt.node, tt.cursor, err)
+		}
+		if tt.node > 0 && joined == 0 {
+			t.Errorf("joinClusterCursor(%d, %d) = 0, which would end the scan", tt.node, tt.cursor)
+		}
+		node, cursor := splitClusterCursor(joined)
+		if node != tt.node || cursor != tt.cursor {
+			t.Errorf("split(join(%d, %d)) = (%d, %d)", tt.node, tt.cursor, node, cursor)
+		}
+	}
+
+	if _, err := joinClusterCursor(1, 1<<60); err == nil {
+		t.Error("expected an error for a node cursor that doesn't fit")
+	}
+}
+
+func TestSplitAddresses(t *testing.T) {
+	got := splitAddresses(" node1:7000, node2:7000,,")
+	if len(got) != 2 || got[0] != "node1:7000" || got[1] != "node2:7000" {
+		t.Errorf("splitAddresses() = %q", got)
+	}
+}
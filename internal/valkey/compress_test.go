@@ -119,3 +119,27 @@ func TestRoundTripJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestIsHyperLogLog(t *testing.T) {
+	tests := []struct {
+		name string
+		val  string
+		want bool
+	}{
+		{"empty", "", false},
+		{"header only", "HYLL", true},
+		{"full value", "HYLL\x01\x00\x00\x00", true},
+		{"too short", "HYL", false},
+		{"lowercase", "hyll", false},
+		{"not at start", "xHYLL", false},
+		{"plain text", "hello", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsHyperLogLog(tt.val); got != tt.want {
+				t.Errorf("IsHyperLogLog(%q) = %v, want %v", tt.val, got, tt.want)
+			}
+		})
+	}
+}
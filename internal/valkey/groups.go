@@ -0,0 +1,163 @@
+package valkey
+
+import (
+	"context"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// Stream consumer group operations
+
+// StreamGroup represents a consumer group on a stream (from XINFO GROUPS)
+type StreamGroup struct {
+	Name            string           `json:"name"`
+	Pending         int64            `json:"pending"`
+	LastDeliveredID string           `json:"lastDeliveredId"`
+	EntriesRead     *int64           `json:"entriesRead,omitempty"` // Valkey 7.0+
+	Lag             *int64           `json:"lag,omitempty"`         // Valkey 7.0+, nil when unknown
+	Consumers       []StreamConsumer `json:"consumers"`
+}
+
+// StreamConsumer represents a consumer in a group (from XINFO CONSUMERS)
+type StreamConsumer struct {
+	Name     string `json:"name"`
+	Pending  int64  `json:"pending"`
+	Idle     int64  `json:"idle"`               // ms since last attempted interaction
+	Inactive *int64 `json:"inactive,omitempty"` // ms since last successful interaction, Valkey 7.2+
+}
+
+// PendingSummary summarizes a group's pending entries list (from XPENDING)
+type PendingSummary struct {
+	Count     int64            `json:"count"`
+	MinID     string           `json:"minId,omitempty"`
+	MaxID     string           `json:"maxId,omitempty"`
+	Consumers map[string]int64 `json:"consumers"` // consumer name → pending count
+}
+
+// XInfoGroups returns the consumer groups of a stream (without their consumers)
+func (c *Client) XInfoGroups(ctx context.Context, key string) ([]StreamGroup, error) {
+	result, err := c.do(ctx, c.client.B().XinfoGroups().Key(key).Build()).ToArray()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]StreamGroup, 0, len(result))
+	for _, r := range result {
+		m, err := r.AsMap()
+		if err != nil {
+			return nil, err
+		}
+		g := StreamGroup{Consumers: []StreamConsumer{}}
+		g.Name, _ = stringField(m, "name")
+		g.Pending, _ = intField(m, "pending")
+		g.LastDeliveredID, _ = stringField(m, "last-delivered-id")
+		if v, ok := intField(m, "entries-read"); ok {
+			g.EntriesRead = &v
+		}
+		if v, ok := intField(m, "lag"); ok {
+			g.Lag = &v
+		}
+		groups = append(groups, g)
+	}
+	return groups, nil
+}
+
+// XInfoConsumers returns the consumers in a stream consumer group
+func (c *Client) XInfoConsumers(ctx context.Context, key, group string) ([]StreamConsumer, error) {
+	result, err := c.do(ctx, c.client.B().XinfoConsumers().Key(key).Group(group).Build()).ToArray()
+	if err != nil {
+		return nil, err
+	}
+
+	consumers := make([]StreamConsumer, 0, len(result))
+	for _, r := range result {
+		m, err := r.AsMap()
+		if err != nil {
+			return nil, err
+		}
+		var sc StreamConsumer
+		sc.Name, _ = stringField(m, "name")
+		sc.Pending, _ = intField(m, "pending")
+		sc.Idle, _ = intField(m, "idle")
+		if v, ok := intField(m, "inactive"); ok {
+			sc.Inactive = &v
+		}
+		consumers = append(consumers, sc)
+	}
+	return consumers, nil
+}
+
+// XPending returns the summary form of XPENDING for a consumer group
+func (c *Client) XPending(ctx context.Context, key, group string) (*PendingSummary, error) {
+	result, err := c.do(ctx, c.client.B().Xpending().Key(key).Group(group).Build()).ToArray()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &PendingSummary{Consumers: map[string]int64{}}
+	if len(result) != 4 {
+		return summary, nil
+	}
+
+	summary.Count, _ = result[0].AsInt64()
+	summary.MinID, _ = result[1].ToString()
+	summary.MaxID, _ = result[2].ToString()
+
+	// Per-consumer counts: [[name, count], ...] (nil when nothing is pending)
+	perConsumer, _ := result[3].ToArray()
+	for _, pc := range perConsumer {
+		pair, err := pc.ToArray()
+		if err != nil || len(pair) != 2 {
+			continue
+		}
+		name, _ := pair[0].ToString()
+		// Counts are returned as bulk strings
+		count, err := pair[1].AsInt64()
+		if err != nil {
+			continue
+		}
+		summary.Consumers[name] = count
+	}
+	return summary, nil
+}
+
+// stringField reads a string value from an XINFO reply map
+func stringField(m map[string]valkey.ValkeyMessage, name string) (string, bool) {
+	v, ok := m[name]
+	if !ok || v.IsNil() {
+		return "", false
+	}
+	s, err := v.ToString()
+	return s, err == nil
+}
+
+// intField reads an integer value from an XINFO reply map
+func intField(m map[string]valkey.ValkeyMessage, name string) (int64, bool) {
+	v, ok := m[name]
+	if !ok || v.IsNil() {
+		return 0, false
+	}
+	n, err := v.AsInt64()
+	return n, err == nil
+}
+
+// XGroupCreate creates a consumer group starting at id ("$" for new entries only, "0" for all).
+// With mkstream, the stream is created if it doesn't exist.
+func (c *Client) XGroupCreate(ctx context.Context, key, group, id string, mkstream bool) error {
+	cmd := c.client.B().XgroupCreate().Key(key).Group(group).Id(id)
+	if mkstream {
+		return c.do(ctx, cmd.Mkstream().Build()).Error()
+	}
+	return c.do(ctx, cmd.Build()).Error()
+}
+
+// XGroupDestroy deletes a consumer group, returning false if it didn't exist
+func (c *Client) XGroupDestroy(ctx context.Context, key, group string) (bool, error) {
+	result, err := c.do(ctx, c.client.B().XgroupDestroy().Key(key).Group(group).Build()).ToInt64()
+	return result == 1, err
+}
+
+// XAck acknowledges pending entries in a consumer group, returning how many were acknowledged
+func (c *Client) XAck(ctx context.Context, key, group string, ids ...string) (int64, error) {
+	return c.do(ctx, c.client.B().Xack().Key(key).Group(group).Id(ids...).Build()).ToInt64()
+}
@@ -0,0 +1,57 @@
+package valkey
+
+import (
+	"context"
+	"strings"
+)
+
+// InfoMap returns INFO parsed into section -> field -> value
+func (c *Client) InfoMap(ctx context.Context, section string) (map[string]map[string]string, error) {
+	info, err := c.Info(ctx, section)
+	if err != nil {
+		return nil, err
+	}
+	return ParseInfo(info), nil
+}
+
+// ServerVersion returns the server's version from INFO server
+func (c *Client) ServerVersion(ctx context.Context) (string, error) {
+	info, err := c.Info(ctx, "server")
+	if err != nil {
+		return "", err
+	}
+	// Valkey reports valkey_version; redis_version is kept for compatibility
+	if v := infoField(info, "valkey_version"); v != "" {
+		return v, nil
+	}
+	return infoField(info, "redis_version"), nil
+}
+
+// ParseInfo parses a raw INFO response. Section headers ("# Server") become
+// lowercase section names; fields before any header land in "default".
+func ParseInfo(info string) map[string]map[string]string {
+	sections := make(map[string]map[string]string)
+	current := "default"
+
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "#"); ok {
+			current = strings.ToLower(strings.TrimSpace(name))
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if sections[current] == nil {
+			sections[current] = make(map[string]string)
+		}
+		sections[current][key] = value
+	}
+
+	return sections
+}
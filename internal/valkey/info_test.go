@@ -0,0 +1,33 @@
+package valkey
+
+import (
+	"testing"
+)
+
+func TestParseInfo(t *testing.T) {
+	raw := "# Server\r\nvalkey_version:8.0.1\r\nuptime_in_seconds:42\r\n\r\n" +
+		"# Keyspace\r\ndb0:keys=10,expires=2,avg_ttl=0\r\n"
+
+	sections := ParseInfo(raw)
+
+	if got := sections["server"]["valkey_version"]; got != "8.0.1" {
+		t.Errorf("server.valkey_version = %q, want %q", got, "8.0.1")
+	}
+	if got := sections["server"]["uptime_in_seconds"]; got != "42" {
+		t.Errorf("server.uptime_in_seconds = %q, want %q", got, "42")
+	}
+	// Values may themselves contain colons or commas; only the first colon splits
+	if got := sections["keyspace"]["db0"]; got != "keys=10,expires=2,avg_ttl=0" {
+		t.Errorf("keyspace.db0 = %q", got)
+	}
+	if len(sections) != 2 {
+		t.Errorf("expected 2 sections, got %d", len(sections))
+	}
+}
+
+func TestParseInfoNoHeader(t *testing.T) {
+	sections := ParseInfo("used_memory:1024\r\n")
+	if got := sections["default"]["used_memory"]; got != "1024" {
+		t.Errorf("default.used_memory = %q, want %q", got, "1024")
+	}
+}
@@ -0,0 +1,101 @@
+package valkey
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Monitor runs MONITOR on a dedicated connection and calls fn with each command line
+// the server reports (e.g. `1700000000.123456 [0 127.0.0.1:5000] "SET" "k" "v"`) until
+// ctx is cancelled or the connection fails. valkey-go can't consume a MONITOR stream,
+// so this speaks RESP directly.
+func (c *Client) Monitor(ctx context.Context, fn func(line string)) error {
+	addr := c.Primary()
+	if addr == "" {
+		return fmt.Errorf("no server address")
+	}
+
+	dialer := c.opts.Dialer
+	if dialer.Timeout == 0 {
+		dialer.Timeout = 5 * time.Second
+	}
+	var conn net.Conn
+	var err error
+	if c.opts.TLSConfig != nil {
+		conn, err = tls.DialWithDialer(&dialer, "tcp", addr, c.opts.TLSConfig)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Unblock the reader when the caller is done
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	r := bufio.NewReader(conn)
+
+	if c.opts.Password != "" {
+		args := []string{"AUTH", c.opts.Password}
+		if c.opts.Username != "" {
+			args = []string{"AUTH", c.opts.Username, c.opts.Password}
+		}
+		if err := roundTrip(conn, r, args...); err != nil {
+			return err
+		}
+	}
+	if err := roundTrip(conn, r, "MONITOR"); err != nil {
+		return err
+	}
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if msg, ok := strings.CutPrefix(line, "+"); ok {
+			fn(msg)
+		} else if msg, ok := strings.CutPrefix(line, "-"); ok {
+			return fmt.Errorf("%s", msg)
+		}
+	}
+}
+
+// roundTrip sends a command and expects a simple-string reply
+func roundTrip(w io.Writer, r *bufio.Reader, args ...string) error {
+	if _, err := io.WriteString(w, encodeCommand(args)); err != nil {
+		return err
+	}
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if msg, ok := strings.CutPrefix(line, "-"); ok {
+		return fmt.Errorf("%s", msg)
+	}
+	return nil
+}
+
+// encodeCommand encodes args as a RESP array of bulk strings
+func encodeCommand(args []string) string {
+	var b strings.Builder
+	b.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, a := range args {
+		b.WriteString("$" + strconv.Itoa(len(a)) + "\r\n" + a + "\r\n")
+	}
+	return b.String()
+}
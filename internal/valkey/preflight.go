@@ -0,0 +1,88 @@
+package valkey
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// preflightKey is written and deleted to probe whether the connection can write
+const preflightKey = "__kvweb:preflight"
+
+// preflightProbeKey places the write probe under the configured prefix, so an
+// ACL user limited to that prefix isn't reported as unable to write
+func (c *Client) preflightProbeKey() string {
+	return c.cfg.Prefix + preflightKey
+}
+
+// PreflightReport summarizes server readiness checks run at startup
+type PreflightReport struct {
+	ServerVersion string
+	Role          string // "master" or "slave" from INFO replication
+	DBSize        int64
+	Notifications string // notify-keyspace-events value, empty = disabled
+	WriteChecked  bool   // false when the probe was skipped (read-only mode)
+	Writable      bool
+	ScriptsLoaded bool
+	Errors        map[string]string // check name -> error message
+}
+
+// Preflight runs a set of quick diagnostic checks. Individual check failures are
+// recorded in Errors rather than returned, so one failure doesn't hide the others.
+// The write probe is skipped when probeWrite is false.
+func (c *Client) Preflight(ctx context.Context, probeWrite bool) *PreflightReport {
+	report := &PreflightReport{Errors: make(map[string]string)}
+
+	if version, err := c.ServerVersion(ctx); err != nil {
+		report.Errors["version"] = err.Error()
+	} else {
+		report.ServerVersion = version
+	}
+
+	if info, err := c.Info(ctx, "replication"); err != nil {
+		report.Errors["role"] = err.Error()
+	} else {
+		report.Role = infoField(info, "role")
+	}
+
+	if size, err := c.DBSize(ctx); err != nil {
+		report.Errors["dbsize"] = err.Error()
+	} else {
+		report.DBSize = size
+	}
+
+	if events, err := c.GetNotifyKeyspaceEvents(ctx); err != nil {
+		report.Errors["notifications"] = err.Error()
+	} else {
+		report.Notifications = events
+	}
+
+	if probeWrite {
+		report.WriteChecked = true
+		probe := c.preflightProbeKey()
+		if err := c.Set(ctx, probe, "1", time.Second); err != nil {
+			report.Errors["write"] = err.Error()
+		} else {
+			report.Writable = true
+			_, _ = c.Del(ctx, probe)
+		}
+	}
+
+	if err := LoadAllScripts(ctx, c); err != nil {
+		report.Errors["scripts"] = err.Error()
+	} else {
+		report.ScriptsLoaded = true
+	}
+
+	return report
+}
+
+// infoField returns the value of name from an INFO response, or "" if absent
+func infoField(info, name string) string {
+	for _, line := range strings.Split(info, "\r\n") {
+		if value, ok := strings.CutPrefix(line, name+":"); ok {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,19 @@
+package valkey
+
+import (
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+func TestPreflightProbeKey(t *testing.T) {
+	c := &Client{cfg: &config.Config{}}
+	if got := c.preflightProbeKey(); got != "__kvweb:preflight" {
+		t.Errorf("probe key without prefix = %q", got)
+	}
+
+	c.cfg.Prefix = "app:"
+	if got := c.preflightProbeKey(); got != "app:__kvweb:preflight" {
+		t.Errorf("probe key with prefix = %q, want it under app:", got)
+	}
+}
@@ -0,0 +1,104 @@
+package valkey
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReplicaInfo describes a replica connected to a primary
+type ReplicaInfo struct {
+	Addr   string `json:"addr"`
+	State  string `json:"state"`
+	Offset int64  `json:"offset"`
+	Lag    int64  `json:"lag"` // seconds since the replica last acknowledged
+}
+
+// ReplicationInfo is the structured form of INFO replication
+type ReplicationInfo struct {
+	Role              string        `json:"role"` // master or slave
+	ConnectedReplicas int64         `json:"connectedReplicas"`
+	Replicas          []ReplicaInfo `json:"replicas"`
+	ReplOffset        int64         `json:"replOffset"`
+
+	// Set when this server is a replica
+	MasterHost       string `json:"masterHost,omitempty"`
+	MasterPort       int64  `json:"masterPort,omitempty"`
+	MasterLinkStatus string `json:"masterLinkStatus,omitempty"` // up or down
+	MasterLastIO     int64  `json:"masterLastIOSeconds,omitempty"`
+	SyncInProgress   bool   `json:"syncInProgress,omitempty"`
+}
+
+// Replication returns the parsed INFO replication section
+func (c *Client) Replication(ctx context.Context) (*ReplicationInfo, error) {
+	info, err := c.InfoMap(ctx, "replication")
+	if err != nil {
+		return nil, err
+	}
+	return parseReplication(info["replication"]), nil
+}
+
+// Wait blocks until numReplicas have acknowledged this connection's writes or
+// timeout passes, and returns how many acknowledged
+func (c *Client) Wait(ctx context.Context, numReplicas int64, timeout time.Duration) (int64, error) {
+	return c.do(ctx, c.client.B().Wait().Numreplicas(numReplicas).Timeout(timeout.Milliseconds()).Build()).ToInt64()
+}
+
+func parseReplication(fields map[string]string) *ReplicationInfo {
+	atoi := func(s string) int64 {
+		n, _ := strconv.ParseInt(s, 10, 64)
+		return n
+	}
+
+	ri := &ReplicationInfo{
+		Role:              fields["role"],
+		ConnectedReplicas: atoi(fields["connected_slaves"]),
+		ReplOffset:        atoi(fields["master_repl_offset"]),
+		MasterHost:        fields["master_host"],
+		MasterPort:        atoi(fields["master_port"]),
+		MasterLinkStatus:  fields["master_link_status"],
+		MasterLastIO:      atoi(fields["master_last_io_seconds_ago"]),
+		SyncInProgress:    fields["master_sync_in_progress"] == "1",
+		Replicas:          []ReplicaInfo{},
+	}
+	if ri.Role == "slave" {
+		ri.ReplOffset = atoi(fields["slave_repl_offset"])
+	}
+
+	// Replicas are listed as slave0:ip=10.0.0.2,port=6379,state=online,offset=123,lag=0
+	var names []string
+	for name := range fields {
+		if _, err := strconv.Atoi(strings.TrimPrefix(name, "slave")); err == nil && strings.HasPrefix(name, "slave") {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return atoi(names[i][len("slave"):]) < atoi(names[j][len("slave"):])
+	})
+
+	for _, name := range names {
+		var rep ReplicaInfo
+		var ip, port string
+		for _, pair := range strings.Split(fields[name], ",") {
+			k, v, _ := strings.Cut(pair, "=")
+			switch k {
+			case "ip":
+				ip = v
+			case "port":
+				port = v
+			case "state":
+				rep.State = v
+			case "offset":
+				rep.Offset = atoi(v)
+			case "lag":
+				rep.Lag = atoi(v)
+			}
+		}
+		rep.Addr = ip + ":" + port
+		ri.Replicas = append(ri.Replicas, rep)
+	}
+
+	return ri
+}
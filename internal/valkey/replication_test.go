@@ -0,0 +1,45 @@
+package valkey
+
+import (
+	"testing"
+)
+
+func TestParseReplicationPrimary(t *testing.T) {
+	info := ParseInfo("# Replication\r\nrole:master\r\nconnected_slaves:2\r\n" +
+		"slave1:ip=10.0.0.3,port=6380,state=wait_bgsave,offset=0,lag=4\r\n" +
+		"slave0:ip=10.0.0.2,port=6379,state=online,offset=1500,lag=0\r\n" +
+		"master_repl_offset:1520\r\n")
+
+	ri := parseReplication(info["replication"])
+	if ri.Role != "master" || ri.ConnectedReplicas != 2 || ri.ReplOffset != 1520 {
+		t.Errorf("unexpected summary: %+v", ri)
+	}
+	if len(ri.Replicas) != 2 {
+		t.Fatalf("expected 2 replicas, got %d", len(ri.Replicas))
+	}
+	want := ReplicaInfo{Addr: "10.0.0.2:6379", State: "online", Offset: 1500, Lag: 0}
+	if ri.Replicas[0] != want {
+		t.Errorf("Replicas[0] = %+v, want %+v", ri.Replicas[0], want)
+	}
+	if ri.Replicas[1].State != "wait_bgsave" || ri.Replicas[1].Lag != 4 {
+		t.Errorf("Replicas[1] = %+v", ri.Replicas[1])
+	}
+}
+
+func TestParseReplicationReplica(t *testing.T) {
+	info := ParseInfo("# Replication\r\nrole:slave\r\nmaster_host:10.0.0.1\r\nmaster_port:6379\r\n" +
+		"master_link_status:down\r\nmaster_last_io_seconds_ago:-1\r\nmaster_sync_in_progress:1\r\n" +
+		"slave_repl_offset:900\r\nslave_priority:100\r\nconnected_slaves:0\r\nmaster_repl_offset:900\r\n")
+
+	ri := parseReplication(info["replication"])
+	if ri.Role != "slave" || ri.MasterHost != "10.0.0.1" || ri.MasterPort != 6379 {
+		t.Errorf("unexpected primary address: %+v", ri)
+	}
+	if ri.MasterLinkStatus != "down" || !ri.SyncInProgress || ri.ReplOffset != 900 {
+		t.Errorf("unexpected link state: %+v", ri)
+	}
+	// slave_priority and slave_repl_offset are not replica entries
+	if len(ri.Replicas) != 0 {
+		t.Errorf("expected no replicas, got %+v", ri.Replicas)
+	}
+}
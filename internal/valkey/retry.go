@@ -0,0 +1,69 @@
+package valkey
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+const (
+	retryBaseDelay = 100 * time.Millisecond
+	retryMaxDelay  = time.Second
+)
+
+// do runs cmd. Read-only commands that fail with a transient error (see
+// retryable) are retried up to cfg.Retries times with exponential backoff,
+// giving up early rather than sleeping past the context's deadline. Writes
+// are never retried: a write whose reply was lost may already have applied.
+func (c *Client) do(ctx context.Context, cmd valkey.Completed) valkey.ValkeyResult {
+	if c.cfg.Retries <= 0 || !cmd.IsReadOnly() || cmd.IsBlock() {
+		return c.client.Do(ctx, cmd)
+	}
+
+	// Pinned commands aren't recycled after Do, so they can be sent again
+	cmd = cmd.Pin()
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		result := c.client.Do(ctx, cmd)
+		if attempt == c.cfg.Retries || !retryable(result.Error()) {
+			return result
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return result
+		}
+		select {
+		case <-ctx.Done():
+			return result
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, retryMaxDelay)
+	}
+}
+
+// retryableCodes are server errors that clear up on their own: a server still
+// loading its dataset, or a failover or resharding in progress
+var retryableCodes = map[string]bool{
+	"LOADING":     true,
+	"MASTERDOWN":  true,
+	"TRYAGAIN":    true,
+	"CLUSTERDOWN": true,
+}
+
+// retryable reports whether err is transient: one of retryableCodes, or a
+// dropped or refused connection. Context cancellation is never retried.
+func retryable(err error) bool {
+	if err == nil || IsNil(err) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	code, _, _ := strings.Cut(err.Error(), " ")
+	if retryableCodes[code] {
+		return true
+	}
+	var netErr net.Error
+	return errors.Is(err, io.EOF) || errors.As(err, &netErr)
+}
@@ -0,0 +1,38 @@
+package valkey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"loading", errors.New("LOADING Valkey is loading the dataset in memory"), true},
+		{"failover", errors.New("MASTERDOWN Link with MASTER is down and replica-serve-stale-data is set to 'no'."), true},
+		{"resharding", errors.New("TRYAGAIN Multiple keys request during rehashing of slot"), true},
+		{"dropped connection", fmt.Errorf("read: %w", io.EOF), true},
+		{"refused connection", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"wrong type", errors.New("WRONGTYPE Operation against a key holding the wrong kind of value"), false},
+		{"syntax", errors.New("ERR syntax error"), false},
+		{"out of memory", errors.New("OOM command not allowed when used memory > 'maxmemory'."), false},
+		{"deadline", context.DeadlineExceeded, false},
+		{"canceled", context.Canceled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.err); got != tt.want {
+				t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -25,12 +25,12 @@ func NewScript(script string) *Script {
 // Eval executes the script with the given keys and args
 // Uses EVALSHA for efficiency, falls back to EVAL if script not cached
 func (s *Script) Eval(ctx context.Context, c *Client, keys []string, args []string) (any, error) {
-	// Build EVALSHA command with all keys and args
-	allArgs := []string{"EVALSHA", s.sha1, fmt.Sprintf("%d", len(keys))}
-	allArgs = append(allArgs, keys...)
-	allArgs = append(allArgs, args...)
-
-	result := c.client.Do(ctx, c.client.B().Arbitrary(allArgs...).Build())
+	if err := c.checkSameSlot(keys...); err != nil {
+		return nil, err
+	}
+	// Keys are passed separately so a cluster client routes by their slot
+	cmd := c.client.B().Arbitrary("EVALSHA", s.sha1, fmt.Sprintf("%d", len(keys))).Keys(keys...).Args(args...)
+	result := c.do(ctx, cmd.Build())
 	err := result.Error()
 
 	// If script not found, load it with EVAL
@@ -47,12 +47,8 @@ func (s *Script) Eval(ctx context.Context, c *Client, keys []string, args []stri
 
 // evalScript executes the script using EVAL (loads and runs in one command)
 func (s *Script) evalScript(ctx context.Context, c *Client, keys []string, args []string) (any, error) {
-	// Build EVAL command with all keys and args
-	allArgs := []string{"EVAL", s.script, fmt.Sprintf("%d", len(keys))}
-	allArgs = append(allArgs, keys...)
-	allArgs = append(allArgs, args...)
-
-	result := c.client.Do(ctx, c.client.B().Arbitrary(allArgs...).Build())
+	cmd := c.client.B().Arbitrary("EVAL", s.script, fmt.Sprintf("%d", len(keys))).Keys(keys...).Args(args...)
+	result := c.do(ctx, cmd.Build())
 	if err := result.Error(); err != nil {
 		return nil, err
 	}
@@ -63,7 +59,7 @@ func (s *Script) evalScript(ctx context.Context, c *Client, keys []string, args
 // Load preloads the script on the server using SCRIPT LOAD
 // This is optional but can improve performance if the script will be used many times
 func (s *Script) Load(ctx context.Context, c *Client) error {
-	sha, err := c.client.Do(ctx, c.client.B().ScriptLoad().Script(s.script).Build()).ToString()
+	sha, err := c.do(ctx, c.client.B().ScriptLoad().Script(s.script).Build()).ToString()
 	if err != nil {
 		return err
 	}
@@ -89,9 +85,9 @@ func isNoScriptError(err error) bool {
 var (
 	// scriptListRemoveByIndex atomically removes a list element at a specific index
 	// KEYS[1] = key name
-	// ARGV[1] = index to remove
+	// ARGV[1] = index to remove (negative counts from the tail, -1 = last)
 	// ARGV[2] = tombstone suffix (for uniqueness)
-	// Returns: 1 on success, 0 if key doesn't exist or wrong type
+	// Returns: 1 on success, 0 if key doesn't exist or wrong type, -1 if index is out of range
 	scriptListRemoveByIndex = NewScript(`
 		local key = KEYS[1]
 		local index = tonumber(ARGV[1])
@@ -105,6 +101,14 @@ var (
 			return 0
 		end
 
+		local len = redis.call('LLEN', key)
+		if index < 0 then
+			index = len + index
+		end
+		if index < 0 or index >= len then
+			return -1
+		end
+
 		-- Set tombstone at index, then remove it
 		local ok, err = pcall(function()
 			redis.call('LSET', key, index, tombstone)
@@ -254,6 +258,59 @@ var (
 
 		return {ktype, size, ttl}
 	`)
+
+	// scriptSoftDelete atomically moves a key into a trash backup and deletes it
+	// KEYS[1] = key name
+	// KEYS[2] = trash key name
+	// ARGV[1] = retention in seconds
+	// Returns: 1 if the key was moved, 0 if it doesn't exist
+	scriptSoftDelete = NewScript(`
+		local key = KEYS[1]
+		local trash = KEYS[2]
+
+		local payload = redis.call('DUMP', key)
+		if not payload then
+			return 0
+		end
+
+		-- Keep the original TTL so restore can reapply it
+		local pttl = redis.call('PTTL', key)
+
+		redis.call('DEL', trash)
+		redis.call('HSET', trash, 'payload', payload, 'pttl', pttl)
+		redis.call('EXPIRE', trash, tonumber(ARGV[1]))
+		redis.call('DEL', key)
+
+		return 1
+	`)
+
+	// scriptRestoreFromTrash atomically restores a key from its trash backup
+	// KEYS[1] = key name
+	// KEYS[2] = trash key name
+	// Returns: 1 on success, error if there is no backup or the key already exists
+	scriptRestoreFromTrash = NewScript(`
+		local key = KEYS[1]
+		local trash = KEYS[2]
+
+		local payload = redis.call('HGET', trash, 'payload')
+		if not payload then
+			return redis.error_reply('Nothing to restore')
+		end
+
+		if redis.call('EXISTS', key) == 1 then
+			return redis.error_reply('Key already exists')
+		end
+
+		local pttl = tonumber(redis.call('HGET', trash, 'pttl'))
+		if pttl < 0 then
+			pttl = 0
+		end
+
+		redis.call('RESTORE', key, pttl, payload)
+		redis.call('DEL', trash)
+
+		return 1
+	`)
 )
 
 // LoadAllScripts preloads all built-in scripts on the server
@@ -266,6 +323,8 @@ func LoadAllScripts(ctx context.Context, c *Client) error {
 		scriptZSetRename,
 		scriptHashRename,
 		scriptGetKeyMetadata,
+		scriptSoftDelete,
+		scriptRestoreFromTrash,
 	}
 
 	for _, script := range scripts {
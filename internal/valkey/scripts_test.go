@@ -2,6 +2,8 @@ package valkey
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -64,6 +66,53 @@ func TestLuaScripts(t *testing.T) {
 		}
 	})
 
+	t.Run("ListRemoveByNegativeIndex", func(t *testing.T) {
+		key := "test:list"
+		_, _ = client.Del(ctx, key)
+		if err := client.RPush(ctx, key, "a", "b", "c"); err != nil {
+			t.Fatalf("RPush failed: %v", err)
+		}
+
+		// -1 removes the tail
+		if err := client.LRemByIndex(ctx, key, -1); err != nil {
+			t.Fatalf("LRemByIndex(-1) failed: %v", err)
+		}
+		items, err := client.LRange(ctx, key, 0, -1)
+		if err != nil {
+			t.Fatalf("LRange failed: %v", err)
+		}
+		if len(items) != 2 || items[0] != "a" || items[1] != "b" {
+			t.Errorf("expected [a b], got %v", items)
+		}
+
+		for _, index := range []int64{2, -3, 100} {
+			if err := client.LRemByIndex(ctx, key, index); !errors.Is(err, ErrIndexOutOfRange) {
+				t.Errorf("LRemByIndex(%d) error = %v, want ErrIndexOutOfRange", index, err)
+			}
+		}
+	})
+
+	t.Run("ListSetIndex", func(t *testing.T) {
+		key := "test:list"
+		_, _ = client.Del(ctx, key)
+		if err := client.RPush(ctx, key, "a", "b"); err != nil {
+			t.Fatalf("RPush failed: %v", err)
+		}
+
+		if err := client.LSet(ctx, key, -1, "z"); err != nil {
+			t.Fatalf("LSet(-1) failed: %v", err)
+		}
+		items, _ := client.LRange(ctx, key, 0, -1)
+		if len(items) != 2 || items[1] != "z" {
+			t.Errorf("expected [a z], got %v", items)
+		}
+
+		// The API maps this server error to 400 by its text
+		if err := client.LSet(ctx, key, 5, "x"); err == nil || !strings.Contains(err.Error(), "index out of range") {
+			t.Errorf("LSet(5) error = %v, want index out of range", err)
+		}
+	})
+
 	t.Run("SetAddIfNotExists", func(t *testing.T) {
 		key := "test:set"
 		_, _ = client.Del(ctx, key)
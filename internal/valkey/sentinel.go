@@ -0,0 +1,50 @@
+package valkey
+
+import (
+	"context"
+	"time"
+)
+
+// failoverPollInterval is how often a keyspace subscription checks whether
+// Sentinel moved the primary
+const failoverPollInterval = time.Second
+
+// IsSentinel reports whether the primary is discovered through Sentinel
+func (c *Client) IsSentinel() bool {
+	return c.sentinel
+}
+
+// Primary returns the address of the server commands go to. Through Sentinel
+// that is the current primary, which changes after a failover.
+func (c *Client) Primary() string {
+	if c.sentinel {
+		for addr := range c.client.Nodes() {
+			return addr
+		}
+		return ""
+	}
+	if len(c.opts.InitAddress) == 0 {
+		return ""
+	}
+	return c.opts.InitAddress[0]
+}
+
+// cancelOnFailover calls cancel once the primary changes or ctx is done. The
+// driver would quietly move a subscription to the new primary, but that node
+// may not have keyspace notifications enabled, so the caller should start over.
+func (c *Client) cancelOnFailover(ctx context.Context, cancel context.CancelFunc) {
+	primary := c.Primary()
+	ticker := time.NewTicker(failoverPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if current := c.Primary(); current != primary {
+				cancel()
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
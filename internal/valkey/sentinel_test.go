@@ -0,0 +1,19 @@
+package valkey
+
+import (
+	"testing"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+func TestPrimaryWithoutSentinel(t *testing.T) {
+	c := &Client{opts: valkey.ClientOption{InitAddress: []string{"db1:6379", "db2:6379"}}}
+	if got := c.Primary(); got != "db1:6379" {
+		t.Errorf("Primary() = %q, want the first address", got)
+	}
+
+	c = &Client{}
+	if got := c.Primary(); got != "" {
+		t.Errorf("Primary() = %q, want empty with no addresses", got)
+	}
+}
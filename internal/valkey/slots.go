@@ -0,0 +1,105 @@
+package valkey
+
+import (
+	"context"
+	"errors"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// clusterSlots is the number of hash slots a cluster splits keys over
+const clusterSlots = 16384
+
+// ErrCrossSlot is returned in cluster mode for a multi-key command whose keys
+// live in different hash slots. valkey-go panics while building such commands,
+// so they're checked first. It reads like the server's own CROSSSLOT reply.
+var ErrCrossSlot = errors.New("CROSSSLOT Keys in request don't hash to the same slot")
+
+// KeySlot returns the cluster hash slot of key: CRC16 of the key, or of its
+// hash tag (the text between the first "{" and the next "}", if not empty)
+func KeySlot(key string) uint16 {
+	if start := indexByte(key, '{'); start >= 0 {
+		if end := indexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return crc16(key) % clusterSlots
+}
+
+func indexByte(s string, b byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == b {
+			return i
+		}
+	}
+	return -1
+}
+
+// crc16 is CRC-16/XMODEM, the checksum cluster slots are derived from
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// checkSameSlot returns ErrCrossSlot in cluster mode if keys span hash slots
+func (c *Client) checkSameSlot(keys ...string) error {
+	if !c.cluster || len(keys) < 2 {
+		return nil
+	}
+	slot := KeySlot(keys[0])
+	for _, key := range keys[1:] {
+		if KeySlot(key) != slot {
+			return ErrCrossSlot
+		}
+	}
+	return nil
+}
+
+// groupBySlot splits keys into runs that share a hash slot, keeping first-seen order
+func groupBySlot(keys []string) [][]string {
+	index := make(map[uint16]int)
+	var groups [][]string
+	for _, key := range keys {
+		slot := KeySlot(key)
+		i, ok := index[slot]
+		if !ok {
+			i = len(groups)
+			index[slot] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], key)
+	}
+	return groups
+}
+
+// sumBySlot runs a counting multi-key command (DEL, EXISTS) once per hash slot
+// and adds up the replies, so it works on keys anywhere in a cluster
+func (c *Client) sumBySlot(ctx context.Context, keys []string, build func(keys []string) valkey.Completed) (int64, error) {
+	if !c.cluster {
+		return c.do(ctx, build(keys)).ToInt64()
+	}
+	groups := groupBySlot(keys)
+	cmds := make(valkey.Commands, len(groups))
+	for i, group := range groups {
+		cmds[i] = build(group)
+	}
+	var total int64
+	for _, result := range c.client.DoMulti(ctx, cmds...) {
+		n, err := result.ToInt64()
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,83 @@
+package valkey
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestKeySlot(t *testing.T) {
+	tests := []struct {
+		key  string
+		want uint16
+	}{
+		{"123456789", 0x31c3}, // CRC-16/XMODEM check value
+		{"foo", 12182},
+		{"bar", 5061},
+		{"{foo}.bar", 12182},
+		{"user:{foo}:profile", 12182},
+		{"{}foo", KeySlot("{}foo")}, // empty tag hashes the whole key
+	}
+
+	for _, tt := range tests {
+		if got := KeySlot(tt.key); got != tt.want {
+			t.Errorf("KeySlot(%q) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+	if KeySlot("{}foo") == KeySlot("foo") {
+		t.Error("an empty hash tag should not be used")
+	}
+}
+
+func TestGroupBySlot(t *testing.T) {
+	groups := groupBySlot([]string{"foo", "bar", "{foo}:x", "{bar}:y"})
+	if len(groups) != 2 || len(groups[0]) != 2 || groups[0][1] != "{foo}:x" || groups[1][1] != "{bar}:y" {
+		t.Errorf("groupBySlot() = %q", groups)
+	}
+}
+
+// Multi-key commands on keys in different slots must fail with ErrCrossSlot
+// before reaching valkey-go, whose command builder panics on them
+func TestCrossSlotRejected(t *testing.T) {
+	c := &Client{cluster: true}
+	ctx := context.Background()
+
+	checks := map[string]func() error{
+		"rename": func() error { return c.Rename(ctx, "foo", "bar") },
+		"setop": func() error {
+			_, err := c.SetOp(ctx, "inter", "foo", "bar")
+			return err
+		},
+		"setopstore": func() error {
+			_, err := c.SetOpStore(ctx, "union", "{foo}:dest", "foo", "bar")
+			return err
+		},
+		"pfmerge": func() error {
+			_, err := c.PFMerge(ctx, "foo", "bar")
+			return err
+		},
+		"transaction": func() error {
+			_, err := c.Transaction(ctx, []TxOp{{Op: "set", Key: "foo"}, {Op: "del", Key: "bar"}})
+			return err
+		},
+		"eval": func() error {
+			_, err := scriptSoftDelete.Eval(ctx, c, []string{"foo", "bar"}, nil)
+			return err
+		},
+	}
+
+	for name, check := range checks {
+		t.Run(name, func(t *testing.T) {
+			if err := check(); !errors.Is(err, ErrCrossSlot) {
+				t.Errorf("err = %v, want ErrCrossSlot", err)
+			}
+		})
+	}
+
+	if err := c.checkSameSlot("{foo}:a", "{foo}:b"); err != nil {
+		t.Errorf("keys sharing a hash tag: %v", err)
+	}
+	if err := (&Client{}).checkSameSlot("foo", "bar"); err != nil {
+		t.Errorf("standalone server: %v", err)
+	}
+}
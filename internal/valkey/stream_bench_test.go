@@ -0,0 +1,68 @@
+package valkey
+
+import (
+	"context"
+	"testing"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+// BenchmarkStreamDeepPage compares reaching a deep page of a 50k-entry stream
+// by skipping over the earlier entries (the old page-number approach) with
+// continuing from the previous page's last ID. This requires a running
+// Valkey/Redis instance.
+func BenchmarkStreamDeepPage(b *testing.B) {
+	cfg := &config.Config{
+		ValkeyURL: "localhost:6379",
+		ValkeyDB:  15, // Use DB 15 for testing
+	}
+
+	client, err := New(cfg)
+	if err != nil {
+		b.Skip("Valkey not available:", err)
+	}
+	defer client.Close()
+
+	const entries = 50000
+	const pageSize = 100
+	const page = 400 // entries 39,900-39,999
+
+	ctx := context.Background()
+	key := "bench:stream:pages"
+	_, _ = client.Del(ctx, key)
+	defer func() {
+		_, _ = client.Del(ctx, key)
+	}()
+	for range entries {
+		if _, err := client.XAddMulti(ctx, key, map[string]string{"f": "v"}, XAddOptions{}); err != nil {
+			b.Fatalf("XAdd failed: %v", err)
+		}
+	}
+
+	skip := int64((page - 1) * pageSize)
+	before, err := client.XRange(ctx, key, "-", "+", skip)
+	if err != nil {
+		b.Fatalf("XRange failed: %v", err)
+	}
+	cursor := before[len(before)-1].ID
+
+	b.Run("skip", func(b *testing.B) {
+		for range b.N {
+			skipped, err := client.XRange(ctx, key, "-", "+", skip)
+			if err != nil {
+				b.Fatalf("XRange failed: %v", err)
+			}
+			if _, _, err := client.XRangePage(ctx, key, skipped[len(skipped)-1].ID, pageSize); err != nil {
+				b.Fatalf("XRangePage failed: %v", err)
+			}
+		}
+	})
+
+	b.Run("cursor", func(b *testing.B) {
+		for range b.N {
+			if _, _, err := client.XRangePage(ctx, key, cursor, pageSize); err != nil {
+				b.Fatalf("XRangePage failed: %v", err)
+			}
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/valkey-io/valkey-go"
 )
@@ -16,35 +17,115 @@ type KeyEvent struct {
 
 // SubscribeKeyspace subscribes to keyspace notifications for a specific database.
 // Returns a channel that emits KeyEvent for each key operation.
-// The channel is closed when the context is cancelled or an error occurs.
+// The channel is closed when the context is cancelled or an error occurs, and
+// through Sentinel also when a failover moves the primary.
 func (c *Client) SubscribeKeyspace(ctx context.Context, db int) (<-chan KeyEvent, error) {
 	events := make(chan KeyEvent, 100)
 
+	var cancel context.CancelFunc = func() {}
+	if c.sentinel {
+		ctx, cancel = context.WithCancel(ctx)
+		go c.cancelOnFailover(ctx, cancel)
+	}
+
 	// Subscribe to __keyspace@{db}__:* pattern
 	pattern := fmt.Sprintf("__keyspace@%d__:*", db)
 	prefix := fmt.Sprintf("__keyspace@%d__:", db)
 
+	// Each cluster node only notifies about its own keys, so listen on every primary
+	receivers := []valkey.Client{c.client}
+	if c.cluster {
+		nodes, err := c.primaries(ctx)
+		if err != nil {
+			return nil, err
+		}
+		receivers = receivers[:0]
+		for _, n := range nodes {
+			receivers = append(receivers, n.client)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, receiver := range receivers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := receiver.Receive(ctx, receiver.B().Psubscribe().Pattern(pattern).Build(),
+				func(msg valkey.PubSubMessage) {
+					// Channel format: __keyspace@0__:mykey
+					// Message: set, del, expire, expired, rename_from, rename_to, etc.
+					key := strings.TrimPrefix(msg.Channel, prefix)
+					select {
+					case events <- KeyEvent{
+						Operation: msg.Message,
+						Key:       key,
+					}:
+					case <-ctx.Done():
+						return
+					}
+				})
+			// On error, channel closes once every receiver stops; err is
+			// intentionally ignored when context is cancelled (normal shutdown)
+			_ = err
+		}()
+	}
 	go func() {
-		defer close(events)
-
-		err := c.client.Receive(ctx, c.client.B().Psubscribe().Pattern(pattern).Build(),
-			func(msg valkey.PubSubMessage) {
-				// Channel format: __keyspace@0__:mykey
-				// Message: set, del, expire, expired, rename_from, rename_to, etc.
-				key := strings.TrimPrefix(msg.Channel, prefix)
-				select {
-				case events <- KeyEvent{
-					Operation: msg.Message,
-					Key:       key,
-				}:
-				case <-ctx.Done():
-					return
-				}
-			})
-		// On error, channel closes via defer; err is intentionally ignored
-		// when context is cancelled (normal shutdown)
-		_ = err
+		wg.Wait()
+		cancel()
+		close(events)
 	}()
 
 	return events, nil
 }
+
+// ChannelMessage is a message published on a user-chosen pub/sub channel
+type ChannelMessage struct {
+	Channel string
+	Pattern string // set for pattern subscriptions
+	Message string
+}
+
+// Subscribe listens on channel, or with pattern on every channel matching it, until
+// ctx is cancelled. The returned channel is closed when the subscription ends.
+func (c *Client) Subscribe(ctx context.Context, channel string, pattern bool) <-chan ChannelMessage {
+	messages := make(chan ChannelMessage, 100)
+
+	cmd := c.client.B().Subscribe().Channel(channel).Build()
+	if pattern {
+		cmd = c.client.B().Psubscribe().Pattern(channel).Build()
+	}
+
+	go func() {
+		defer close(messages)
+
+		err := c.client.Receive(ctx, cmd, func(msg valkey.PubSubMessage) {
+			select {
+			case messages <- ChannelMessage{Channel: msg.Channel, Pattern: msg.Pattern, Message: msg.Message}:
+			case <-ctx.Done():
+			}
+		})
+		_ = err // the caller sees the closed channel; ctx tells it whether that was expected
+	}()
+
+	return messages
+}
+
+// PubSubChannels returns active channels matching pattern ("" = all) with their subscriber counts
+func (c *Client) PubSubChannels(ctx context.Context, pattern string) (map[string]int64, error) {
+	cmd := c.client.B().PubsubChannels()
+	var channels []string
+	var err error
+	if pattern != "" {
+		channels, err = c.do(ctx, cmd.Pattern(pattern).Build()).AsStrSlice()
+	} else {
+		channels, err = c.do(ctx, cmd.Build()).AsStrSlice()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(channels) == 0 {
+		return map[string]int64{}, nil
+	}
+	return c.do(ctx, c.client.B().PubsubNumsub().Channel(channels...).Build()).AsIntMap()
+}
@@ -0,0 +1,104 @@
+package valkey
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// TxOp is one write in a Transaction. Which fields apply depends on Op:
+//
+//	set:    Key, Value, TTL (0 = no expiry)
+//	del:    Key
+//	hset:   Key, Field, Value
+//	zadd:   Key, Member, Score
+//	expire: Key, TTL
+type TxOp struct {
+	Op     string
+	Key    string
+	Field  string
+	Member string
+	Value  string
+	Score  float64
+	TTL    time.Duration
+}
+
+// TxResult is the reply to one TxOp. Error is set when the server rejected
+// the command at EXEC time (e.g. WRONGTYPE); the other commands still ran.
+type TxResult struct {
+	Result any    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Transaction runs ops in order inside MULTI/EXEC on a dedicated connection,
+// so other clients never see a partial result.
+func (c *Client) Transaction(ctx context.Context, ops []TxOp) ([]TxResult, error) {
+	keys := make([]string, len(ops))
+	for i, op := range ops {
+		keys[i] = op.Key
+	}
+	if err := c.checkSameSlot(keys...); err != nil {
+		return nil, err
+	}
+
+	var results []TxResult
+	err := c.client.Dedicated(func(dc valkey.DedicatedClient) error {
+		cmds := make(valkey.Commands, 0, len(ops)+2)
+		cmds = append(cmds, dc.B().Multi().Build())
+		for _, op := range ops {
+			cmd, err := txCommand(dc, op)
+			if err != nil {
+				return err
+			}
+			cmds = append(cmds, cmd)
+		}
+		cmds = append(cmds, dc.B().Exec().Build())
+
+		replies := dc.DoMulti(ctx, cmds...)
+		// MULTI and the QUEUED replies only fail on syntax errors, which abort EXEC
+		for _, r := range replies[:len(replies)-1] {
+			if err := r.Error(); err != nil {
+				return err
+			}
+		}
+		exec, err := replies[len(replies)-1].ToArray()
+		if err != nil {
+			return err
+		}
+
+		results = make([]TxResult, len(exec))
+		for i, msg := range exec {
+			v, err := msg.ToAny()
+			if err != nil {
+				results[i].Error = err.Error()
+				continue
+			}
+			results[i].Result = v
+		}
+		return nil
+	})
+	return results, err
+}
+
+// txCommand builds the command for a single transaction op
+func txCommand(dc valkey.DedicatedClient, op TxOp) (valkey.Completed, error) {
+	switch op.Op {
+	case "set":
+		cmd := dc.B().Set().Key(op.Key).Value(op.Value)
+		if op.TTL > 0 {
+			cmd.Px(op.TTL)
+		}
+		return cmd.Build(), nil
+	case "del":
+		return dc.B().Del().Key(op.Key).Build(), nil
+	case "hset":
+		return dc.B().Hset().Key(op.Key).FieldValue().FieldValue(op.Field, op.Value).Build(), nil
+	case "zadd":
+		return dc.B().Zadd().Key(op.Key).ScoreMember().ScoreMember(op.Score, op.Member).Build(), nil
+	case "expire":
+		return dc.B().Pexpire().Key(op.Key).Milliseconds(op.TTL.Milliseconds()).Build(), nil
+	}
+	return valkey.Completed{}, fmt.Errorf("unsupported transaction op: %s", op.Op)
+}
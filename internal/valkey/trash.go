@@ -0,0 +1,171 @@
+package valkey
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// TrashPrefix is the reserved key prefix for soft-deleted key backups
+const TrashPrefix = "__kvweb:trash:"
+
+// TrashKey returns the backup key name used for a soft-deleted key
+func TrashKey(key string) string {
+	return TrashPrefix + key
+}
+
+// IsTrashKey reports whether key is a soft-delete backup
+func IsTrashKey(key string) bool {
+	return strings.HasPrefix(key, TrashPrefix)
+}
+
+// TrashEntry represents a recoverable soft-deleted key
+type TrashEntry struct {
+	Key string `json:"key"`
+	TTL int64  `json:"ttl"` // seconds until the backup expires
+}
+
+// SoftDelete DUMPs each key into a trash backup that expires after retention,
+// then deletes the original. Returns the number of keys deleted.
+// Each backup holds a full serialized copy of the key, so deleted data keeps
+// using memory until the retention window passes.
+func (c *Client) SoftDelete(ctx context.Context, retention time.Duration, keys ...string) (int64, error) {
+	var deleted int64
+	for _, key := range keys {
+		if c.cluster && KeySlot(key) != KeySlot(TrashKey(key)) {
+			moved, err := c.softDeleteAcrossSlots(ctx, retention, key)
+			if err != nil {
+				return deleted, err
+			}
+			if moved {
+				deleted++
+			}
+			continue
+		}
+		result, err := scriptSoftDelete.Eval(
+			ctx,
+			c,
+			[]string{key, TrashKey(key)},
+			[]string{strconv.FormatInt(int64(retention.Seconds()), 10)},
+		)
+		if err != nil {
+			return deleted, err
+		}
+		if moved, ok := result.(int64); ok && moved == 1 {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// RestoreFromTrash recreates a soft-deleted key from its backup, including its original TTL.
+// Returns an error if there is no backup or the key already exists.
+func (c *Client) RestoreFromTrash(ctx context.Context, key string) error {
+	if c.cluster && KeySlot(key) != KeySlot(TrashKey(key)) {
+		return c.restoreAcrossSlots(ctx, key)
+	}
+	_, err := scriptRestoreFromTrash.Eval(
+		ctx,
+		c,
+		[]string{key, TrashKey(key)},
+		[]string{},
+	)
+	return err
+}
+
+// softDeleteAcrossSlots is SoftDelete for a cluster key whose backup lands in
+// another slot, where no single script can touch both. The same steps run as
+// separate commands, so a write to the key between DUMP and DEL is lost.
+func (c *Client) softDeleteAcrossSlots(ctx context.Context, retention time.Duration, key string) (bool, error) {
+	payload, err := c.Dump(ctx, key)
+	if IsNil(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	pttl, err := c.PTTL(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	trash := TrashKey(key)
+	for _, result := range c.client.DoMulti(ctx,
+		c.client.B().Del().Key(trash).Build(),
+		c.client.B().Hset().Key(trash).FieldValue().FieldValue("payload", payload).FieldValue("pttl", strconv.FormatInt(pttl, 10)).Build(),
+		c.client.B().Expire().Key(trash).Seconds(int64(retention.Seconds())).Build(),
+	) {
+		if err := result.Error(); err != nil {
+			return false, err
+		}
+	}
+
+	n, err := c.Del(ctx, key)
+	return n == 1, err
+}
+
+// restoreAcrossSlots is RestoreFromTrash for a cluster key whose backup is in
+// another slot. RESTORE without REPLACE still refuses to overwrite the key.
+func (c *Client) restoreAcrossSlots(ctx context.Context, key string) error {
+	trash := TrashKey(key)
+	fields, err := c.do(ctx, c.client.B().Hmget().Key(trash).Field("payload", "pttl").Build()).ToArray()
+	if err != nil {
+		return err
+	}
+	payload, err := fields[0].ToString()
+	if IsNil(err) {
+		return fmt.Errorf("Nothing to restore")
+	}
+	if err != nil {
+		return err
+	}
+	pttl, _ := fields[1].AsInt64()
+
+	if err := c.Restore(ctx, key, time.Duration(max(pttl, 0))*time.Millisecond, payload, false); err != nil {
+		if strings.HasPrefix(err.Error(), "BUSYKEY") {
+			return fmt.Errorf("Key already exists")
+		}
+		return err
+	}
+	_, err = c.Del(ctx, trash)
+	return err
+}
+
+// TrashList returns the recoverable keys whose original names match the glob
+// pattern; callers escape any literal prefix in it. TTLs are fetched in one
+// pipeline per SCAN batch.
+func (c *Client) TrashList(ctx context.Context, pattern string) ([]TrashEntry, error) {
+	entries := []TrashEntry{}
+	var cursor uint64
+	for {
+		keys, next, err := c.Keys(ctx, TrashPrefix+pattern, cursor, 1000, "hash")
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) > 0 {
+			cmds := make([]valkey.Completed, len(keys))
+			for i, k := range keys {
+				cmds[i] = c.client.B().Ttl().Key(k).Build()
+			}
+			for i, r := range c.client.DoMulti(ctx, cmds...) {
+				ttl, err := r.ToInt64()
+				if err != nil {
+					return nil, fmt.Errorf("failed to get trash TTL: %w", err)
+				}
+				if ttl == -2 {
+					continue // expired since the scan
+				}
+				entries = append(entries, TrashEntry{Key: strings.TrimPrefix(keys[i], TrashPrefix), TTL: ttl})
+			}
+		}
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	return entries, nil
+}
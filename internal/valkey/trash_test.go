@@ -0,0 +1,169 @@
+package valkey
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/natrimmer/kvweb/internal/config"
+)
+
+// TestSoftDelete tests delete→restore round trips through the trash
+// This requires a running Valkey/Redis instance
+func TestSoftDelete(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	cfg := &config.Config{
+		ValkeyURL: "localhost:6379",
+		ValkeyDB:  15, // Use DB 15 for testing
+	}
+
+	client, err := New(cfg)
+	if err != nil {
+		t.Skip("Valkey not available:", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	// Clean up test keys
+	defer func() {
+		_, _ = client.Del(ctx, "test:trash:string", "test:trash:hash",
+			TrashKey("test:trash:string"), TrashKey("test:trash:hash"))
+	}()
+
+	t.Run("StringRoundTrip", func(t *testing.T) {
+		key := "test:trash:string"
+		_, _ = client.Del(ctx, key, TrashKey(key))
+
+		if err := client.Set(ctx, key, "hello", 120*time.Second); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+
+		deleted, err := client.SoftDelete(ctx, 60*time.Second, key)
+		if err != nil {
+			t.Fatalf("SoftDelete failed: %v", err)
+		}
+		if deleted != 1 {
+			t.Fatalf("expected 1 deleted, got %d", deleted)
+		}
+
+		if n, _ := client.Exists(ctx, key); n != 0 {
+			t.Fatal("expected key to be deleted")
+		}
+
+		entries, err := client.TrashList(ctx, "test:trash:*")
+		if err != nil {
+			t.Fatalf("TrashList failed: %v", err)
+		}
+		found := false
+		for _, e := range entries {
+			if e.Key == key {
+				found = true
+				if e.TTL <= 0 || e.TTL > 60 {
+					t.Errorf("expected trash TTL around 60, got %d", e.TTL)
+				}
+			}
+		}
+		if !found {
+			t.Fatalf("expected %q in trash list", key)
+		}
+
+		if err := client.RestoreFromTrash(ctx, key); err != nil {
+			t.Fatalf("RestoreFromTrash failed: %v", err)
+		}
+
+		val, err := client.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if val != "hello" {
+			t.Errorf("expected value 'hello', got %q", val)
+		}
+
+		// Original TTL is reapplied
+		ttl, err := client.TTL(ctx, key)
+		if err != nil {
+			t.Fatalf("TTL failed: %v", err)
+		}
+		if ttl <= 0 || ttl > 120 {
+			t.Errorf("expected TTL around 120, got %d", ttl)
+		}
+
+		if n, _ := client.Exists(ctx, TrashKey(key)); n != 0 {
+			t.Error("expected trash backup to be removed after restore")
+		}
+	})
+
+	t.Run("HashRoundTrip", func(t *testing.T) {
+		key := "test:trash:hash"
+		_, _ = client.Del(ctx, key, TrashKey(key))
+
+		if err := client.HSet(ctx, key, "name", "Alice"); err != nil {
+			t.Fatalf("HSet failed: %v", err)
+		}
+		if err := client.HSet(ctx, key, "age", "30"); err != nil {
+			t.Fatalf("HSet failed: %v", err)
+		}
+
+		if _, err := client.SoftDelete(ctx, 60*time.Second, key); err != nil {
+			t.Fatalf("SoftDelete failed: %v", err)
+		}
+		if err := client.RestoreFromTrash(ctx, key); err != nil {
+			t.Fatalf("RestoreFromTrash failed: %v", err)
+		}
+
+		fields, err := client.HGetAll(ctx, key)
+		if err != nil {
+			t.Fatalf("HGetAll failed: %v", err)
+		}
+		if fields["name"] != "Alice" || fields["age"] != "30" {
+			t.Errorf("unexpected fields after restore: %v", fields)
+		}
+
+		// No TTL on the original means none after restore
+		ttl, _ := client.TTL(ctx, key)
+		if ttl != -1 {
+			t.Errorf("expected no TTL, got %d", ttl)
+		}
+	})
+
+	t.Run("RestoreConflicts", func(t *testing.T) {
+		key := "test:trash:string"
+		_, _ = client.Del(ctx, key, TrashKey(key))
+
+		// Nothing in the trash
+		if err := client.RestoreFromTrash(ctx, key); err == nil {
+			t.Error("expected error restoring key with no backup")
+		}
+
+		if err := client.Set(ctx, key, "old", 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if _, err := client.SoftDelete(ctx, 60*time.Second, key); err != nil {
+			t.Fatalf("SoftDelete failed: %v", err)
+		}
+
+		// Key recreated in the meantime; restore must not clobber it
+		if err := client.Set(ctx, key, "new", 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if err := client.RestoreFromTrash(ctx, key); err == nil {
+			t.Error("expected error restoring over an existing key")
+		}
+		if val, _ := client.Get(ctx, key); val != "new" {
+			t.Errorf("expected existing value 'new' to be kept, got %q", val)
+		}
+
+		// Deleting a missing key is a no-op
+		deleted, err := client.SoftDelete(ctx, 60*time.Second, "test:trash:missing")
+		if err != nil {
+			t.Fatalf("SoftDelete failed: %v", err)
+		}
+		if deleted != 0 {
+			t.Errorf("expected 0 deleted, got %d", deleted)
+		}
+	})
+}
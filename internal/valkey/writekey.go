@@ -0,0 +1,109 @@
+package valkey
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// KeyValue is a whole key's value for WriteKey. Which field applies depends on Type:
+//
+//	string: String
+//	list:   Items, in order
+//	set:    Items
+//	hash:   Fields
+//	zset:   Members
+//	stream: Entries (an empty ID is auto-generated)
+type KeyValue struct {
+	Type    string
+	String  string
+	Items   []string
+	Fields  map[string]string
+	Members []ZMember
+	Entries []StreamEntry
+}
+
+// WriteKey writes v to key inside MULTI/EXEC, deleting the key first when
+// replace is set and applying ttl afterwards (0 = no expiry). Other clients
+// see the old value or the whole new one, never a deleted key in between. A
+// command the server rejects at EXEC time (e.g. WRONGTYPE, or a stream ID that
+// doesn't increase) is returned as the error, but doesn't undo the others, so
+// callers should validate v beforehand.
+func (c *Client) WriteKey(ctx context.Context, key string, v KeyValue, replace bool, ttl time.Duration) error {
+	return c.client.Dedicated(func(dc valkey.DedicatedClient) error {
+		writes, err := writeCommands(dc, key, v)
+		if err != nil {
+			return err
+		}
+
+		cmds := make(valkey.Commands, 0, len(writes)+4)
+		cmds = append(cmds, dc.B().Multi().Build())
+		if replace {
+			cmds = append(cmds, dc.B().Del().Key(key).Build())
+		}
+		cmds = append(cmds, writes...)
+		if ttl > 0 {
+			cmds = append(cmds, dc.B().Pexpire().Key(key).Milliseconds(ttl.Milliseconds()).Build())
+		}
+		cmds = append(cmds, dc.B().Exec().Build())
+
+		replies := dc.DoMulti(ctx, cmds...)
+		// A command rejected while queuing aborts EXEC, so nothing ran
+		for _, r := range replies[:len(replies)-1] {
+			if err := r.Error(); err != nil {
+				return err
+			}
+		}
+		exec, err := replies[len(replies)-1].ToArray()
+		if err != nil {
+			return err
+		}
+		for _, msg := range exec {
+			if err := msg.Error(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeCommands builds the commands that create v at key
+func writeCommands(dc valkey.DedicatedClient, key string, v KeyValue) (valkey.Commands, error) {
+	switch v.Type {
+	case "string":
+		return valkey.Commands{dc.B().Set().Key(key).Value(v.String).Build()}, nil
+	case "list":
+		return valkey.Commands{dc.B().Rpush().Key(key).Element(v.Items...).Build()}, nil
+	case "set":
+		return valkey.Commands{dc.B().Sadd().Key(key).Member(v.Items...).Build()}, nil
+	case "hash":
+		cmd := dc.B().Hset().Key(key).FieldValue()
+		for field, value := range v.Fields {
+			cmd = cmd.FieldValue(field, value)
+		}
+		return valkey.Commands{cmd.Build()}, nil
+	case "zset":
+		cmd := dc.B().Zadd().Key(key).ScoreMember()
+		for _, m := range v.Members {
+			cmd = cmd.ScoreMember(m.Score, m.Member)
+		}
+		return valkey.Commands{cmd.Build()}, nil
+	case "stream":
+		cmds := make(valkey.Commands, 0, len(v.Entries))
+		for _, e := range v.Entries {
+			id := e.ID
+			if id == "" {
+				id = "*"
+			}
+			cmd := dc.B().Xadd().Key(key).Id(id).FieldValue()
+			for field, value := range e.Fields {
+				cmd = cmd.FieldValue(field, value)
+			}
+			cmds = append(cmds, cmd.Build())
+		}
+		return cmds, nil
+	}
+	return nil, fmt.Errorf("unsupported type: %s", v.Type)
+}
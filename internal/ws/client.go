@@ -2,6 +2,9 @@ package ws
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/coder/websocket"
@@ -13,13 +16,33 @@ const (
 
 	// Send buffer size
 	sendBufferSize = 256
+
+	// How often the server pings the client
+	pingPeriod = 30 * time.Second
+
+	// Time allowed for the client to answer a ping before it's dropped
+	pongWait = 10 * time.Second
 )
 
+// MessageHandler handles a message sent by a client
+type MessageHandler func(ctx context.Context, c *Client, msg ClientMessage)
+
 // Client represents a WebSocket client connection
 type Client struct {
-	hub  *Hub
-	conn *websocket.Conn
-	send chan []byte
+	hub       *Hub
+	conn      *websocket.Conn
+	send      chan []byte
+	onMessage MessageHandler
+
+	mu       sync.Mutex
+	closed   bool
+	dropOnce sync.Once
+
+	// Key event filter set by a subscribe message; both empty = all events
+	filterMu     sync.RWMutex
+	filterKey    string
+	filterPrefix string
+	withValues   bool // include value snapshots in key events
 }
 
 // NewClient creates a new Client
@@ -31,14 +54,60 @@ func NewClient(hub *Hub, conn *websocket.Conn) *Client {
 	}
 }
 
+// OnMessage sets the handler for messages sent by the client
+func (c *Client) OnMessage(fn MessageHandler) {
+	c.onMessage = fn
+}
+
+// Subscribe limits key events sent to this client to an exact key or a key prefix.
+// Passing both empty removes the filter. withValues opts in to value snapshots.
+func (c *Client) Subscribe(key, prefix string, withValues bool) {
+	c.filterMu.Lock()
+	c.filterKey = key
+	c.filterPrefix = prefix
+	c.withValues = withValues
+	c.filterMu.Unlock()
+}
+
+// WantsValues reports whether the client opted in to value snapshots
+func (c *Client) WantsValues() bool {
+	c.filterMu.RLock()
+	defer c.filterMu.RUnlock()
+	return c.withValues
+}
+
+// Wants reports whether a key event for key passes the client's subscription filter
+func (c *Client) Wants(key string) bool {
+	c.filterMu.RLock()
+	defer c.filterMu.RUnlock()
+	if c.filterKey != "" && key == c.filterKey {
+		return true
+	}
+	if c.filterPrefix != "" && strings.HasPrefix(key, c.filterPrefix) {
+		return true
+	}
+	return c.filterKey == "" && c.filterPrefix == ""
+}
+
 // WritePump pumps messages from the hub to the WebSocket connection
 func (c *Client) WritePump(ctx context.Context) {
 	defer func() {
 		_ = c.conn.CloseNow()
 	}()
 
+	ping := time.NewTicker(pingPeriod)
+	defer ping.Stop()
+
 	for {
 		select {
+		case <-ping.C:
+			// Ping waits for the pong, which ReadPump receives; dead peers time out
+			pingCtx, cancel := context.WithTimeout(ctx, pongWait)
+			err := c.conn.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				return
+			}
 		case msg, ok := <-c.send:
 			if !ok {
 				// Hub closed the channel
@@ -56,26 +125,69 @@ func (c *Client) WritePump(ctx context.Context) {
 	}
 }
 
-// ReadPump reads messages from the WebSocket connection (mainly to detect disconnects)
+// ReadPump reads messages from the WebSocket connection and dispatches them to the message handler
 func (c *Client) ReadPump(ctx context.Context) {
 	defer c.hub.Unregister(c)
-	c.conn.SetReadLimit(4096) // We don't process incoming messages; cap to prevent abuse
+	c.conn.SetReadLimit(4096) // Client messages are small commands; cap to prevent abuse
 
 	for {
-		_, _, err := c.conn.Read(ctx)
+		_, data, err := c.conn.Read(ctx)
 		if err != nil {
 			break
 		}
-		// We don't process incoming messages currently
+		if c.onMessage == nil {
+			continue
+		}
+		var msg ClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue // Ignore malformed messages
+		}
+		c.onMessage(ctx, c, msg)
 	}
 }
 
 // Send queues a message to be sent to this client
 func (c *Client) Send(data []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return false
+	}
 	select {
 	case c.send <- data:
 		return true
 	default:
+		c.drop()
 		return false
 	}
 }
+
+// drop disconnects a client that can't keep up. Silently skipping messages would
+// leave its view out of sync, so closing lets the frontend reconnect and refetch.
+// ReadPump then fails and unregisters the client.
+func (c *Client) drop() {
+	c.dropOnce.Do(func() {
+		go func() {
+			_ = c.conn.Close(websocket.StatusPolicyViolation, "send buffer full")
+		}()
+	})
+}
+
+// SendMessage marshals and queues a message to be sent to this client
+func (c *Client) SendMessage(msg Message) bool {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return false
+	}
+	return c.Send(data)
+}
+
+// close closes the send channel so WritePump exits; safe against concurrent Send calls
+func (c *Client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
+}
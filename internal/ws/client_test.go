@@ -0,0 +1,29 @@
+package ws
+
+import "testing"
+
+func TestClientWants(t *testing.T) {
+	tests := []struct {
+		name        string
+		key, prefix string
+		event       string
+		want        bool
+	}{
+		{"no filter", "", "", "anything", true},
+		{"exact match", "user:1", "", "user:1", true},
+		{"exact miss", "user:1", "", "user:10", false},
+		{"prefix match", "", "user:1:", "user:1:name", true},
+		{"prefix miss", "", "user:1:", "user:2:name", false},
+		{"key or prefix", "config", "user:", "config", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{}
+			c.Subscribe(tt.key, tt.prefix, false)
+			if got := c.Wants(tt.event); got != tt.want {
+				t.Errorf("Wants(%q) = %v, want %v", tt.event, got, tt.want)
+			}
+		})
+	}
+}
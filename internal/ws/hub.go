@@ -37,7 +37,7 @@ func (h *Hub) Run() {
 			h.mu.Lock()
 			if _, ok := h.clients[client]; ok {
 				delete(h.clients, client)
-				close(client.send)
+				client.close()
 			}
 			h.mu.Unlock()
 
@@ -46,12 +46,32 @@ func (h *Hub) Run() {
 			if err != nil {
 				continue
 			}
+			event, isKeyEvent := msg.Data.(KeyEventData)
+
+			// Clients that didn't opt in to value snapshots get the bare event
+			bare := data
+			if isKeyEvent && event.HasSnapshot() {
+				stripped := event
+				stripped.Value, stripped.Length, stripped.Truncated = nil, nil, false
+				if b, err := json.Marshal(Message{Type: msg.Type, Data: stripped}); err == nil {
+					bare = b
+				}
+			}
+
 			h.mu.RLock()
 			for client := range h.clients {
+				if isKeyEvent && !client.Wants(event.Key) {
+					continue
+				}
+				payload := data
+				if isKeyEvent && !client.WantsValues() {
+					payload = bare
+				}
 				select {
-				case client.send <- data:
+				case client.send <- payload:
 				default:
-					// Client buffer full, skip
+					// Client buffer full: it's too slow, disconnect it
+					client.drop()
 				}
 			}
 			h.mu.RUnlock()
@@ -68,6 +88,19 @@ func (h *Hub) Broadcast(msg Message) {
 	}
 }
 
+// WantsValues reports whether any client subscribed to key wants value snapshots,
+// so snapshots are only fetched when someone will receive them
+func (h *Hub) WantsValues(key string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for client := range h.clients {
+		if client.WantsValues() && client.Wants(key) {
+			return true
+		}
+	}
+	return false
+}
+
 // Register adds a client to the hub
 func (h *Hub) Register(c *Client) {
 	h.register <- c
@@ -2,7 +2,7 @@ package ws
 
 // Message is the wrapper for all WebSocket messages
 type Message struct {
-	Type string `json:"type"` // "key_event", "stats", "status"
+	Type string `json:"type"` // "key_event", "stats", "status", "key_appeared", "await_timeout", "progress", "subscribed", "pubsub_subscribed", "pubsub_message", "stream_tail", "stream_entries", "monitor", "monitor_stopped", "error"
 	Data any    `json:"data"`
 }
 
@@ -10,14 +10,37 @@ type Message struct {
 type KeyEventData struct {
 	Op  string `json:"op"` // "set", "del", "expire", "expired", "rename_from", "rename_to"
 	Key string `json:"key"`
+
+	// Optional snapshot for clients subscribed with withValues
+	Value     *string `json:"value,omitempty"`     // string keys, capped
+	Length    *int64  `json:"length,omitempty"`    // bytes for strings, elements for collections
+	Truncated bool    `json:"truncated,omitempty"` // value was cut to the cap
+}
+
+// HasSnapshot reports whether the event carries a value snapshot
+func (e KeyEventData) HasSnapshot() bool {
+	return e.Value != nil || e.Length != nil
 }
 
 // StatsData represents periodic stats updates
 type StatsData struct {
-	DBSize          int64  `json:"dbSize"`
-	UsedMemory      int64  `json:"usedMemory"`      // bytes
-	UsedMemoryHuman string `json:"usedMemoryHuman"` // formatted (e.g., "1.18M")
-	NotificationsOn bool   `json:"notificationsOn"`
+	DBSize          int64     `json:"dbSize"`
+	UsedMemory      int64     `json:"usedMemory"`      // bytes
+	UsedMemoryHuman string    `json:"usedMemoryHuman"` // formatted (e.g., "1.18M")
+	NotificationsOn bool      `json:"notificationsOn"`
+	DBConnected     bool      `json:"dbConnected"`
+	LastError       string    `json:"lastError,omitempty"` // ping error when disconnected
+	LatencyMs       float64   `json:"latencyMs"`           // PING round trip for this sample
+	LatencyHistory  []float64 `json:"latencyHistory"`      // recent tick samples, oldest first
+	HitRatio        *float64  `json:"hitRatio"`            // keyspace hits/(hits+misses) over the last tick; null if no lookups
+}
+
+// ProgressData reports how far a long-running scan (e.g. "bigkeys") has got
+type ProgressData struct {
+	Op      string `json:"op"`
+	Scanned int64  `json:"scanned"`
+	Total   int64  `json:"total"` // database size, 0 if unknown
+	Done    bool   `json:"done"`
 }
 
 // StatusData represents connection status information
@@ -25,3 +48,69 @@ type StatusData struct {
 	Live bool   `json:"live"`          // true if keyspace notifications are enabled
 	Msg  string `json:"msg,omitempty"` // optional message
 }
+
+// ClientMessage is a message sent from a client to the server
+type ClientMessage struct {
+	Type      string `json:"type"`                 // "await_key", "subscribe", "unsubscribe", "pubsub_subscribe", "pubsub_unsubscribe", "stream_tail", "stream_untail"
+	Key       string `json:"key,omitempty"`        // await_key: key to wait for; subscribe: exact key; stream_tail: stream key
+	Prefix    string `json:"prefix,omitempty"`     // subscribe: key prefix
+	Values    bool   `json:"withValues,omitempty"` // subscribe: include value snapshots in key events
+	TimeoutMs int64  `json:"timeoutMs,omitempty"`  // await_key: how long to wait
+	Channel   string `json:"channel,omitempty"`    // pubsub_subscribe: channel name or pattern
+	Pattern   bool   `json:"pattern,omitempty"`    // pubsub_subscribe: treat channel as a PSUBSCRIBE pattern
+}
+
+// SubscriptionData echoes the key event filter now active for a client
+type SubscriptionData struct {
+	Key        string `json:"key,omitempty"`
+	Prefix     string `json:"prefix,omitempty"`
+	WithValues bool   `json:"withValues,omitempty"`
+}
+
+// PubSubSubscriptionData echoes the pub/sub channel now watched; empty when none
+type PubSubSubscriptionData struct {
+	Channel string `json:"channel,omitempty"`
+	Pattern bool   `json:"pattern,omitempty"`
+}
+
+// PubSubData is a message received on a watched pub/sub channel
+type PubSubData struct {
+	Channel   string `json:"channel"`
+	Pattern   string `json:"pattern,omitempty"` // matching pattern for pattern subscriptions
+	Message   string `json:"message"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// StreamTailData echoes the stream now tailed; empty when none. Reason is set
+// when the server ended the tail (e.g. the key was deleted).
+type StreamTailData struct {
+	Key    string `json:"key,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// StreamEntryData is one stream entry
+type StreamEntryData struct {
+	ID     string            `json:"id"`
+	Fields map[string]string `json:"fields"`
+}
+
+// StreamEntriesData carries entries newly added to a tailed stream, oldest first
+type StreamEntriesData struct {
+	Key     string            `json:"key"`
+	Entries []StreamEntryData `json:"entries"`
+}
+
+// MonitorData is one command line reported by MONITOR
+type MonitorData struct {
+	Line string `json:"line"`
+}
+
+// KeyData identifies the key a message refers to
+type KeyData struct {
+	Key string `json:"key"`
+}
+
+// ErrorData reports a rejected client message
+type ErrorData struct {
+	Msg string `json:"msg"`
+}
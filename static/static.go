@@ -1,23 +1,45 @@
 package static
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"io/fs"
 	"net/http"
+	"strings"
+	"time"
 )
 
 //go:embed dist/*
 var content embed.FS
 
-// Handler returns an http.Handler that serves the embedded static files
-func Handler() http.Handler {
+// assetsDir holds the build's content-hashed files, which never change under
+// the same name and can be cached for good
+const assetsDir = "assets/"
+
+// Handler returns an http.Handler that serves the embedded static files.
+// basePath is where kvweb is mounted behind a proxy ("" for the root); the
+// app's <base href> is rewritten to it so relative asset and API URLs resolve.
+func Handler(basePath string) http.Handler {
 	// Strip the "dist" prefix so files are served from root
 	dist, err := fs.Sub(content, "dist")
 	if err != nil {
 		panic(err)
 	}
+	return newHandler(dist, basePath)
+}
 
+func newHandler(dist fs.FS, basePath string) http.Handler {
 	fileServer := http.FileServer(http.FS(dist))
+	etags := contentETags(dist)
+
+	index, err := fs.ReadFile(dist, "index.html")
+	if err != nil {
+		panic(err)
+	}
+	index = bytes.Replace(index, []byte(`<base href="/"`), []byte(`<base href="`+basePath+`/"`), 1)
+	etags["index.html"] = contentETag(index)
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Security-Policy",
@@ -36,10 +58,62 @@ func Handler() http.Handler {
 
 		// Check if file exists
 		if _, err := fs.Stat(dist, path[1:]); err != nil {
+			// API and WebSocket clients expect JSON, not the app shell
+			if isBackendPath(path) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"Not found"}` + "\n"))
+				return
+			}
 			// File doesn't exist, serve index.html for SPA
 			r.URL.Path = "/"
+			path = "/index.html"
+		}
+
+		// The file server answers If-None-Match with 304 once ETag is set
+		name := path[1:]
+		if etag, ok := etags[name]; ok {
+			w.Header().Set("ETag", etag)
+		}
+		if strings.HasPrefix(name, assetsDir) {
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			// index.html names the current assets, so always revalidate it
+			w.Header().Set("Cache-Control", "no-cache")
 		}
 
+		if name == "index.html" {
+			http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(index))
+			return
+		}
 		fileServer.ServeHTTP(w, r)
 	})
 }
+
+// isBackendPath reports whether path belongs to the API or WebSocket routes
+func isBackendPath(path string) bool {
+	return path == "/api" || strings.HasPrefix(path, "/api/") ||
+		path == "/ws" || strings.HasPrefix(path, "/ws/")
+}
+
+// contentETags hashes every file in dist once, keyed by path
+func contentETags(dist fs.FS) map[string]string {
+	etags := make(map[string]string)
+	_ = fs.WalkDir(dist, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(dist, name)
+		if err != nil {
+			return err
+		}
+		etags[name] = contentETag(data)
+		return nil
+	})
+	return etags
+}
+
+func contentETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
@@ -0,0 +1,94 @@
+package static
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"index.html":             {Data: []byte(`<!doctype html><base href="/" /><script src="./assets/index-abc123.js"></script>`)},
+		"assets/index-abc123.js": {Data: []byte(`console.log("kvweb")`)},
+	}
+}
+
+func TestCacheHeaders(t *testing.T) {
+	h := newHandler(testFS(), "")
+
+	tests := []struct {
+		path         string
+		cacheControl string
+	}{
+		{"/", "no-cache"},
+		{"/index.html", "no-cache"},
+		{"/some/spa/route", "no-cache"},
+		{"/assets/index-abc123.js", "public, max-age=31536000, immutable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+			if got := rec.Header().Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.cacheControl)
+			}
+			if rec.Header().Get("ETag") == "" {
+				t.Error("missing ETag")
+			}
+		})
+	}
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	h := newHandler(testFS(), "")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/assets/index-abc123.js", nil))
+	etag := rec.Header().Get("ETag")
+
+	req := httptest.NewRequest("GET", "/assets/index-abc123.js", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for matching ETag, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/assets/index-abc123.js", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for stale ETag, got %d", rec.Code)
+	}
+}
+
+func TestBasePath(t *testing.T) {
+	h := newHandler(testFS(), "/kvweb")
+
+	for _, path := range []string{"/", "/index.html", "/some/spa/route"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if !strings.Contains(rec.Body.String(), `<base href="/kvweb/"`) {
+			t.Errorf("%s: base href not rewritten: %s", path, rec.Body.String())
+		}
+	}
+}
+
+func TestBackendPathsNotFound(t *testing.T) {
+	h := newHandler(testFS(), "")
+
+	for _, path := range []string{"/api", "/api/nonexistent", "/ws/extra"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status = %d, want 404", path, rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s: Content-Type = %q, want application/json", path, ct)
+		}
+	}
+}
@@ -1,4 +1,12 @@
-const BASE_URL = '/api';
+// The server points <base href> at --base-path, so kvweb works behind a proxy subpath
+export const BASE_PATH = new URL('.', document.baseURI).pathname;
+const BASE_URL = `${BASE_PATH}api`;
+
+// Destructive requests are only sent after the user confirms in the UI
+const CONFIRM_HEADERS = { 'X-Kvweb-Confirm': 'yes' };
+
+// Console commands the server treats as destructive under --require-confirm-header
+const DESTRUCTIVE_COMMANDS = ['DEL', 'UNLINK', 'FLUSHDB', 'FLUSHALL'];
 
 export interface ZSetMember {
 	member: string;
@@ -11,6 +19,14 @@ export interface GeoMember {
 	latitude: number;
 }
 
+export interface GeoSearchResult extends GeoMember {
+	distance: number;
+}
+
+export type GeoSearchArea =
+	| { radius: number }
+	| { width: number; height: number };
+
 export interface StreamEntry {
 	id: string;
 	fields: Record<string, string>;
@@ -25,8 +41,9 @@ export interface PaginationInfo {
 	page?: number;
 	pageSize: number;
 	total: number;
+	totalPages: number;
 	hasMore: boolean;
-	nextCursor?: number;
+	nextCursor?: string; // opaque; empty when there are no more pages
 }
 
 export type KeyType = 'string' | 'list' | 'set' | 'hash' | 'zset' | 'stream' | 'hyperloglog';
@@ -52,10 +69,16 @@ export interface KeyInfo {
 	length?: number;
 	pagination?: PaginationInfo;
 	encoding?: string;
+	range?: [number, number];
+	truncated?: boolean;
+	version?: string;
+	latest?: StreamEntry;
+	warnings?: string[];
 }
 
 export interface ServerInfo {
 	info: string;
+	sections: Record<string, Record<string, string>>;
 	dbSize: number;
 }
 
@@ -63,17 +86,47 @@ export interface KeyMeta {
 	key: string;
 	type: string;
 	ttl: number;
+	memory?: number;
 }
 
 export interface KeysResponse {
 	keys: string[] | KeyMeta[];
 	cursor: number;
+	dbSize?: number;
+	scanned?: number; // estimated keys examined so far; pass back as `scanned` with the next cursor
+	total?: number; // sorted mode only
+	truncated?: boolean; // sorted mode only: scan cap hit before sorting
 }
 
+export type KeySort = 'name' | 'ttl' | 'type' | 'size';
+
 export interface AppConfig {
 	readOnly: boolean;
+	dryRun?: boolean;
 	prefix: string;
+	allowPatterns?: string[] | null;
+	denyPatterns?: string[] | null;
+	writableTypes?: string[]; // empty when read-only
 	disableFlush: boolean;
+	softDelete?: boolean;
+	requireConfirm?: boolean;
+	// Limits, 0 = none
+	maxKeys?: number;
+	scanCount?: number;
+	maxValueSize?: number; // bytes
+	maxBodySize?: number; // bytes
+	maxImportSize?: number; // bytes
+	maxConcurrentScans?: number;
+	opTimeout?: number; // seconds
+	// Optional endpoints
+	clientKill?: boolean;
+	debug?: boolean;
+	acl?: boolean;
+	serverConfig?: boolean;
+	monitor?: boolean;
+	monitorAuth?: boolean; // /api/monitor needs a token
+	metrics?: boolean;
+	notifications?: boolean; // keyspace notifications auto-enabled at startup
 	version: string;
 	commit: string;
 	dirty: boolean;
@@ -92,10 +145,20 @@ export interface PrefixResponse {
 	prefix: string;
 }
 
+export interface VersionInfo {
+	version: string;
+	commit: string;
+	dirty: boolean;
+	goVersion: string;
+	serverVersion?: string; // missing if the server couldn't be queried
+}
+
 export interface HealthResponse {
 	status: 'ok' | 'degraded';
 	database: boolean;
 	timestamp: number;
+	uptime: number; // seconds since kvweb started
+	version: string;
 }
 
 async function request<T>(path: string, options?: RequestInit): Promise<T> {
@@ -115,6 +178,127 @@ async function request<T>(path: string, options?: RequestInit): Promise<T> {
 	return res.json();
 }
 
+export interface KeyStat {
+	key: string;
+	type: string;
+	length: number;
+	memory: number;
+	ttl: number;
+}
+
+export interface BigKeysResponse {
+	bySize: KeyStat[];
+	byLength: KeyStat[];
+	types: Record<string, { count: number; memory: number }>;
+	scanned: number;
+}
+
+export interface TypeDistributionResponse {
+	types: Record<string, { count: number; memory: number }>;
+	scanned: number;
+	sampled: boolean;
+	dbSize: number;
+}
+
+export interface TTLDistributionResponse {
+	buckets: Record<'noExpiry' | 'lt1m' | 'lt1h' | 'lt1d' | 'gt1d', number>;
+	scanned: number;
+	sampled: boolean;
+	dbSize: number;
+}
+
+export interface SlowLogEntry {
+	id: number;
+	timestamp: number;
+	durationUs: number;
+	args: string[];
+	clientAddr?: string;
+	clientName?: string;
+}
+
+export interface HistoryEntry {
+	id: number;
+	time: string;
+	method: string;
+	path: string;
+	key?: string;
+	undoable: boolean;
+	undone: boolean;
+}
+
+export interface ClientInfo {
+	id: number;
+	addr: string;
+	name?: string;
+	age: number;
+	idle: number;
+	flags?: string;
+	db: number;
+	cmd?: string;
+}
+
+// Password hashes are never sent; passwords is how many are set
+export interface AclUser {
+	name: string;
+	rules: string;
+	flags: string[];
+	passwords: number;
+	commands: string;
+	keys: string;
+	channels: string;
+}
+
+export interface ReplicaInfo {
+	addr: string;
+	state: string;
+	offset: number;
+	lag: number;
+}
+
+export interface ReplicationInfo {
+	role: string;
+	connectedReplicas: number;
+	replicas: ReplicaInfo[];
+	replOffset: number;
+	masterHost?: string;
+	masterPort?: number;
+	masterLinkStatus?: string;
+	masterLastIOSeconds?: number;
+	syncInProgress?: boolean;
+}
+
+export interface MigrateTarget {
+	host: string;
+	port?: number;
+	db?: number;
+	password?: string;
+	timeout?: number; // ms
+	copy?: boolean;
+	replace?: boolean;
+}
+
+export interface MemoryConfig {
+	maxmemory: number;
+	maxmemoryPolicy: string;
+	usedMemory?: number;
+	usedMemoryHuman?: string;
+}
+
+export interface TransactionOp {
+	op: 'set' | 'del' | 'hset' | 'zadd' | 'expire';
+	key: string;
+	field?: string;
+	member?: string;
+	value?: string;
+	score?: number;
+	ttl?: number;
+}
+
+export interface TransactionResult {
+	result?: unknown;
+	error?: string;
+}
+
 export interface ExecResult {
 	type: 'string' | 'integer' | 'array' | 'nil' | 'error';
 	value: string | number | ExecResult[] | null;
@@ -129,23 +313,138 @@ export const api = {
 		return request('/config');
 	},
 
+	getVersion(): Promise<VersionInfo> {
+		return request('/version');
+	},
+
 	getInfo(section?: string): Promise<ServerInfo> {
 		const params = section ? `?section=${encodeURIComponent(section)}` : '';
 		return request(`/info${params}`);
 	},
 
+	getMemoryConfig(): Promise<MemoryConfig> {
+		return request('/config/memory');
+	},
+
+	setMemoryConfig(update: { maxmemory?: string; maxmemoryPolicy?: string }): Promise<MemoryConfig> {
+		return request('/config/memory', {
+			method: 'POST',
+			body: JSON.stringify(update)
+		});
+	},
+
+	getServerConfig(pattern = '*'): Promise<{ parameters: { name: string; value: string }[]; writable: boolean }> {
+		return request(`/config/server?pattern=${encodeURIComponent(pattern)}`);
+	},
+
+	setServerConfig(parameters: Record<string, string>): Promise<{ updated: number }> {
+		return request('/config/server', {
+			method: 'POST',
+			body: JSON.stringify({ parameters })
+		});
+	},
+
+	getSlowLog(count = 50): Promise<{ entries: SlowLogEntry[] }> {
+		return request(`/slowlog?count=${count}`);
+	},
+
+	resetSlowLog(): Promise<void> {
+		return request('/slowlog/reset', { method: 'POST' });
+	},
+
+	getHistory(): Promise<{ entries: HistoryEntry[] }> {
+		return request('/history');
+	},
+
+	undoHistory(id: number): Promise<void> {
+		return request(`/history/${id}/undo`, { method: 'POST' });
+	},
+
+	getClients(): Promise<{ clients: ClientInfo[] }> {
+		return request('/clients');
+	},
+
+	killClient(id: number): Promise<void> {
+		return request(`/clients/${id}/kill`, { method: 'POST', headers: CONFIRM_HEADERS });
+	},
+
+	getKeyDebug(
+		key: string
+	): Promise<{ key: string; fields: Record<string, string>; serializedLength?: number; encoding: string }> {
+		return request(`/key/${encodeURIComponent(key)}/debug`);
+	},
+
+	getAcl(): Promise<{ whoami: string; users: AclUser[] }> {
+		return request('/acl');
+	},
+
+	dumpKey(key: string): Promise<{ key: string; payload: string; ttl: number }> {
+		return request(`/key/${encodeURIComponent(key)}/dump`);
+	},
+
+	restoreKey(key: string, payload: string, ttl = 0, replace = false): Promise<void> {
+		return request(`/key/${encodeURIComponent(key)}/restore`, {
+			method: 'POST',
+			headers: replace ? CONFIRM_HEADERS : undefined,
+			body: JSON.stringify({ payload, ttl, replace })
+		});
+	},
+
+	migrateKey(key: string, target: MigrateTarget): Promise<{ copied: boolean }> {
+		return request(`/key/${encodeURIComponent(key)}/migrate`, {
+			method: 'POST',
+			headers: !target.copy || target.replace ? CONFIRM_HEADERS : undefined,
+			body: JSON.stringify(target)
+		});
+	},
+
+	getKeyType(key: string): Promise<{ key: string; type: string }> {
+		return request(`/key/${encodeURIComponent(key)}/type`);
+	},
+
+	keysExist(keys: string[]): Promise<{ exists: Record<string, boolean>; count: number }> {
+		return request('/keys/exists', {
+			method: 'POST',
+			body: JSON.stringify({ keys })
+		});
+	},
+
+	sampleKeys(n = 20): Promise<{ keys: KeyMeta[] }> {
+		return request(`/keys/sample?n=${n}`);
+	},
+
+	getReplication(wait = false): Promise<{ replication: ReplicationInfo; acked?: number }> {
+		return request(`/replication${wait ? '?wait=1' : ''}`);
+	},
+
+	getPubSubChannels(pattern?: string): Promise<{ channels: { channel: string; subscribers: number }[] }> {
+		const params = pattern ? `?pattern=${encodeURIComponent(pattern)}` : '';
+		return request(`/pubsub/channels${params}`);
+	},
+
 	getKeys(
 		pattern = '*',
 		cursor = 0,
 		count = 100,
 		type?: string,
 		meta = false,
-		regex = false
+		regex = false,
+		sort?: KeySort,
+		order: 'asc' | 'desc' = 'asc',
+		search?: string,
+		caseInsensitive = true,
+		scanned?: number
 	): Promise<KeysResponse> {
 		let url = `/keys?pattern=${encodeURIComponent(pattern)}&cursor=${cursor}&count=${count}`;
 		if (type) url += `&type=${encodeURIComponent(type)}`;
 		if (meta) url += '&meta=1';
 		if (regex) url += '&regex=1';
+		if (sort) url += `&sort=${sort}&order=${order}`;
+		if (search) {
+			url += `&search=${encodeURIComponent(search)}`;
+			if (caseInsensitive) url += '&caseInsensitive=1';
+		}
+		if (scanned !== undefined) url += `&scanned=${scanned}`;
 		return request(url);
 	},
 
@@ -155,39 +454,90 @@ export const api = {
 		);
 	},
 
-	getKey(key: string, page?: number, pageSize?: number, cursor?: number): Promise<KeyInfo> {
+	getKey(
+		key: string,
+		page?: number,
+		pageSize?: number,
+		cursor?: string,
+		reverse?: boolean,
+		match?: string // glob over set members / hash fields
+	): Promise<KeyInfo> {
 		let url = `/key/${encodeURIComponent(key)}`;
 		const params = new URLSearchParams();
 		if (page !== undefined) params.set('page', page.toString());
 		if (pageSize !== undefined) params.set('pageSize', pageSize.toString());
-		if (cursor !== undefined) params.set('cursor', cursor.toString());
+		if (cursor) params.set('cursor', cursor);
+		if (reverse) params.set('reverse', '1');
+		if (match) params.set('match', match);
 		if (params.toString()) url += `?${params.toString()}`;
 		return request(url);
 	},
 
-	setKey(key: string, value: string, ttl = 0, encoding?: string): Promise<void> {
+	getZSetByScore(
+		key: string,
+		min = '-inf',
+		max = '+inf',
+		page?: number,
+		pageSize?: number
+	): Promise<KeyInfo> {
+		const params = new URLSearchParams({ byScore: '1', min, max });
+		if (page !== undefined) params.set('page', page.toString());
+		if (pageSize !== undefined) params.set('pageSize', pageSize.toString());
+		return request(`/key/${encodeURIComponent(key)}?${params.toString()}`);
+	},
+
+	// With a version from getKey, the save fails with 412 if the value changed since
+	setKey(key: string, value: string, ttl = 0, encoding?: string, version?: string): Promise<void> {
 		return request(`/key/${encodeURIComponent(key)}`, {
 			method: 'PUT',
-			body: JSON.stringify({ value, ttl, ...(encoding && { encoding }) })
+			body: JSON.stringify({ value, ttl, ...(encoding && { encoding }), ...(version && { version }) })
 		});
 	},
 
-	incrKey(key: string, amount: number): Promise<{ value: string }> {
+	incrKey(key: string, amount: number): Promise<{ value: string | number }> {
 		return request(`/key/${encodeURIComponent(key)}/incr`, {
 			method: 'POST',
 			body: JSON.stringify({ amount })
 		});
 	},
 
+	getKeyObject(key: string): Promise<{
+		key: string;
+		encoding: string;
+		refCount?: number;
+		idleTime?: number; // non-LFU policies
+		freq?: number; // LFU policies only
+		evictionPolicy?: string;
+		memory?: number;
+	}> {
+		return request(`/key/${encodeURIComponent(key)}/object`);
+	},
+
+	appendKey(key: string, value: string): Promise<{ length: number }> {
+		return request(`/key/${encodeURIComponent(key)}/append`, {
+			method: 'POST',
+			body: JSON.stringify({ value })
+		});
+	},
+
+	setRange(key: string, offset: number, value: string): Promise<{ length: number }> {
+		return request(`/key/${encodeURIComponent(key)}/range`, {
+			method: 'PATCH',
+			body: JSON.stringify({ offset, value })
+		});
+	},
+
 	deleteKey(key: string): Promise<{ deleted: number }> {
 		return request(`/key/${encodeURIComponent(key)}`, {
-			method: 'DELETE'
+			method: 'DELETE',
+			headers: CONFIRM_HEADERS
 		});
 	},
 
 	deleteKeys(keys: string[]): Promise<{ deleted: number }> {
 		return request('/keys/delete', {
 			method: 'POST',
+			headers: CONFIRM_HEADERS,
 			body: JSON.stringify({ keys })
 		});
 	},
@@ -202,12 +552,13 @@ export const api = {
 	renameKey(key: string, newKey: string): Promise<void> {
 		return request(`/key/${encodeURIComponent(key)}/rename`, {
 			method: 'POST',
+			headers: CONFIRM_HEADERS,
 			body: JSON.stringify({ newKey })
 		});
 	},
 
 	flushDb(): Promise<void> {
-		return request('/flush', { method: 'POST' });
+		return request('/flush', { method: 'POST', headers: CONFIRM_HEADERS });
 	},
 
 	getNotifications(): Promise<{ enabled: boolean; value: string }> {
@@ -229,6 +580,25 @@ export const api = {
 		});
 	},
 
+	listInsert(
+		key: string,
+		pivot: string,
+		value: string,
+		position: 'before' | 'after'
+	): Promise<{ length: number }> {
+		return request(`/key/${encodeURIComponent(key)}/list/insert`, {
+			method: 'POST',
+			body: JSON.stringify({ pivot, value, position })
+		});
+	},
+
+	listTrim(key: string, start: number, stop: number): Promise<{ length: number }> {
+		return request(`/key/${encodeURIComponent(key)}/list/trim`, {
+			method: 'POST',
+			body: JSON.stringify({ start, stop })
+		});
+	},
+
 	listSet(key: string, index: number, value: string): Promise<void> {
 		return request(`/key/${encodeURIComponent(key)}/list/${index}`, {
 			method: 'PUT',
@@ -263,6 +633,29 @@ export const api = {
 		});
 	},
 
+	setOp(
+		op: 'inter' | 'union' | 'diff',
+		keys: string[],
+		page = 1,
+		pageSize = 100
+	): Promise<{ members: string[]; pagination: PaginationInfo }> {
+		return request('/sets/op', {
+			method: 'POST',
+			body: JSON.stringify({ op, keys, page, pageSize })
+		});
+	},
+
+	setOpStore(
+		op: 'inter' | 'union' | 'diff',
+		keys: string[],
+		store: string
+	): Promise<{ store: string; total: number }> {
+		return request('/sets/op', {
+			method: 'POST',
+			body: JSON.stringify({ op, keys, store })
+		});
+	},
+
 	// Hash operations
 	hashSet(key: string, field: string, value: string): Promise<void> {
 		return request(`/key/${encodeURIComponent(key)}/hash`, {
@@ -271,6 +664,20 @@ export const api = {
 		});
 	},
 
+	hashSetNX(key: string, field: string, value: string): Promise<void> {
+		return request(`/key/${encodeURIComponent(key)}/hash`, {
+			method: 'POST',
+			body: JSON.stringify({ field, value, nx: true })
+		});
+	},
+
+	hashSetMany(key: string, fields: Record<string, string>): Promise<{ fields: number }> {
+		return request(`/key/${encodeURIComponent(key)}/hash`, {
+			method: 'POST',
+			body: JSON.stringify({ fields })
+		});
+	},
+
 	hashRemove(key: string, field: string): Promise<void> {
 		return request(`/key/${encodeURIComponent(key)}/hash/${encodeURIComponent(field)}`, {
 			method: 'DELETE'
@@ -292,6 +699,13 @@ export const api = {
 		});
 	},
 
+	zsetLocate(
+		key: string,
+		member: string
+	): Promise<{ rank: number; revRank: number; score: number }> {
+		return request(`/key/${encodeURIComponent(key)}/zset/${encodeURIComponent(member)}`);
+	},
+
 	zsetRemove(key: string, member: string): Promise<void> {
 		return request(`/key/${encodeURIComponent(key)}/zset/${encodeURIComponent(member)}`, {
 			method: 'DELETE'
@@ -329,11 +743,41 @@ export const api = {
 		});
 	},
 
+	geoHash(key: string, members: string[]): Promise<{ hashes: Record<string, string | null> }> {
+		const params = new URLSearchParams({ members: members.join(',') });
+		return request(`/key/${encodeURIComponent(key)}/geo/hash?${params.toString()}`);
+	},
+
+	geoSearch(
+		key: string,
+		longitude: number,
+		latitude: number,
+		area: GeoSearchArea,
+		unit: 'm' | 'km' | 'mi' | 'ft' = 'km',
+		count?: number
+	): Promise<{ members: GeoSearchResult[]; unit: string; limited: boolean }> {
+		const params = new URLSearchParams({ lon: String(longitude), lat: String(latitude), unit });
+		if ('radius' in area) {
+			params.set('radius', String(area.radius));
+		} else {
+			params.set('width', String(area.width));
+			params.set('height', String(area.height));
+		}
+		if (count !== undefined) params.set('count', count.toString());
+		return request(`/key/${encodeURIComponent(key)}/geo/search?${params.toString()}`);
+	},
+
 	// Stream operations
-	streamAdd(key: string, fields: Record<string, string>): Promise<{ id: string }> {
+	streamAdd(
+		key: string,
+		fields: Record<string, string>,
+		maxlen?: number,
+		approx = true,
+		id?: string
+	): Promise<{ id: string }> {
 		return request(`/key/${encodeURIComponent(key)}/stream`, {
 			method: 'POST',
-			body: JSON.stringify({ fields })
+			body: JSON.stringify({ fields, ...(maxlen && { maxlen, approx }), ...(id && { id }) })
 		});
 	},
 
@@ -344,13 +788,27 @@ export const api = {
 	},
 
 	// HyperLogLog operations
-	hllAdd(key: string, element: string): Promise<void> {
+	hllAdd(key: string, element: string): Promise<{ count: number }> {
 		return request(`/key/${encodeURIComponent(key)}/hll`, {
 			method: 'POST',
 			body: JSON.stringify({ element })
 		});
 	},
 
+	hllAddMany(key: string, elements: string[]): Promise<{ added: number; count: number }> {
+		return request(`/key/${encodeURIComponent(key)}/hll`, {
+			method: 'POST',
+			body: JSON.stringify({ elements })
+		});
+	},
+
+	hllMerge(key: string, sources: string[]): Promise<{ count: number }> {
+		return request(`/key/${encodeURIComponent(key)}/hll/merge`, {
+			method: 'POST',
+			body: JSON.stringify({ sources })
+		});
+	},
+
 	// Memory usage
 	getKeysMemory(keys: string[]): Promise<{ memory: Record<string, number> }> {
 		return request('/keys/memory', {
@@ -359,10 +817,38 @@ export const api = {
 		});
 	},
 
-	// Console
+	// Analysis
+	getBigKeys(pattern = '*', top = 10): Promise<BigKeysResponse> {
+		return request(`/analysis/bigkeys?pattern=${encodeURIComponent(pattern)}&top=${top}`);
+	},
+
+	getTypeDistribution(pattern = '*', sample?: number): Promise<TypeDistributionResponse> {
+		let url = `/analysis/types?pattern=${encodeURIComponent(pattern)}`;
+		if (sample) url += `&sample=${sample}`;
+		return request(url);
+	},
+
+	getTTLDistribution(pattern = '*', sample?: number): Promise<TTLDistributionResponse> {
+		let url = `/analysis/ttl?pattern=${encodeURIComponent(pattern)}`;
+		if (sample) url += `&sample=${sample}`;
+		return request(url);
+	},
+
+	// Atomic multi-key writes
+	transaction(ops: TransactionOp[]): Promise<{ results: TransactionResult[] }> {
+		return request('/transaction', {
+			method: 'POST',
+			headers: ops.some((op) => op.op === 'del') ? CONFIRM_HEADERS : undefined,
+			body: JSON.stringify({ ops })
+		});
+	},
+
+	// Console; a typed DEL/UNLINK/FLUSH* counts as confirmed
 	exec(command: string): Promise<ExecResult> {
+		const name = command.trim().split(/\s+/)[0].toUpperCase();
 		return request('/exec', {
 			method: 'POST',
+			headers: DESTRUCTIVE_COMMANDS.includes(name) ? CONFIRM_HEADERS : undefined,
 			body: JSON.stringify({ command })
 		});
 	}
@@ -1,8 +1,13 @@
+import { BASE_PATH } from './api';
+
 // WebSocket message types
 
 export type KeyEvent = {
 	op: 'set' | 'del' | 'expire' | 'expired' | 'rename_from' | 'rename_to';
 	key: string;
+	value?: string; // only when subscribed with withValues
+	length?: number;
+	truncated?: boolean;
 };
 
 export type Stats = {
@@ -10,6 +15,11 @@ export type Stats = {
 	usedMemory: number;
 	usedMemoryHuman: string;
 	notificationsOn: boolean;
+	dbConnected: boolean;
+	lastError?: string;
+	latencyMs: number;
+	latencyHistory: number[]; // ms, oldest first, one sample per 5s tick
+	hitRatio: number | null; // over the last 5s tick; null when there were no lookups
 };
 
 export type Status = {
@@ -17,10 +27,39 @@ export type Status = {
 	msg?: string;
 };
 
+export type Progress = {
+	op: string;
+	scanned: number;
+	total: number;
+	done: boolean;
+};
+
+export type PubSubMessage = {
+	channel: string;
+	pattern?: string;
+	message: string;
+	truncated?: boolean;
+};
+
+export type StreamEntries = {
+	key: string;
+	entries: { id: string; fields: Record<string, string> }[];
+};
+
+// Sent when a tail starts or stops; reason is set when the server ended it
+export type StreamTailStatus = {
+	key?: string;
+	reason?: string;
+};
+
 type Message =
 	| { type: 'key_event'; data: KeyEvent }
 	| { type: 'stats'; data: Stats }
-	| { type: 'status'; data: Status };
+	| { type: 'status'; data: Status }
+	| { type: 'progress'; data: Progress }
+	| { type: 'pubsub_message'; data: PubSubMessage }
+	| { type: 'stream_entries'; data: StreamEntries }
+	| { type: 'stream_tail'; data: StreamTailStatus };
 
 type Handler<T> = (data: T) => void;
 
@@ -29,6 +68,10 @@ class WebSocketManager {
 	private keyHandlers = new Set<Handler<KeyEvent>>();
 	private statsHandlers = new Set<Handler<Stats>>();
 	private statusHandlers = new Set<Handler<Status>>();
+	private progressHandlers = new Set<Handler<Progress>>();
+	private pubsubHandlers = new Set<Handler<PubSubMessage>>();
+	private streamHandlers = new Set<Handler<StreamEntries>>();
+	private streamTailHandlers = new Set<Handler<StreamTailStatus>>();
 	private reconnectDelay = 1000;
 	private shouldReconnect = true;
 	private url: string = '';
@@ -37,7 +80,7 @@ class WebSocketManager {
 		if (this.ws?.readyState === WebSocket.OPEN) return;
 
 		const protocol = location.protocol === 'https:' ? 'wss:' : 'ws:';
-		this.url = `${protocol}//${location.host}/ws`;
+		this.url = `${protocol}//${location.host}${BASE_PATH}ws`;
 		this.ws = new WebSocket(this.url);
 
 		this.ws.onmessage = (e) => {
@@ -49,6 +92,14 @@ class WebSocketManager {
 					this.statsHandlers.forEach((h) => h(msg.data));
 				} else if (msg.type === 'status') {
 					this.statusHandlers.forEach((h) => h(msg.data));
+				} else if (msg.type === 'progress') {
+					this.progressHandlers.forEach((h) => h(msg.data));
+				} else if (msg.type === 'pubsub_message') {
+					this.pubsubHandlers.forEach((h) => h(msg.data));
+				} else if (msg.type === 'stream_entries') {
+					this.streamHandlers.forEach((h) => h(msg.data));
+				} else if (msg.type === 'stream_tail') {
+					this.streamTailHandlers.forEach((h) => h(msg.data));
 				}
 			} catch {
 				// Ignore parse errors
@@ -93,9 +144,90 @@ class WebSocketManager {
 		return () => this.statusHandlers.delete(handler);
 	}
 
+	onProgress(handler: Handler<Progress>): () => void {
+		this.progressHandlers.add(handler);
+		return () => this.progressHandlers.delete(handler);
+	}
+
+	onPubSubMessage(handler: Handler<PubSubMessage>): () => void {
+		this.pubsubHandlers.add(handler);
+		return () => this.pubsubHandlers.delete(handler);
+	}
+
+	// Watch a pub/sub channel (or, with pattern, every matching channel); replaces any previous watch
+	pubsubSubscribe(channel: string, pattern = false) {
+		this.send({ type: 'pubsub_subscribe', channel, pattern });
+	}
+
+	pubsubUnsubscribe() {
+		this.send({ type: 'pubsub_unsubscribe' });
+	}
+
+	onStreamEntries(handler: Handler<StreamEntries>): () => void {
+		this.streamHandlers.add(handler);
+		return () => this.streamHandlers.delete(handler);
+	}
+
+	onStreamTail(handler: Handler<StreamTailStatus>): () => void {
+		this.streamTailHandlers.add(handler);
+		return () => this.streamTailHandlers.delete(handler);
+	}
+
+	// Receive entries added to a stream as they arrive; replaces any previous tail
+	streamTail(key: string) {
+		this.send({ type: 'stream_tail', key });
+	}
+
+	streamUntail() {
+		this.send({ type: 'stream_untail' });
+	}
+
+	// Only receive key events for an exact key and/or a key prefix
+	subscribe(filter: { key?: string; prefix?: string; withValues?: boolean }) {
+		this.send({ type: 'subscribe', ...filter });
+	}
+
+	unsubscribe() {
+		this.send({ type: 'unsubscribe' });
+	}
+
+	private send(msg: object) {
+		if (this.ws?.readyState === WebSocket.OPEN) {
+			this.ws.send(JSON.stringify(msg));
+		}
+	}
+
 	isConnected(): boolean {
 		return this.ws?.readyState === WebSocket.OPEN;
 	}
 }
 
 export const ws = new WebSocketManager();
+
+// Opens a MONITOR stream (requires --enable-monitor on the server). Returns a function that closes it.
+export function openMonitor(
+	token: string,
+	onLine: (line: string) => void,
+	onStop: (reason: string) => void,
+	seconds?: number
+): () => void {
+	const protocol = location.protocol === 'https:' ? 'wss:' : 'ws:';
+	const params = new URLSearchParams({ token });
+	if (seconds !== undefined) params.set('seconds', seconds.toString());
+	const socket = new WebSocket(
+		`${protocol}//${location.host}${BASE_PATH}api/monitor?${params.toString()}`
+	);
+
+	socket.onmessage = (e) => {
+		try {
+			const msg = JSON.parse(e.data);
+			if (msg.type === 'monitor') onLine(msg.data.line);
+			else if (msg.type === 'monitor_stopped') onStop(msg.data.msg);
+		} catch {
+			// Ignore parse errors
+		}
+	};
+	socket.onclose = () => onStop('connection closed');
+
+	return () => socket.close();
+}
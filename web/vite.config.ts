@@ -5,14 +5,16 @@ import { defineConfig } from 'vite';
 
 export default defineConfig({
 	plugins: [svelte(), tailwindcss()],
+	// Relative asset URLs resolve against <base href>, so one build works under any --base-path
+	base: './',
 	build: {
 		outDir: 'dist',
 		emptyOutDir: true,
 		rollupOptions: {
 			output: {
-				entryFileNames: 'assets/[name].js',
-				chunkFileNames: 'assets/[name].js',
-				assetFileNames: 'assets/[name][extname]'
+				entryFileNames: 'assets/[name]-[hash].js',
+				chunkFileNames: 'assets/[name]-[hash].js',
+				assetFileNames: 'assets/[name]-[hash][extname]'
 			}
 		}
 	},